@@ -0,0 +1,117 @@
+package delta
+
+import (
+	"slices"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// AcceptChanges marks the current value as persisted, so that it is no longer reported as a change.
+func (v *LazyScalar[T]) AcceptChanges() {
+	if !v.isDirty {
+		return
+	}
+	v.isDirty = false
+	v.original, v.hasOrig = v.value, true
+	v.syncGauge()
+}
+
+// AcceptChanges marks the pending changes as persisted: added and modified items become unchanged
+// and removed items are dropped.
+func (s *LazySlice[T, I]) AcceptChanges() {
+	s.isReset = false
+	s.beforeReset = nil
+	// not a change of the collection, so it is not recorded in the history
+	s.fetched = filterItems(s.fetched, func(item Item[T, I]) (Item[T, I], bool) {
+		var ok bool
+		item.status, ok = acceptStatus(item.status)
+		return item, ok
+	})
+	s.recount()
+}
+
+// AcceptChanges marks the pending changes as persisted: added and modified keys become unchanged
+// and removed keys are dropped.
+func (d *DynamicFields) AcceptChanges() {
+	d.isReset = false
+	d.beforeReset = nil
+	d.fetched = filterItems(d.fetched, func(item mapItem[any]) (mapItem[any], bool) {
+		var ok bool
+		item.status, ok = acceptStatus(item.status)
+		return item, ok
+	})
+	d.recount()
+}
+
+// AcceptChanges marks the pending changes as persisted: added and modified keys become unchanged
+// and removed keys are dropped.
+func (m *LazyMap[K, V]) AcceptChanges() {
+	m.isReset = false
+	m.beforeReset = nil
+	m.fetched = filterItems(m.fetched, func(item mapItem[V]) (mapItem[V], bool) {
+		var ok bool
+		item.status, ok = acceptStatus(item.status)
+		return item, ok
+	})
+	m.recount()
+}
+
+// AcceptChanges marks the pending changes as persisted: added members become unchanged
+// and removed members are dropped.
+func (s *LazySet[T]) AcceptChanges() {
+	s.isReset = false
+	s.beforeReset = nil
+	s.fetched = filterItems(s.fetched, func(item setItem) (setItem, bool) {
+		var ok bool
+		item.status, ok = acceptStatus(item.status)
+		item.fetched = true
+		return item, ok
+	})
+	s.recount()
+}
+
+// AcceptChanges marks the current reference as persisted, so that it is no longer reported as a change.
+func (r *LazyRef[T, I]) AcceptChanges() {
+	if !r.isDirty {
+		return
+	}
+	var zeroID I
+	r.isSet = true
+	r.origExists = r.exists
+	r.origID = zeroID
+	if r.exists {
+		r.origID = r.value.ID()
+	}
+	r.orig = r.value
+	r.isDirty = false
+	r.syncGauge()
+}
+
+// AcceptChanges marks the current items, and their positions, as persisted.
+func (l *LazyList[T, I]) AcceptChanges() {
+	l.fetched = slices.Clone(l.items)
+	clear(l.set)
+	l.syncGauge()
+}
+
+// acceptStatus returns the status of an item after its changes are persisted, or false if the item must be dropped.
+func acceptStatus(s Status) (Status, bool) {
+	switch s {
+	case Added, Modified:
+		return Unchanged, true
+	case Removed:
+		return s, false
+	}
+	return s, true
+}
+
+// filterItems copies the items, converted by fn, dropping the ones it rejects.
+func filterItems[K comparable, V any](m *linkedmap.Map[K, V], fn func(V) (V, bool)) *linkedmap.Map[K, V] {
+	filtered := linkedmap.New(linkedmap.WithCapacity[K, V](m.Size()))
+	for k, v := range m.Entries() {
+		if v, ok := fn(v); ok {
+			filtered.Put(k, v)
+		}
+	}
+	return filtered
+}
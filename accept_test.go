@@ -0,0 +1,96 @@
+package delta_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyScalar_AcceptChanges(t *testing.T) {
+	scalar := delta.New("John")
+	scalar.Set("Jane")
+	require.NotNil(t, scalar.Change())
+
+	scalar.AcceptChanges()
+	assert.Nil(t, scalar.Change())
+	assert.Equal(t, "Jane", scalar.Get())
+}
+
+func TestLazySlice_AcceptChanges(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	lazySlice.Set(&testEntity{id: "1", name: "entity1_new"})
+	lazySlice.Remove("2")
+	lazySlice.Set(&testEntity{id: "3", name: "entity3"})
+
+	lazySlice.AcceptChanges()
+	changes := lazySlice.Changes()
+	assert.False(t, changes.Reset)
+	assert.Empty(t, slices.Collect(changes.Items))
+
+	lazySlice.Clear()
+	lazySlice.Set(&testEntity{id: "4", name: "entity4"})
+	lazySlice.AcceptChanges()
+	changes = lazySlice.Changes()
+	assert.False(t, changes.Reset)
+	assert.Empty(t, slices.Collect(changes.Items))
+	all, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4"}, ids(slices.Collect(all)))
+}
+
+func TestDynamicFields_AcceptChanges(t *testing.T) {
+	fields, err := delta.NewDynamicFieldsFrom(map[string]any{"color": "red", "size": 42})
+	require.NoError(t, err)
+	require.NoError(t, fields.Set("color", "blue"))
+	fields.Remove("size")
+
+	fields.AcceptChanges()
+	assert.Empty(t, slices.Collect(fields.Changes()))
+	all, err := fields.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"color": "blue"}, maps.Collect(all))
+}
+
+func TestLazyMap_AcceptChanges(t *testing.T) {
+	var calls []string
+	settings := delta.NewLazyMap(mapFetcher(map[string]int{"volume": 5}, &calls))
+	settings.Put("contrast", 50)
+
+	settings.AcceptChanges()
+	assert.Empty(t, slices.Collect(settings.Changes().Items))
+	v, err := settings.Get("contrast")
+	require.NoError(t, err)
+	assert.Equal(t, 50, v)
+	assert.Empty(t, calls)
+}
+
+func TestLazySet_AcceptChanges(t *testing.T) {
+	var calls []string
+	tags := delta.NewLazySet(setFetcher([]string{"go"}, &calls))
+	tags.Add("rust")
+
+	tags.AcceptChanges()
+	assert.Empty(t, setChanges(tags))
+	ok, err := tags.Contains("rust")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestLazyRef_AcceptChanges(t *testing.T) {
+	address := delta.NewRef(&testEntity{id: "1"})
+	address.Set(&testEntity{id: "2"})
+	address.AcceptChanges()
+	assert.Nil(t, address.Change())
+
+	address.Remove()
+	c := address.Change()
+	require.NotNil(t, c)
+	assert.Equal(t, "2", c.OldID)
+}
@@ -0,0 +1,38 @@
+package delta
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrAlreadyExists = errors.New("item already exists")
+
+// Add adds a new item, failing with ErrAlreadyExists if an item with the same ID exists,
+// taking into account the pending changes. Unknown items are checked as in Exists.
+func (s *LazySlice[T, I]) Add(value T) error {
+	if err := s.mut.writable(); err != nil {
+		return err
+	}
+	exists, err := s.Exists(value.ID())
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: %v", ErrAlreadyExists, value.ID())
+	}
+	s.Set(value)
+	return nil
+}
+
+// Update replaces an existing item, failing with ErrNotFound if there is no item with the same ID,
+// taking into account the pending changes. Unknown items are loaded.
+func (s *LazySlice[T, I]) Update(value T) error {
+	if err := s.mut.writable(); err != nil {
+		return err
+	}
+	if _, err := s.Get(value.ID()); err != nil {
+		return err
+	}
+	s.Set(value)
+	return nil
+}
@@ -0,0 +1,48 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazySlice_Add(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{{id: "1", name: "a"}}))
+
+	require.ErrorIs(t, items.Add(&testEntity{id: "1", name: "b"}), delta.ErrAlreadyExists)
+	require.NoError(t, items.Add(&testEntity{id: "2", name: "b"}))
+	require.ErrorIs(t, items.Add(&testEntity{id: "2", name: "c"}), delta.ErrAlreadyExists)
+
+	// a removed item can be added back
+	items.Remove("1")
+	require.NoError(t, items.Add(&testEntity{id: "1", name: "d"}))
+
+	changes := changesByID(items.Changes())
+	assert.Equal(t, delta.Modified, changes["1"].Status)
+	assert.Equal(t, delta.Added, changes["2"].Status)
+}
+
+func TestLazySlice_Update(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{{id: "1", name: "a"}}))
+
+	require.ErrorIs(t, items.Update(&testEntity{id: "2", name: "b"}), delta.ErrNotFound)
+	require.NoError(t, items.Update(&testEntity{id: "1", name: "b"}))
+
+	changes := changesByID(items.Changes())
+	assert.Equal(t, delta.Modified, changes["1"].Status)
+	assert.Equal(t, "a", changes["1"].Old.name)
+	assert.NotContains(t, changes, "2")
+
+	items.Remove("1")
+	require.ErrorIs(t, items.Update(&testEntity{id: "1", name: "c"}), delta.ErrNotFound)
+}
+
+func changesByID(c delta.Changes[*testEntity, string]) map[string]delta.SliceChange[string, *testEntity] {
+	changes := map[string]delta.SliceChange[string, *testEntity]{}
+	for change := range c.Items {
+		changes[change.ID] = change
+	}
+	return changes
+}
@@ -0,0 +1,47 @@
+package delta
+
+// Apply applies the changes to base, returning the resulting items.
+// Base is not modified. Existing items keep their order and added items are appended in the order of the changes.
+// A reset discards the base items, and an added or modified item replaces the existing one with the same ID, if any.
+func Apply[T Identifiable[I], I comparable](base []T, changes Changes[T, I]) []T {
+	var result []T
+	if !changes.Reset {
+		result = make([]T, len(base))
+		copy(result, base)
+	}
+	index := make(map[I]int, len(result))
+	for i, v := range result {
+		index[v.ID()] = i
+	}
+
+	removed := map[I]struct{}{}
+	if changes.Items != nil {
+		for c := range changes.Items {
+			switch c.Status {
+			case Added, Modified:
+				delete(removed, c.ID)
+				if i, ok := index[c.ID]; ok {
+					result[i] = c.Value
+					continue
+				}
+				index[c.ID] = len(result)
+				result = append(result, c.Value)
+			case Removed:
+				if _, ok := index[c.ID]; ok {
+					removed[c.ID] = struct{}{}
+				}
+			}
+		}
+	}
+	if len(removed) == 0 {
+		return result
+	}
+
+	kept := result[:0]
+	for _, v := range result {
+		if _, ok := removed[v.ID()]; !ok {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
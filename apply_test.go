@@ -0,0 +1,33 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	base := []*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+		{id: "3", name: "entity3"},
+	}
+	lazySlice := delta.NewSlice(base)
+	lazySlice.Remove("1")
+	lazySlice.Set(&testEntity{id: "2", name: "entity2_new"})
+	lazySlice.Set(&testEntity{id: "4", name: "entity4"})
+
+	result := delta.Apply(base, lazySlice.Changes())
+	assert.Equal(t, []*testEntity{
+		{id: "2", name: "entity2_new"},
+		{id: "3", name: "entity3"},
+		{id: "4", name: "entity4"},
+	}, result)
+	assert.Equal(t, "entity1", base[0].name)
+	assert.Equal(t, slices.Collect(lazySlice.GetAll()), result)
+
+	lazySlice.SetAll([]*testEntity{{id: "5", name: "entity5"}})
+	assert.Equal(t, []*testEntity{{id: "5", name: "entity5"}}, delta.Apply(base, lazySlice.Changes()))
+}
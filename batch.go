@@ -0,0 +1,110 @@
+package delta
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// BatchLoader batches the loads of the same field of many aggregates (eg: the cars of 50 persons) into a single fetch,
+// like the dataloaders of GraphQL. It is meant to be used within a request scope.
+//
+// The loader of each aggregate is created with Load, which queues its key,
+// and the first one to be called fetches all the queued keys at once.
+type BatchLoader[K comparable, T any] struct {
+	mu      sync.Mutex
+	fn      func(keys []K) (map[K]T, error)
+	pending []K
+	results map[K]batchResult[T] // fetched and not yet delivered
+}
+
+type batchResult[T any] struct {
+	value T
+	found bool
+}
+
+// NewBatchLoader creates a batch loader. The fetch function returns the values per key, omitting the keys not found.
+func NewBatchLoader[K comparable, T any](fn func(keys []K) (map[K]T, error)) *BatchLoader[K, T] {
+	return &BatchLoader[K, T]{
+		fn:      fn,
+		results: map[K]batchResult[T]{},
+	}
+}
+
+// Load queues the key and returns its loader, that returns ErrNotFound if the key was not found.
+func (b *BatchLoader[K, T]) Load(key K) func() (T, error) {
+	b.mu.Lock()
+	b.queue(key)
+	b.mu.Unlock()
+
+	return func() (T, error) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if slices.Contains(b.pending, key) {
+			if err := b.fetch(); err != nil {
+				var zero T
+				return zero, err
+			}
+		}
+		if _, ok := b.results[key]; !ok {
+			// already delivered, eg: reloading
+			b.queue(key)
+			if err := b.fetch(); err != nil {
+				var zero T
+				return zero, err
+			}
+		}
+		r := b.results[key]
+		// results are delivered once, so that a reload fetches fresh values
+		delete(b.results, key)
+		if !r.found {
+			return r.value, ErrNotFound
+		}
+		return r.value, nil
+	}
+}
+
+func (b *BatchLoader[K, T]) queue(key K) {
+	if !slices.Contains(b.pending, key) {
+		b.pending = append(b.pending, key)
+	}
+}
+
+func (b *BatchLoader[K, T]) fetch() error {
+	values, err := b.fn(b.pending)
+	if err != nil {
+		return err
+	}
+	for _, k := range b.pending {
+		v, ok := values[k]
+		b.results[k] = batchResult[T]{value: v, found: ok}
+	}
+	b.pending = nil
+	return nil
+}
+
+// BatchSlice returns the loader of a LazySlice whose items are fetched in batch, per aggregate key.
+// Loading a single item also fetches all the items of the aggregate.
+func BatchSlice[K comparable, T Identifiable[I], I comparable](b *BatchLoader[K, []T], key K) func(I) ([]T, error) {
+	load := b.Load(key)
+	return func(id I) ([]T, error) {
+		values, err := load()
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var zero I
+		if id == zero {
+			return values, nil
+		}
+		for _, v := range values {
+			if v.ID() == id {
+				return []T{v}, nil
+			}
+		}
+		return nil, nil
+	}
+}
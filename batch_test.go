@@ -0,0 +1,62 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchLoader(t *testing.T) {
+	carsByOwner := map[string][]*testEntity{
+		"p1": {{id: "c1", name: "car1"}, {id: "c2", name: "car2"}},
+		"p2": {{id: "c3", name: "car3"}},
+	}
+	var fetches [][]string
+	loader := delta.NewBatchLoader(func(owners []string) (map[string][]*testEntity, error) {
+		fetches = append(fetches, owners)
+		result := map[string][]*testEntity{}
+		for _, o := range owners {
+			if cars, ok := carsByOwner[o]; ok {
+				result[o] = cars
+			}
+		}
+		return result, nil
+	})
+
+	var lazySlices []*delta.LazySlice[*testEntity, string]
+	for _, owner := range []string{"p1", "p2", "p3"} {
+		lazySlices = append(lazySlices, delta.NewLazySlice(delta.BatchSlice(loader, owner)))
+	}
+
+	cars, err := lazySlices[0].GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c1", "c2"}, ids(slices.Collect(cars)))
+	car, err := lazySlices[1].Get("c3")
+	require.NoError(t, err)
+	assert.Equal(t, "car3", car.name)
+	cars, err = lazySlices[2].GetAll()
+	require.NoError(t, err)
+	assert.Empty(t, slices.Collect(cars))
+	assert.Equal(t, [][]string{{"p1", "p2", "p3"}}, fetches)
+
+	// a reload fetches again
+	require.NoError(t, lazySlices[0].Reload())
+	assert.Equal(t, [][]string{{"p1", "p2", "p3"}, {"p1"}}, fetches)
+}
+
+func TestBatchLoader_Ref(t *testing.T) {
+	loader := delta.NewBatchLoader(func(ids []string) (map[string]*testEntity, error) {
+		return map[string]*testEntity{"a1": {id: "a1", name: "address1"}}, nil
+	})
+	address1 := delta.NewLazyRef[*testEntity](loader.Load("a1"))
+	address2 := delta.NewLazyRef[*testEntity](loader.Load("a2"))
+
+	address, err := address1.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "address1", address.name)
+	_, err = address2.Get()
+	require.ErrorIs(t, err, delta.ErrNotFound)
+}
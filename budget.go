@@ -0,0 +1,181 @@
+package delta
+
+import (
+	"sync"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// WithMemoryBudget bounds the memory, in bytes, used by the data loaded into the fields registered in the tracker.
+// When a load exceeds the budget, the least recently used fields are evicted, dropping their unchanged data.
+// Only values that are []byte, string or Sizer are accounted for.
+func WithMemoryBudget(bytes int64) TrackerOption {
+	return trackerOptionFunc(func(t *Tracker) {
+		t.budget = &memoryBudget{
+			limit: bytes,
+			lru:   linkedmap.New[Field, struct{}](),
+		}
+	})
+}
+
+type memoryBudget struct {
+	mu     sync.Mutex // fields can be loaded concurrently by LoadAll
+	limit  int64
+	lru    *linkedmap.Map[Field, struct{}] // least recently used first
+	paused int                             // no eviction while loading concurrently, by LoadAll or Preload
+}
+
+func (b *memoryBudget) add(f Field) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lru.Put(f.base(), struct{}{})
+}
+
+func (b *memoryBudget) remove(f Field) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lru.Delete(f.base())
+}
+
+// touch marks the field as the most recently used.
+func (b *memoryBudget) touch(f Field) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.moveToBack(f.base())
+}
+
+// loaded marks the field as the most recently used and evicts other fields if the budget is exceeded.
+func (b *memoryBudget) loaded(f Field) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	f = f.base()
+	b.moveToBack(f)
+	if b.paused == 0 {
+		b.enforce(f)
+	}
+}
+
+func (b *memoryBudget) pause(paused bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if paused {
+		b.paused++
+	} else {
+		b.paused--
+	}
+}
+
+func (b *memoryBudget) moveToBack(f Field) {
+	b.lru.Delete(f)
+	b.lru.Put(f, struct{}{})
+}
+
+// enforce evicts the least recently used fields, except the one being loaded, until the budget is met.
+// The other fields are only inspected if they are not busy, since they may be loading concurrently.
+func (b *memoryBudget) enforce(except Field) {
+	var total int64
+	for f := range b.lru.Keys() {
+		if f == except {
+			total += int64(f.footprint())
+			continue
+		}
+		if mu := f.locker(); mu.TryLock() {
+			total += int64(f.footprint())
+			mu.Unlock()
+		}
+	}
+	for f := range b.lru.Keys() {
+		if total <= b.limit {
+			return
+		}
+		if f == except {
+			continue
+		}
+		mu := f.locker()
+		if !mu.TryLock() {
+			continue
+		}
+		before := f.footprint()
+		if f.Evict() {
+			total -= int64(before - f.footprint())
+		}
+		mu.Unlock()
+	}
+}
+
+// Footprint returns the estimated size, in bytes, of the tracked data in the registered fields.
+// Only values that are []byte, string or Sizer are accounted for.
+func (t *Tracker) Footprint() int {
+	size := 0
+	for _, f := range t.fields.Entries() {
+		size += f.footprint()
+	}
+	return size
+}
+
+func (v *LazyScalar[T]) footprint() int {
+	if !v.isSet {
+		return 0
+	}
+	return sizeOf(v.value)
+}
+
+func (v *LazyScalar[T]) setBudget(b *memoryBudget) {
+	v.budget = b
+}
+
+func (v *LazyScalar[T]) getBudget() *memoryBudget {
+	return v.budget
+}
+
+func (v *LazyScalar[T]) locker() *sync.Mutex {
+	return &v.mu
+}
+
+func (s *LazySlice[T, I]) footprint() int {
+	size := 0
+	for item := range s.fetched.Values() {
+		if item.status != Removed && item.status != Absent {
+			size += sizeOf(item.value)
+		}
+	}
+	return size
+}
+
+func (s *LazySlice[T, I]) setBudget(b *memoryBudget) {
+	s.budget = b
+}
+
+func (s *LazySlice[T, I]) getBudget() *memoryBudget {
+	return s.budget
+}
+
+func (s *LazySlice[T, I]) locker() *sync.Mutex {
+	return &s.mu
+}
+
+func (d *DynamicFields) setBudget(b *memoryBudget) {
+	d.budget = b
+}
+
+func (d *DynamicFields) getBudget() *memoryBudget {
+	return d.budget
+}
+
+func (d *DynamicFields) locker() *sync.Mutex {
+	return &d.mu
+}
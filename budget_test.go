@@ -0,0 +1,101 @@
+package delta_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_MemoryBudget(t *testing.T) {
+	loads := map[string]int{}
+	lazyOf := func(name string, size int) *delta.LazyScalar[string] {
+		return delta.NewLazy(func() (string, error) {
+			loads[name]++
+			return strings.Repeat("x", size), nil
+		})
+	}
+	a := lazyOf("a", 40)
+	b := lazyOf("b", 40)
+	c := lazyOf("c", 40)
+
+	tracker := delta.NewTracker(delta.WithMemoryBudget(100))
+	tracker.Register("a", a)
+	tracker.Register("b", b)
+	tracker.Register("c", c)
+
+	_, err := a.Get()
+	require.NoError(t, err)
+	_, err = b.Get()
+	require.NoError(t, err)
+	_, err = a.Get() // a is now more recently used than b
+	require.NoError(t, err)
+	assert.Equal(t, 80, tracker.Footprint())
+
+	_, err = c.Get() // exceeds the budget, evicting b
+	require.NoError(t, err)
+	assert.Equal(t, 80, tracker.Footprint())
+
+	_, err = a.Get()
+	require.NoError(t, err)
+	_, err = c.Get()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 1, "c": 1}, loads)
+
+	_, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads["b"])
+}
+
+func TestTracker_MemoryBudget_KeepsChanges(t *testing.T) {
+	a := delta.NewLazy(func() (string, error) {
+		return strings.Repeat("x", 60), nil
+	})
+	b := delta.NewLazy(func() (string, error) {
+		return strings.Repeat("x", 60), nil
+	})
+
+	tracker := delta.NewTracker(delta.WithMemoryBudget(100))
+	tracker.Register("a", a)
+	tracker.Register("b", b)
+
+	a.Set(strings.Repeat("y", 60))
+	_, err := b.Get()
+	require.NoError(t, err)
+
+	// the budget is exceeded but changes are never evicted
+	assert.Equal(t, 120, tracker.Footprint())
+	value, err := a.Get()
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("y", 60), value)
+}
+
+func TestTracker_MemoryBudget_ConcurrentGets(t *testing.T) {
+	tracker := delta.NewTracker(delta.WithMemoryBudget(100))
+	var fields []*delta.LazyScalar[string]
+	for _, name := range []string{"a", "b", "c", "d"} {
+		field := delta.NewLazy(func() (string, error) {
+			return strings.Repeat("x", 40), nil
+		})
+		tracker.Register(name, field)
+		fields = append(fields, field)
+	}
+
+	// every load evicts other fields, that may be loading at the same time
+	var wg sync.WaitGroup
+	for _, field := range fields {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				value, err := field.Get()
+				assert.NoError(t, err)
+				assert.Len(t, value, 40)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,37 @@
+package delta
+
+// SetMany sets the values, as Set, in a single mutation (eg: for import pipelines),
+// journaled as one step and with the same change metadata.
+func (s *LazySlice[T, I]) SetMany(values []T) {
+	if len(values) == 0 {
+		return
+	}
+	s.mut.begin()
+	defer s.mut.end()
+
+	meta := s.mut.meta(s.now())
+	for _, value := range values {
+		s.set(value, meta)
+	}
+	s.mut.log(OpSet, "count", len(values))
+}
+
+// RemoveMany removes the items identified by ids, as Remove, in a single mutation,
+// returning how many of them were cached.
+func (s *LazySlice[T, I]) RemoveMany(ids []I) int {
+	if len(ids) == 0 {
+		return 0
+	}
+	s.mut.begin()
+	defer s.mut.end()
+
+	meta := s.mut.meta(s.now())
+	n := 0
+	for _, id := range ids {
+		if s.remove(id, meta) {
+			n++
+		}
+	}
+	s.mut.log(OpRemove, "count", len(ids))
+	return n
+}
@@ -0,0 +1,37 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetManyRemoveMany(t *testing.T) {
+	items := delta.NewSlice([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	})
+	tracker := delta.NewTracker(delta.WithJournal())
+	tracker.Register("items", items)
+
+	items.SetMany([]*testEntity{
+		{id: "1", name: "changed"},
+		{id: "3", name: "entity3"},
+	})
+	assert.Equal(t, 2, items.RemoveMany([]string{"2", "3", "4"}))
+
+	changes := map[string]delta.Status{}
+	for c := range items.Changes().Items {
+		changes[c.ID] = c.Status
+	}
+	assert.Equal(t, map[string]delta.Status{"1": delta.Modified, "2": delta.Removed, "4": delta.Removed}, changes)
+
+	require.NoError(t, tracker.Undo())
+	assert.Len(t, slices.Collect(items.GetAll()), 3, "undoing RemoveMany restores all the items at once")
+
+	require.NoError(t, tracker.Undo())
+	assert.False(t, items.IsDirty())
+}
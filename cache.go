@@ -0,0 +1,55 @@
+package delta
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Cache is a key value store with expiration (eg: Redis), used by the read-through loaders of CachedLoader and CachedSliceLoader.
+type Cache interface {
+	// Get returns the value of the key, or false if it is missing or expired.
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+}
+
+// CachedLoader decorates a loader so that the value is read from the cache, under key, before calling the loader,
+// and written back with ttl. Values are encoded as JSON.
+// Cache failures are not fatal: on a read failure the loader is called and write failures are ignored.
+func CachedLoader[T any](cache Cache, key string, ttl time.Duration, fn func() (T, error)) func() (T, error) {
+	return func() (T, error) {
+		return readThrough(cache, key, ttl, fn)
+	}
+}
+
+// CachedSliceLoader decorates the loader of a LazySlice, as CachedLoader, caching the items of each ID under prefix
+// followed by the ID, and all the items under prefix followed by "*".
+func CachedSliceLoader[T Identifiable[I], I comparable](cache Cache, prefix string, ttl time.Duration, fn func(I) ([]T, error)) func(I) ([]T, error) {
+	return func(id I) ([]T, error) {
+		var zero I
+		key := prefix + "*"
+		if id != zero {
+			key = prefix + fmt.Sprint(id)
+		}
+		return readThrough(cache, key, ttl, func() ([]T, error) {
+			return fn(id)
+		})
+	}
+}
+
+func readThrough[T any](cache Cache, key string, ttl time.Duration, fn func() (T, error)) (T, error) {
+	if data, ok, err := cache.Get(key); err == nil && ok {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			return value, nil
+		}
+	}
+	value, err := fn()
+	if err != nil {
+		return value, err
+	}
+	if data, err := json.Marshal(value); err == nil {
+		_ = cache.Set(key, data, ttl)
+	}
+	return value, nil
+}
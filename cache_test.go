@@ -0,0 +1,79 @@
+package delta_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cacheEntry struct {
+	value []byte
+	ttl   time.Duration
+}
+
+type memoryCache struct {
+	entries map[string]cacheEntry
+	failGet bool
+}
+
+func (c *memoryCache) Get(key string) ([]byte, bool, error) {
+	if c.failGet {
+		return nil, false, errors.New("unavailable")
+	}
+	e, ok := c.entries[key]
+	return e.value, ok, nil
+}
+
+func (c *memoryCache) Set(key string, value []byte, ttl time.Duration) error {
+	c.entries[key] = cacheEntry{value: value, ttl: ttl}
+	return nil
+}
+
+func TestCachedLoader(t *testing.T) {
+	cache := &memoryCache{entries: map[string]cacheEntry{}}
+	calls := 0
+	load := delta.CachedLoader(cache, "person:1:photo", time.Minute, func() ([]byte, error) {
+		calls++
+		return []byte("photo"), nil
+	})
+
+	photo, err := delta.NewLazy(load).Get()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("photo"), photo)
+	photo, err = delta.NewLazy(load).Get()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("photo"), photo)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, time.Minute, cache.entries["person:1:photo"].ttl)
+
+	// the loader is called when the cache fails
+	cache.failGet = true
+	_, err = delta.NewLazy(load).Get()
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestCachedSliceLoader(t *testing.T) {
+	cache := &memoryCache{entries: map[string]cacheEntry{}}
+	var calls []string
+	load := delta.CachedSliceLoader(cache, "person:1:cars:", time.Minute, func(id string) ([]*jsonEntity, error) {
+		calls = append(calls, id)
+		return []*jsonEntity{{Key: "1", Name: "car1"}}, nil
+	})
+
+	for range 2 {
+		all, err := delta.NewLazySlice(load).GetAll()
+		require.NoError(t, err)
+		assert.Equal(t, []*jsonEntity{{Key: "1", Name: "car1"}}, slices.Collect(all))
+		_, err = delta.NewLazySlice(load).Get("1")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"", "1"}, calls)
+	assert.Contains(t, cache.entries, "person:1:cars:*")
+	assert.Contains(t, cache.entries, "person:1:cars:1")
+}
@@ -0,0 +1,69 @@
+package delta
+
+import (
+	"slices"
+	"time"
+)
+
+// ChangeSet is a set of changes accepted together with AcceptAll.
+type ChangeSet struct {
+	At time.Time
+	// Ops are the operations that reproduced the accepted changes over the previous state, as returned by Pending.
+	Ops Patch
+}
+
+// Fields returns the names of the changed fields, in the order of their operations.
+func (c ChangeSet) Fields() []string {
+	var names []string
+	for _, op := range c.Ops {
+		if !slices.Contains(names, op.Path) {
+			names = append(names, op.Path)
+		}
+	}
+	return names
+}
+
+// WithChangeHistory keeps in memory the last n change sets accepted with AcceptAll, or all of them if n <= 0
+// (eg: to show the recent edits or to compensate them). See History.
+func WithChangeHistory(n int) TrackerOption {
+	return trackerOptionFunc(func(t *Tracker) {
+		t.changeSets = &changeSets{limit: n}
+	})
+}
+
+type changeSets struct {
+	limit int
+	sets  []ChangeSet
+}
+
+func (h *changeSets) add(set ChangeSet) {
+	h.sets = append(h.sets, set)
+	if h.limit > 0 && len(h.sets) > h.limit {
+		h.sets = slices.Delete(h.sets, 0, len(h.sets)-h.limit)
+	}
+}
+
+// History returns the change sets accepted with AcceptAll, oldest first,
+// or nil if the tracker was not created with WithChangeHistory.
+func (t *Tracker) History() []ChangeSet {
+	if t.changeSets == nil {
+		return nil
+	}
+	return slices.Clone(t.changeSets.sets)
+}
+
+// recordChangeSet records the pending changes, before being accepted, if there is a history.
+func (t *Tracker) recordChangeSet() {
+	if t.changeSets == nil {
+		return
+	}
+	ops := t.Pending()
+	if len(ops) == 0 {
+		return
+	}
+	clock := t.clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	t.changeSets.add(ChangeSet{At: clock.Now(), Ops: ops})
+}
@@ -0,0 +1,50 @@
+package delta_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithChangeHistory(t *testing.T) {
+	clock := newFakeClock()
+	name := delta.New("Paulo")
+	items := delta.NewSlice([]*testEntity{{id: "1", name: "entity1"}})
+	tracker := delta.NewTracker(delta.WithClock(clock), delta.WithChangeHistory(2))
+	tracker.Register("name", name)
+	tracker.Register("items", items)
+
+	name.Set("Quintans")
+	tracker.AcceptAll()
+	tracker.AcceptAll() // nothing to record
+
+	clock.Advance(time.Minute)
+	items.Remove("1")
+	tracker.AcceptAll()
+
+	clock.Advance(time.Minute)
+	name.Set("Paulo")
+	items.Set(&testEntity{id: "2"})
+	tracker.AcceptAll()
+
+	history := tracker.History()
+	require.Len(t, history, 2)
+	assert.Equal(t, delta.ChangeSet{
+		At:  clock.Now().Add(-time.Minute),
+		Ops: delta.Patch{{Op: delta.OpRemove, Path: "items", ID: "1"}},
+	}, history[0])
+	assert.Equal(t, clock.Now(), history[1].At)
+	assert.Equal(t, []string{"name", "items"}, history[1].Fields())
+}
+
+func TestTracker_History_Disabled(t *testing.T) {
+	name := delta.New("Paulo")
+	tracker := delta.NewTracker()
+	tracker.Register("name", name)
+	name.Set("Quintans")
+	tracker.AcceptAll()
+	assert.Nil(t, tracker.History())
+}
@@ -0,0 +1,36 @@
+package delta
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithChildDelta sets how the own delta of a modified item of a LazySlice of T is extracted (eg: Car.Delta),
+// to be reported in SliceChange.Delta, so that only the changed columns of the child need to be persisted.
+// It panics when used with a collection of a different type.
+func WithChildDelta[T, D any](extract func(T) D) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optChildDelta
+		o.delta = func(v T) any {
+			return extract(v)
+		}
+	})
+}
+
+func deltaFor[T any](opts options) func(T) any {
+	if opts.delta == nil {
+		return nil
+	}
+	extract, ok := opts.delta.(func(T) any)
+	if !ok {
+		panic(fmt.Sprintf("delta: WithChildDelta extractor %T used with a collection of %s", opts.delta, reflect.TypeFor[T]()))
+	}
+	return extract
+}
+
+// ChildDelta returns the delta of a modified item, as extracted with WithChildDelta,
+// or false if there is none or it is not a D.
+func ChildDelta[D any, I comparable, T any](c SliceChange[I, T]) (D, bool) {
+	d, ok := c.Delta.(D)
+	return d, ok
+}
@@ -0,0 +1,55 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type part struct {
+	id  string
+	qty *delta.Scalar[int]
+}
+
+func (p *part) ID() string {
+	return p.id
+}
+
+func (p *part) HasChanges() bool {
+	return p.qty.IsDirty()
+}
+
+type partDelta struct {
+	Qty *delta.Change[int]
+}
+
+func TestWithChildDelta(t *testing.T) {
+	parts := delta.NewSlice([]*part{
+		{id: "1", qty: delta.New(1)},
+		{id: "2", qty: delta.New(2)},
+	}, delta.WithChildDelta(func(p *part) *partDelta {
+		return &partDelta{Qty: p.qty.Change()}
+	}))
+
+	parts.Get("1").qty.Set(10)
+	parts.Set(&part{id: "3", qty: delta.New(3)})
+
+	var deltas []*partDelta
+	for c := range parts.Changes().Items {
+		d, ok := delta.ChildDelta[*partDelta](c)
+		assert.Equal(t, c.Status == delta.Modified, ok, c.ID)
+		if ok {
+			deltas = append(deltas, d)
+		}
+	}
+	require.Len(t, deltas, 1)
+	assert.Equal(t, 10, deltas[0].Qty.Value)
+}
+
+func TestWithChildDelta_WrongType(t *testing.T) {
+	assert.Panics(t, func() {
+		delta.NewSlice([]*testEntity{}, delta.WithChildDelta(func(p *part) int { return 0 }))
+	})
+}
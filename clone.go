@@ -0,0 +1,135 @@
+package delta
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// Clone returns an independent copy of the scalar, with the same loader and options, and the same value and change state.
+// Values are copied with copyValue, for deep copies, or assigned if it is nil.
+// The copy is not registered in any tracker.
+func (v *LazyScalar[T]) Clone(copyValue func(T) T) *LazyScalar[T] {
+	c := &LazyScalar[T]{}
+	v.cloneInto(c, copyValue)
+	return c
+}
+
+// Clone returns an independent copy of the scalar, as LazyScalar.Clone.
+func (e *Scalar[T]) Clone(copyValue func(T) T) *Scalar[T] {
+	c := &Scalar[T]{}
+	e.cloneInto(&c.LazyScalar, copyValue)
+	return c
+}
+
+func (v *LazyScalar[T]) cloneInto(c *LazyScalar[T], copyValue func(T) T) {
+	if copyValue == nil {
+		copyValue = identity[T]
+	}
+	c.fn = v.fn
+	c.ttl = v.ttl
+	c.equal = v.equal
+	c.clock = v.clock
+	c.errs = v.errs.fresh()
+	c.marshal = v.marshal
+	c.keepHistory = v.keepHistory
+	c.fieldDiff = v.fieldDiff
+
+	c.isSet = v.isSet
+	if v.isSet {
+		c.value = copyValue(v.value)
+	}
+	c.isDirty = v.isDirty
+	if v.hasOrig {
+		c.original = copyValue(v.original)
+	}
+	c.hasOrig = v.hasOrig
+	c.meta = v.meta
+	c.fetchedAt = v.fetchedAt
+	c.history = slices.Clone(v.history)
+}
+
+// Clone returns an independent copy of the collection, with the same loaders and options,
+// and the same items, statuses and reset state.
+// Items are copied with copyValue, for deep copies, or assigned if it is nil.
+// The copy is not registered in any tracker.
+func (s *LazySlice[T, I]) Clone(copyValue func(T) T) *LazySlice[T, I] {
+	c := &LazySlice[T, I]{}
+	s.cloneInto(c, copyValue)
+	return c
+}
+
+// Clone returns an independent copy of the collection, as LazySlice.Clone.
+func (e *Slice[T, I]) Clone(copyValue func(T) T) *Slice[T, I] {
+	c := &Slice[T, I]{}
+	e.cloneInto(&c.LazySlice, copyValue)
+	return c
+}
+
+func (s *LazySlice[T, I]) cloneInto(c *LazySlice[T, I], copyValue func(T) T) {
+	if copyValue == nil {
+		copyValue = identity[T]
+	}
+	c.fn = s.fn
+	c.pager = s.pager
+	c.querier = s.querier
+	c.exister = s.exister
+	c.many = s.many
+	c.stream = s.stream
+	c.errs = s.errs.fresh()
+	c.noAbsent = s.noAbsent
+	c.equal = s.equal
+	c.compare = s.compare
+	c.delta = s.delta
+	c.marshal = s.marshal
+	c.order = s.order
+	c.ttl = s.ttl
+	c.clock = s.clock
+	c.stride = s.stride
+	c.keepHistory = s.keepHistory
+
+	c.isSet = s.isSet
+	c.isReset = s.isReset
+	c.fetched = cloneItems(s.fetched, copyValue)
+	if s.beforeReset != nil {
+		c.beforeReset = cloneItems(s.beforeReset, copyValue)
+	}
+	c.wasSet = s.wasSet
+	c.queries = maps.Clone(s.queries)
+	c.existing = maps.Clone(s.existing)
+	c.indexes = make(map[string]*sliceIndex[T, I], len(s.indexes))
+	for name, x := range s.indexes {
+		c.indexes[name] = &sliceIndex[T, I]{key: x.key, ids: map[any][]I{}}
+	}
+	c.reindex()
+	c.fetchedAt = s.fetchedAt
+	c.loadedAt = s.loadedAt
+	c.history = slices.Clone(s.history)
+}
+
+func cloneItems[T Identifiable[I], I comparable](m *linkedmap.Map[I, Item[T, I]], copyValue func(T) T) *linkedmap.Map[I, Item[T, I]] {
+	c := linkedmap.New(linkedmap.WithCapacity[I, Item[T, I]](m.Size()))
+	for id, item := range m.Entries() {
+		if item.status != Removed && item.status != Absent {
+			item.value = copyValue(item.value)
+		}
+		if item.hasOld {
+			item.old = copyValue(item.old)
+		}
+		c.Put(id, item)
+	}
+	return c
+}
+
+func identity[T any](v T) T {
+	return v
+}
+
+// fresh returns an empty cache with the same ttl.
+func (c *errorCache[K]) fresh() *errorCache[K] {
+	if c == nil {
+		return nil
+	}
+	return newErrorCache[K](c.ttl)
+}
@@ -0,0 +1,72 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func copyEntity(e *testEntity) *testEntity {
+	c := *e
+	return &c
+}
+
+func TestLazyScalar_Clone(t *testing.T) {
+	name := delta.NewLazy(func() (string, error) { return "John", nil })
+	_, err := name.Get()
+	require.NoError(t, err)
+	name.Set("Paulo")
+
+	clone := name.Clone(nil)
+	clone.Set("Quintans")
+	clone.AcceptChanges()
+
+	assert.Equal(t, "Paulo", name.MustGet())
+	assert.True(t, name.IsDirty())
+	name.DiscardChanges()
+	assert.Equal(t, "John", name.MustGet())
+	assert.Equal(t, "Quintans", clone.MustGet())
+	assert.False(t, clone.IsDirty())
+}
+
+func TestLazySlice_Clone(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	_, err := items.GetAll()
+	require.NoError(t, err)
+	items.Set(&testEntity{id: "1", name: "changed"})
+	items.Remove("2")
+
+	clone := items.Clone(copyEntity)
+	assert.Equal(t, slices.Collect(items.Changes().Items), slices.Collect(clone.Changes().Items))
+
+	// changes to the clone, or to its items, do not affect the original
+	item, err := clone.Get("1")
+	require.NoError(t, err)
+	item.name = "changed again"
+	clone.Set(&testEntity{id: "3", name: "entity3"})
+	clone.Clear()
+
+	assert.False(t, items.IsReset())
+	assert.True(t, clone.IsReset())
+	item, err = items.Get("1")
+	require.NoError(t, err)
+	assert.Equal(t, "changed", item.name)
+	_, err = items.Get("3")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+}
+
+func TestSlice_Clone(t *testing.T) {
+	items := delta.NewSlice([]*testEntity{{id: "1", name: "entity1"}})
+	clone := items.Clone(nil)
+	clone.Remove("1")
+
+	assert.False(t, items.IsDirty())
+	assert.True(t, clone.IsDirty())
+	assert.Equal(t, "entity1", items.Get("1").name)
+}
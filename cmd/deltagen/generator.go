@@ -0,0 +1,498 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const (
+	deltaPath       = "github.com/quintans/delta"
+	generatedHeader = "// Code generated by deltagen. DO NOT EDIT."
+)
+
+// pkgFile is a parsed source file of the package.
+type pkgFile struct {
+	file *ast.File
+	fset *token.FileSet
+}
+
+// parsePackage parses the source files of the package in dir, skipping tests and generated files.
+func parsePackage(dir string) ([]pkgFile, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var files []pkgFile
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(src, []byte(generatedHeader)) {
+			continue
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, pkgFile{file: f, fset: fset})
+	}
+	return files, nil
+}
+
+// field is a lazy field of the aggregate.
+type field struct {
+	Name     string // field name
+	Exported string // accessor and delta field name
+	Kind     string // LazyScalar, LazySlice, ...
+	Args     []string
+	Pkg      string // local name of the delta package
+	Equal    string // comparator passed with WithEqual, if any
+	Getter   bool   // generate the getter
+	Setter   bool   // generate the setter
+}
+
+// Change is the type of the changes of the field.
+func (f field) Change() string {
+	switch f.Kind {
+	case "LazyScalar":
+		return fmt.Sprintf("*%s.Change[%s]", f.Pkg, f.Args[0])
+	case "LazyRef":
+		return fmt.Sprintf("*%s.RefChange[%s, %s]", f.Pkg, f.Args[0], f.Args[1])
+	case "LazySlice":
+		return fmt.Sprintf("%s.Changes[%s, %s]", f.Pkg, f.Args[0], f.Args[1])
+	case "LazyMap":
+		return fmt.Sprintf("%s.MapChanges[%s, %s]", f.Pkg, f.Args[0], f.Args[1])
+	default:
+		return fmt.Sprintf("%s.SetChanges[%s]", f.Pkg, f.Args[0])
+	}
+}
+
+// ChangeMethod is the method that returns the changes of the field.
+func (f field) ChangeMethod() string {
+	if f.Kind == "LazyScalar" || f.Kind == "LazyRef" {
+		return "Change"
+	}
+	return "Changes"
+}
+
+// Loader is the type of the loader of the field.
+func (f field) Loader() string {
+	switch f.Kind {
+	case "LazyScalar", "LazyRef":
+		return fmt.Sprintf("func() (%s, error)", f.Args[0])
+	case "LazySlice":
+		return fmt.Sprintf("func(%s) ([]%s, error)", f.Args[1], f.Args[0])
+	case "LazyMap":
+		return fmt.Sprintf("func(%s) (map[%[1]s]%s, error)", f.Args[0], f.Args[1])
+	default:
+		return fmt.Sprintf("func(%s) ([]%[1]s, error)", f.Args[0])
+	}
+}
+
+// Constructor is the constructor of the lazy field.
+func (f field) Constructor() string {
+	switch f.Kind {
+	case "LazyScalar":
+		return f.Pkg + ".NewLazy"
+	case "LazyRef":
+		// the ID type cannot be inferred from the loader
+		return fmt.Sprintf("%s.NewLazyRef[%s]", f.Pkg, strings.Join(f.Args, ", "))
+	}
+	return f.Pkg + ".New" + f.Kind
+}
+
+// Value is the type returned by the getter.
+func (f field) Value() string {
+	switch f.Kind {
+	case "LazyScalar", "LazyRef":
+		return f.Args[0]
+	case "LazyMap":
+		return fmt.Sprintf("map[%s]%s", f.Args[0], f.Args[1])
+	default:
+		return "[]" + f.Args[0]
+	}
+}
+
+// Collect is the function that collects the items returned by GetAll, if any.
+func (f field) Collect() string {
+	switch f.Kind {
+	case "LazySlice", "LazySet":
+		return "slices.Collect"
+	case "LazyMap":
+		return "maps.Collect"
+	}
+	return ""
+}
+
+// HasSetter returns true if the kind has a Set(value) method.
+func (f field) HasSetter() bool {
+	return f.Kind == "LazyScalar" || f.Kind == "LazyRef"
+}
+
+var kinds = []string{"LazyScalar", "LazySlice", "LazyMap", "LazySet", "LazyRef"}
+
+type aggregate struct {
+	Package    string
+	Type       string
+	Receiver   string
+	Delta      string // local name of the delta package
+	StdImports []string
+	Imports    []string
+	Fields     []field
+}
+
+// generate generates the code of the aggregate type of the package.
+func generate(files []pkgFile, typeName string) ([]byte, error) {
+	var (
+		file *pkgFile
+		spec *ast.StructType
+	)
+	for i, f := range files {
+		if st := findStruct(f.file, typeName); st != nil {
+			file, spec = &files[i], st
+			break
+		}
+	}
+	if spec == nil {
+		return nil, fmt.Errorf("struct %s not found", typeName)
+	}
+
+	imports := map[string]string{} // local name -> import spec
+	deltaName := "delta"
+	for _, imp := range file.file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if path == deltaPath {
+			deltaName = name
+			continue
+		}
+		imports[name] = imp.Path.Value
+		if imp.Name != nil {
+			imports[name] = imp.Name.Name + " " + imp.Path.Value
+		}
+	}
+
+	methods := declaredMethods(files, typeName)
+	agg := aggregate{
+		Package:  file.file.Name.Name,
+		Type:     typeName,
+		Receiver: strings.ToLower(typeName[:1]),
+		Delta:    deltaName,
+	}
+	used := map[string]bool{}
+	for _, fl := range spec.Fields.List {
+		kind, args := lazyType(fl.Type, deltaName)
+		if kind == "" {
+			continue
+		}
+		t, err := parseTag(fl.Tag)
+		if err != nil {
+			return nil, err
+		}
+		if t.skip {
+			continue
+		}
+		if t.name != "" && len(fl.Names) > 1 {
+			return nil, fmt.Errorf("delta tag name %q used by many fields", t.name)
+		}
+		var argSrc []string
+		for _, a := range args {
+			argSrc = append(argSrc, nodeString(file.fset, a))
+			for pkg := range packagesOf(a) {
+				used[pkg] = true
+			}
+		}
+		if t.equal != "" {
+			expr, err := parser.ParseExpr(t.equal)
+			if err != nil {
+				return nil, fmt.Errorf("delta tag comparator %q: %w", t.equal, err)
+			}
+			for pkg := range packagesOf(expr) {
+				used[pkg] = true
+			}
+		}
+		for _, n := range fl.Names {
+			exported := upperFirst(n.Name)
+			if t.name != "" {
+				exported = upperFirst(t.name)
+			}
+			agg.Fields = append(agg.Fields, field{
+				Equal:    t.equal,
+				Name:     n.Name,
+				Exported: exported,
+				Kind:     kind,
+				Args:     argSrc,
+				Pkg:      deltaName,
+				Getter:   !n.IsExported() && !methods[exported],
+				Setter:   !n.IsExported() && !methods["Set"+exported],
+			})
+		}
+	}
+	if len(agg.Fields) == 0 {
+		return nil, fmt.Errorf("struct %s has no lazy fields", typeName)
+	}
+
+	deltaImport := strconv.Quote(deltaPath)
+	if deltaName != "delta" {
+		deltaImport = deltaName + " " + deltaImport
+	}
+	agg.Imports = append(agg.Imports, deltaImport)
+	for _, f := range agg.Fields {
+		if f.Getter && f.Collect() != "" {
+			agg.Imports = append(agg.Imports, strconv.Quote(strings.Split(f.Collect(), ".")[0]))
+		}
+	}
+	for pkg := range used {
+		imp, ok := imports[pkg]
+		if !ok {
+			return nil, fmt.Errorf("import of package %s not found", pkg)
+		}
+		agg.Imports = append(agg.Imports, imp)
+	}
+	slices.Sort(agg.Imports)
+	agg.Imports = slices.Compact(agg.Imports)
+	// standard library first
+	var others []string
+	for _, imp := range agg.Imports {
+		if strings.Contains(imp, ".") {
+			others = append(others, imp)
+		} else {
+			agg.StdImports = append(agg.StdImports, imp)
+		}
+	}
+	agg.Imports = others
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, agg); err != nil {
+		return nil, err
+	}
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting the generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// tag is the parsed delta struct tag of a lazy field: `delta:"name,equal=comparator"`.
+// The name "-" skips the field.
+type tag struct {
+	name  string
+	skip  bool
+	equal string
+}
+
+func parseTag(lit *ast.BasicLit) (tag, error) {
+	if lit == nil {
+		return tag{}, nil
+	}
+	raw, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return tag{}, err
+	}
+	value, ok := reflect.StructTag(raw).Lookup("delta")
+	if !ok {
+		return tag{}, nil
+	}
+	name, opts, _ := strings.Cut(value, ",")
+	if name == "-" {
+		return tag{skip: true}, nil
+	}
+	t := tag{name: name}
+	for opt := range strings.SplitSeq(opts, ",") {
+		switch k, v, _ := strings.Cut(opt, "="); k {
+		case "":
+		case "equal":
+			t.equal = v
+		default:
+			return tag{}, fmt.Errorf("unknown delta tag option %q", opt)
+		}
+	}
+	return t, nil
+}
+
+func findStruct(f *ast.File, name string) *ast.StructType {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, s := range gen.Specs {
+			ts := s.(*ast.TypeSpec)
+			if ts.Name.Name != name {
+				continue
+			}
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				return st
+			}
+		}
+	}
+	return nil
+}
+
+// declaredMethods returns the names of the methods of the type declared in the package.
+func declaredMethods(files []pkgFile, typeName string) map[string]bool {
+	methods := map[string]bool{}
+	for _, f := range files {
+		for _, decl := range f.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+				continue
+			}
+			recv := fn.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if id, ok := recv.(*ast.Ident); ok && id.Name == typeName {
+				methods[fn.Name.Name] = true
+			}
+		}
+	}
+	return methods
+}
+
+// lazyType returns the kind and type arguments of a lazy field type, eg: *delta.LazySlice[*Car, uuid.UUID].
+func lazyType(expr ast.Expr, deltaName string) (string, []ast.Expr) {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return "", nil
+	}
+	var (
+		x    ast.Expr
+		args []ast.Expr
+	)
+	switch t := star.X.(type) {
+	case *ast.IndexExpr:
+		x, args = t.X, []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		x, args = t.X, t.Indices
+	default:
+		return "", nil
+	}
+	sel, ok := x.(*ast.SelectorExpr)
+	if !ok {
+		return "", nil
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != deltaName {
+		return "", nil
+	}
+	if !slices.Contains(kinds, sel.Sel.Name) {
+		return "", nil
+	}
+	return sel.Sel.Name, args
+}
+
+// packagesOf returns the packages referenced by the type expression.
+func packagesOf(expr ast.Expr) map[string]bool {
+	pkgs := map[string]bool{}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				pkgs[id.Name] = true
+			}
+			return false
+		}
+		return true
+	})
+	return pkgs
+}
+
+func nodeString(fset *token.FileSet, n ast.Node) string {
+	var buf bytes.Buffer
+	_ = format.Node(&buf, fset, n)
+	return buf.String()
+}
+
+func upperFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+var tmpl = template.Must(template.New("delta").Parse(generatedHeader + `
+
+package {{.Package}}
+
+import (
+{{- range .StdImports}}
+	{{.}}
+{{- end}}
+{{if .StdImports}}
+{{end}}
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+{{$recv := .Receiver}}{{$type := .Type}}
+{{- range .Fields}}
+{{- if .Getter}}
+func ({{$recv}} *{{$type}}) {{.Exported}}() ({{.Value}}, error) {
+{{- if .Collect}}
+	values, err := {{$recv}}.{{.Name}}.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return {{.Collect}}(values), nil
+{{- else}}
+	return {{$recv}}.{{.Name}}.Get()
+{{- end}}
+}
+{{end}}
+{{- if and .Setter .HasSetter}}
+func ({{$recv}} *{{$type}}) Set{{.Exported}}(value {{.Value}}) {
+	{{$recv}}.{{.Name}}.Set(value)
+}
+{{end}}
+{{- end}}
+// {{.Type}}Loaders has the loaders of the lazy fields of {{.Type}}.
+type {{.Type}}Loaders struct {
+{{- range .Fields}}
+	{{.Exported}} {{.Loader}}
+{{- end}}
+}
+
+// wire creates the lazy fields of {{.Type}} from the loaders.
+func ({{$recv}} *{{$type}}) wire(loaders {{.Type}}Loaders, options ...{{.Delta}}.Option) {
+{{- range .Fields}}
+	{{$recv}}.{{.Name}} = {{.Constructor}}(loaders.{{.Exported}},
+	{{- if .Equal}} append([]{{$.Delta}}.Option{ {{$.Delta}}.WithEqual({{.Equal}}) }, options...)...)
+	{{- else}} options...)
+	{{- end}}
+{{- end}}
+}
+
+// {{.Type}}Delta has the changes of the lazy fields of {{.Type}}.
+type {{.Type}}Delta struct {
+{{- range .Fields}}
+	{{.Exported}} {{.Change}}
+{{- end}}
+}
+
+// Delta returns the changes of the lazy fields.
+func ({{$recv}} *{{$type}}) Delta() *{{.Type}}Delta {
+	return &{{.Type}}Delta{
+{{- range .Fields}}
+		{{.Exported}}: {{$recv}}.{{.Name}}.{{.ChangeMethod}}(),
+{{- end}}
+	}
+}
+`))
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	files, err := parsePackage("testdata/person")
+	require.NoError(t, err)
+
+	src, err := generate(files, "Person")
+	require.NoError(t, err)
+	// the golden file is compiled by go vet ./cmd/deltagen/testdata/person
+	golden, err := os.ReadFile("testdata/person/person_delta.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), string(src))
+
+	_, err = generate(files, "Car")
+	require.ErrorContains(t, err, "no lazy fields")
+	_, err = generate(files, "Missing")
+	require.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,57 @@
+// Command deltagen generates the boilerplate of aggregates with lazy fields, eg:
+//
+//	//go:generate go run github.com/quintans/delta/cmd/deltagen -type Person
+//	type Person struct {
+//		id    uuid.UUID
+//		photo *delta.LazyScalar[[]byte]
+//		cars  *delta.LazySlice[*Car, uuid.UUID]
+//	}
+//
+// For each field of type LazyScalar, LazySlice, LazyMap, LazySet or LazyRef it generates:
+//   - the accessors, eg: Photo() ([]byte, error) and SetPhoto([]byte), unless they are already declared
+//   - the loaders struct (PersonLoaders) and the wire method, that creates the lazy fields from the loaders
+//   - the delta struct (PersonDelta) and the Delta method
+//
+// The delta struct tag renames (`delta:"name"`) or skips (`delta:"-"`) a field,
+// and sets the comparator of its lazy field (`delta:",equal=samePhoto"`), that is passed with delta.WithEqual.
+//
+// The code is written to <type>_delta.go, in the current directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of the aggregate types; required")
+	dir := flag.String("dir", ".", "directory of the package")
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	for name := range strings.SplitSeq(*typeNames, ",") {
+		if err := run(*dir, strings.TrimSpace(name)); err != nil {
+			fmt.Fprintln(os.Stderr, "deltagen:", err)
+			os.Exit(1)
+		}
+	}
+}
+
+func run(dir, typeName string) error {
+	pkg, err := parsePackage(dir)
+	if err != nil {
+		return err
+	}
+	src, err := generate(pkg, typeName)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, strings.ToLower(typeName)+"_delta.go"), src, 0o644)
+}
@@ -0,0 +1,42 @@
+package person
+
+import (
+	"github.com/google/uuid"
+	"github.com/quintans/delta"
+)
+
+//go:generate go run github.com/quintans/delta/cmd/deltagen -type Person
+type Person struct {
+	id      uuid.UUID
+	name    string
+	photo   *delta.LazyScalar[[]byte] `delta:",equal=samePhoto"`
+	cars    *delta.LazySlice[*Car, uuid.UUID]
+	tags    *delta.LazySet[string] `delta:"labels"`
+	extras  *delta.LazyMap[string, int]
+	address *delta.LazyRef[*Address, uuid.UUID]
+	cache   *delta.LazyScalar[string] `delta:"-"`
+}
+
+func (p *Person) Photo() ([]byte, error) {
+	return p.photo.Get()
+}
+
+func samePhoto(a, b []byte) bool {
+	return len(a) == len(b)
+}
+
+type Car struct {
+	id uuid.UUID
+}
+
+func (c *Car) ID() uuid.UUID {
+	return c.id
+}
+
+type Address struct {
+	id uuid.UUID
+}
+
+func (a *Address) ID() uuid.UUID {
+	return a.id
+}
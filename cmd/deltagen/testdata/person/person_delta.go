@@ -0,0 +1,85 @@
+// Code generated by deltagen. DO NOT EDIT.
+
+package person
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/quintans/delta"
+)
+
+func (p *Person) SetPhoto(value []byte) {
+	p.photo.Set(value)
+}
+
+func (p *Person) Cars() ([]*Car, error) {
+	values, err := p.cars.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return slices.Collect(values), nil
+}
+
+func (p *Person) Labels() ([]string, error) {
+	values, err := p.tags.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return slices.Collect(values), nil
+}
+
+func (p *Person) Extras() (map[string]int, error) {
+	values, err := p.extras.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return maps.Collect(values), nil
+}
+
+func (p *Person) Address() (*Address, error) {
+	return p.address.Get()
+}
+
+func (p *Person) SetAddress(value *Address) {
+	p.address.Set(value)
+}
+
+// PersonLoaders has the loaders of the lazy fields of Person.
+type PersonLoaders struct {
+	Photo   func() ([]byte, error)
+	Cars    func(uuid.UUID) ([]*Car, error)
+	Labels  func(string) ([]string, error)
+	Extras  func(string) (map[string]int, error)
+	Address func() (*Address, error)
+}
+
+// wire creates the lazy fields of Person from the loaders.
+func (p *Person) wire(loaders PersonLoaders, options ...delta.Option) {
+	p.photo = delta.NewLazy(loaders.Photo, append([]delta.Option{delta.WithEqual(samePhoto)}, options...)...)
+	p.cars = delta.NewLazySlice(loaders.Cars, options...)
+	p.tags = delta.NewLazySet(loaders.Labels, options...)
+	p.extras = delta.NewLazyMap(loaders.Extras, options...)
+	p.address = delta.NewLazyRef[*Address, uuid.UUID](loaders.Address, options...)
+}
+
+// PersonDelta has the changes of the lazy fields of Person.
+type PersonDelta struct {
+	Photo   *delta.Change[[]byte]
+	Cars    delta.Changes[*Car, uuid.UUID]
+	Labels  delta.SetChanges[string]
+	Extras  delta.MapChanges[string, int]
+	Address *delta.RefChange[*Address, uuid.UUID]
+}
+
+// Delta returns the changes of the lazy fields.
+func (p *Person) Delta() *PersonDelta {
+	return &PersonDelta{
+		Photo:   p.photo.Change(),
+		Cars:    p.cars.Changes(),
+		Labels:  p.tags.Changes(),
+		Extras:  p.extras.Changes(),
+		Address: p.address.Change(),
+	}
+}
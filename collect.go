@@ -0,0 +1,93 @@
+package delta
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+var fieldType = reflect.TypeFor[Field]()
+
+// Collect returns the changes of the tracked fields of the aggregate with pending changes, by field name,
+// as Tracker.Changes does. The aggregate must be a pointer to a struct, whose tracked fields may be unexported.
+// The fields of embedded structs are collected as if they were fields of the aggregate.
+//
+// The delta struct tag renames (`delta:"name"`) or skips (`delta:"-"`) a field,
+// and selects a comparator registered with RegisterEqual (`delta:",equal=samePhoto"`).
+func Collect(aggregate any) (map[string]any, error) {
+	rv := reflect.ValueOf(aggregate)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T is not a pointer to a struct", ErrInvalidValue, aggregate)
+	}
+	changes := map[string]any{}
+	if err := collect(rv.Elem(), changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func collect(rv reflect.Value, changes map[string]any) error {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		t, err := parseTag(sf.Tag)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", sf.Name, err)
+		}
+		if t.skip {
+			continue
+		}
+		// unexported fields cannot be accessed through reflection, so they are accessed through their address
+		fv := reflect.NewAt(sf.Type, unsafe.Pointer(rv.Field(i).UnsafeAddr())).Elem()
+		if f, ok := trackedField(fv); ok {
+			var equal func(a, b any) bool
+			if t.equal != "" {
+				if equal, err = comparator(t.equal); err != nil {
+					return fmt.Errorf("field %s: %w", sf.Name, err)
+				}
+			}
+			if !f.IsDirty() {
+				continue
+			}
+			c := f.changes()
+			if s, ok := c.(scalarChange); ok && equal != nil && s.unchanged(equal) {
+				continue
+			}
+			name := sf.Name
+			if t.name != "" {
+				name = t.name
+			}
+			changes[name] = c
+			continue
+		}
+		if !sf.Anonymous {
+			continue
+		}
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct {
+			if err := collect(fv, changes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// trackedField returns the tracked field held by the value, either a pointer or a struct (eg: Scalar[T]).
+func trackedField(fv reflect.Value) (Field, bool) {
+	if fv.Kind() == reflect.Pointer && fv.Type().Implements(fieldType) {
+		if fv.IsNil() {
+			return nil, false
+		}
+		return fv.Interface().(Field), true
+	}
+	if fv.Kind() == reflect.Struct && reflect.PointerTo(fv.Type()).Implements(fieldType) {
+		return fv.Addr().Interface().(Field), true
+	}
+	return nil, false
+}
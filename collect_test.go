@@ -0,0 +1,84 @@
+package delta_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type collectBase struct {
+	version *delta.Scalar[int]
+}
+
+type collectAggregate struct {
+	collectBase
+	id      string
+	name    *delta.Scalar[string]
+	photo   *delta.LazyScalar[[]byte]
+	cars    *delta.LazySlice[*testEntity, string]
+	missing *delta.LazySlice[*testEntity, string]
+}
+
+func TestCollect(t *testing.T) {
+	agg := &collectAggregate{
+		collectBase: collectBase{version: delta.New(1)},
+		id:          "1",
+		name:        delta.New("John"),
+		photo:       delta.NewLazy(func() ([]byte, error) { return []byte("photo"), nil }),
+		cars:        &delta.NewSlice([]*testEntity{{id: "1", name: "car1"}}).LazySlice,
+	}
+
+	changes, err := delta.Collect(agg)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	agg.version.Set(2)
+	agg.name.Set("Jane")
+	agg.cars.Remove("1")
+	changes, err = delta.Collect(agg)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Equal(t, &delta.Change[int]{Value: 2, Old: 1, HasOld: true}, changes["version"])
+	assert.Equal(t, &delta.Change[string]{Value: "Jane", Old: "John", HasOld: true}, changes["name"])
+	cars := slices.Collect(changes["cars"].(delta.Changes[*testEntity, string]).Items)
+	require.Len(t, cars, 1)
+	assert.Equal(t, delta.Removed, cars[0].Status)
+
+	_, err = delta.Collect(agg.id)
+	require.ErrorIs(t, err, delta.ErrInvalidValue)
+}
+
+func TestCollect_Tags(t *testing.T) {
+	delta.RegisterEqual("caseInsensitive", strings.EqualFold)
+	agg := &struct {
+		name     *delta.Scalar[string] `delta:",equal=caseInsensitive"`
+		photo    *delta.Scalar[[]byte] `delta:"picture"`
+		internal *delta.Scalar[int]    `delta:"-"`
+	}{
+		name:     delta.New("John"),
+		photo:    delta.New([]byte("photo")),
+		internal: delta.New(1),
+	}
+	agg.name.Set("JOHN")
+	agg.photo.Set([]byte("new photo"))
+	agg.internal.Set(2)
+
+	changes, err := delta.Collect(agg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"picture": &delta.Change[[]byte]{Value: []byte("new photo"), Old: []byte("photo"), HasOld: true},
+	}, changes)
+
+	_, err = delta.Collect(&struct {
+		name *delta.Scalar[string] `delta:",sorted"`
+	}{name: delta.New("John")})
+	require.ErrorIs(t, err, delta.ErrInvalidValue)
+	_, err = delta.Collect(&struct {
+		name *delta.Scalar[string] `delta:",equal=missing"`
+	}{name: delta.New("John")})
+	require.ErrorIs(t, err, delta.ErrInvalidValue)
+}
@@ -0,0 +1,187 @@
+package delta
+
+import (
+	"errors"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// Command is a reversible mutation over a tracked field.
+// Undo must only be called after a successful Execute.
+type Command interface {
+	Execute() error
+	Undo() error
+}
+
+// fieldCommand is a command over a single field that can be described as an operation.
+type fieldCommand interface {
+	field() Field
+	operation(path string) PatchOp
+}
+
+// ============ Scalar commands ======================
+
+type setCommand[T any] struct {
+	target   *LazyScalar[T]
+	value    T
+	previous any // snapshot of the scalar
+}
+
+// NewSetCommand creates a command that sets the value of a scalar.
+func NewSetCommand[T any](field *LazyScalar[T], value T) Command {
+	return &setCommand[T]{target: field, value: value}
+}
+
+func (c *setCommand[T]) Execute() error {
+	c.previous = c.target.snapshot()
+	c.target.Set(c.value)
+	return nil
+}
+
+func (c *setCommand[T]) Undo() error {
+	c.target.restoreSnapshot(c.previous)
+	return nil
+}
+
+func (c *setCommand[T]) field() Field {
+	return c.target
+}
+
+func (c *setCommand[T]) operation(path string) PatchOp {
+	return PatchOp{Op: OpSet, Path: path, Value: c.value}
+}
+
+// ============ Slice commands ======================
+
+type itemState[T Identifiable[I], I comparable] struct {
+	item   Item[T, I]
+	exists bool
+}
+
+func (s *LazySlice[T, I]) itemState(id I) itemState[T, I] {
+	item, exists := s.fetched.Get(id)
+	return itemState[T, I]{item: item, exists: exists}
+}
+
+func (s *LazySlice[T, I]) restoreItem(id I, state itemState[T, I]) {
+	if state.exists {
+		s.put(id, state.item)
+		return
+	}
+	s.delete(id)
+}
+
+// copyMap copies a linked map.
+// linkedmap.Map.Clone() cannot be used because the clone shares the entries with the original.
+func copyMap[K comparable, V any](m *linkedmap.Map[K, V]) *linkedmap.Map[K, V] {
+	c := linkedmap.New(linkedmap.WithCapacity[K, V](m.Size()))
+	for k, v := range m.Entries() {
+		c.Put(k, v)
+	}
+	return c
+}
+
+type sliceSetCommand[T Identifiable[I], I comparable] struct {
+	target   *LazySlice[T, I]
+	value    T
+	previous itemState[T, I]
+}
+
+// NewSliceSetCommand creates a command that adds or updates an item of a collection.
+func NewSliceSetCommand[T Identifiable[I], I comparable](field *LazySlice[T, I], value T) Command {
+	return &sliceSetCommand[T, I]{target: field, value: value}
+}
+
+func (c *sliceSetCommand[T, I]) Execute() error {
+	c.previous = c.target.itemState(c.value.ID())
+	c.target.Set(c.value)
+	return nil
+}
+
+func (c *sliceSetCommand[T, I]) Undo() error {
+	c.target.restoreItem(c.value.ID(), c.previous)
+	return nil
+}
+
+func (c *sliceSetCommand[T, I]) field() Field {
+	return c.target
+}
+
+func (c *sliceSetCommand[T, I]) operation(path string) PatchOp {
+	return PatchOp{Op: OpSet, Path: path, ID: c.value.ID(), Value: c.value}
+}
+
+type sliceRemoveCommand[T Identifiable[I], I comparable] struct {
+	target   *LazySlice[T, I]
+	id       I
+	previous itemState[T, I]
+}
+
+// NewSliceRemoveCommand creates a command that removes an item from a collection.
+func NewSliceRemoveCommand[T Identifiable[I], I comparable](field *LazySlice[T, I], id I) Command {
+	return &sliceRemoveCommand[T, I]{target: field, id: id}
+}
+
+func (c *sliceRemoveCommand[T, I]) Execute() error {
+	c.previous = c.target.itemState(c.id)
+	c.target.Remove(c.id)
+	return nil
+}
+
+func (c *sliceRemoveCommand[T, I]) Undo() error {
+	c.target.restoreItem(c.id, c.previous)
+	return nil
+}
+
+func (c *sliceRemoveCommand[T, I]) field() Field {
+	return c.target
+}
+
+func (c *sliceRemoveCommand[T, I]) operation(path string) PatchOp {
+	return PatchOp{Op: OpRemove, Path: path, ID: c.id}
+}
+
+type sliceSetAllCommand[T Identifiable[I], I comparable] struct {
+	target   *LazySlice[T, I]
+	values   []T
+	clear    bool
+	previous any // snapshot of the collection
+}
+
+// NewSetAllCommand creates a command that replaces all the items of a collection.
+func NewSetAllCommand[T Identifiable[I], I comparable](field *LazySlice[T, I], values []T) Command {
+	return &sliceSetAllCommand[T, I]{target: field, values: values}
+}
+
+// NewClearCommand creates a command that removes all the items of a collection.
+func NewClearCommand[T Identifiable[I], I comparable](field *LazySlice[T, I]) Command {
+	return &sliceSetAllCommand[T, I]{target: field, clear: true}
+}
+
+func (c *sliceSetAllCommand[T, I]) Execute() error {
+	c.previous = c.target.snapshot()
+	if c.clear {
+		c.target.Clear()
+		return nil
+	}
+	c.target.SetAll(c.values)
+	return nil
+}
+
+func (c *sliceSetAllCommand[T, I]) Undo() error {
+	c.target.restoreSnapshot(c.previous)
+	return nil
+}
+
+func (c *sliceSetAllCommand[T, I]) field() Field {
+	return c.target
+}
+
+func (c *sliceSetAllCommand[T, I]) operation(path string) PatchOp {
+	if c.clear {
+		return PatchOp{Op: OpClear, Path: path}
+	}
+	return PatchOp{Op: OpSetAll, Path: path, Value: c.values}
+}
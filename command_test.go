@@ -0,0 +1,189 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_ExecuteUndo_Scalar(t *testing.T) {
+	name := delta.New("john")
+	tracker := delta.NewTracker()
+
+	require.NoError(t, tracker.Execute(delta.NewSetCommand(&name.LazyScalar, "jane")))
+	require.NoError(t, tracker.Execute(delta.NewSetCommand(&name.LazyScalar, "mary")))
+	assert.Equal(t, "mary", name.Get())
+	assert.Len(t, tracker.Commands(), 2)
+
+	require.NoError(t, tracker.Undo())
+	assert.Equal(t, "jane", name.Get())
+	require.NotNil(t, name.Change())
+
+	require.NoError(t, tracker.Undo())
+	assert.Equal(t, "john", name.Get())
+	assert.Nil(t, name.Change())
+
+	require.ErrorIs(t, tracker.Undo(), delta.ErrNothingToUndo)
+}
+
+func TestTracker_ExecuteUndo_Slice(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	tracker := delta.NewTracker()
+
+	require.NoError(t, tracker.Execute(delta.NewSliceSetCommand(lazySlice, &testEntity{id: "3", name: "entity3"})))
+	require.NoError(t, tracker.Execute(delta.NewSliceRemoveCommand(lazySlice, "1")))
+	require.NoError(t, tracker.Execute(delta.NewSliceSetCommand(lazySlice, &testEntity{id: "3", name: "entity3_new"})))
+
+	changes := slices.Collect(lazySlice.Changes().Items)
+	require.Len(t, changes, 2)
+
+	require.NoError(t, tracker.Undo())
+	result, err := lazySlice.Get("3")
+	require.NoError(t, err)
+	assert.Equal(t, "entity3", result.name)
+
+	require.NoError(t, tracker.Undo())
+	require.NoError(t, tracker.Undo())
+	assert.Empty(t, slices.Collect(lazySlice.Changes().Items))
+
+	seq, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, slices.Collect(seq), 2)
+}
+
+func TestTracker_ExecuteUndo_SetAll(t *testing.T) {
+	entities := delta.NewSlice([]*testEntity{
+		{id: "1", name: "entity1"},
+	})
+	tracker := delta.NewTracker()
+
+	require.NoError(t, tracker.Execute(delta.NewSetAllCommand(&entities.LazySlice, []*testEntity{{id: "2", name: "entity2"}})))
+	require.NoError(t, tracker.Execute(delta.NewClearCommand(&entities.LazySlice)))
+	assert.Empty(t, slices.Collect(entities.GetAll()))
+
+	require.NoError(t, tracker.Undo())
+	result := slices.Collect(entities.GetAll())
+	require.Len(t, result, 1)
+	assert.Equal(t, "2", result[0].id)
+	assert.True(t, entities.IsReset())
+
+	require.NoError(t, tracker.Undo())
+	result = slices.Collect(entities.GetAll())
+	require.Len(t, result, 1)
+	assert.Equal(t, "1", result[0].id)
+	assert.False(t, entities.IsReset())
+}
+
+func TestTracker_OperationLog(t *testing.T) {
+	name := delta.New("john")
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	tracker := delta.NewTracker(delta.WithOperationLog())
+	tracker.Register("name", name)
+	tracker.Register("items", lazySlice)
+
+	require.NoError(t, tracker.Execute(delta.NewSetCommand(&name.LazyScalar, "jane")))
+	require.NoError(t, tracker.Execute(delta.NewSetCommand(&name.LazyScalar, "mary")))
+	require.NoError(t, tracker.Execute(delta.NewSliceSetCommand(lazySlice, &testEntity{id: "3", name: "entity3"})))
+	require.NoError(t, tracker.Execute(delta.NewSliceSetCommand(lazySlice, &testEntity{id: "3", name: "entity3_new"})))
+	require.NoError(t, tracker.Execute(delta.NewSliceSetCommand(lazySlice, &testEntity{id: "4", name: "entity4"})))
+	require.NoError(t, tracker.Execute(delta.NewSliceRemoveCommand(lazySlice, "4")))
+	require.NoError(t, delta.ApplyPatch(tracker, delta.Patch{{Op: delta.OpRemove, Path: "items", ID: "1"}}))
+
+	ops := tracker.Operations()
+	require.Len(t, ops, 7)
+	assert.Equal(t, delta.PatchOp{Op: delta.OpSet, Path: "name", Value: "jane"}, ops[0])
+	assert.Equal(t, delta.PatchOp{Op: delta.OpRemove, Path: "items", ID: "1"}, ops[6])
+
+	compacted := tracker.Compact()
+	require.Len(t, compacted, 3)
+	assert.Equal(t, delta.PatchOp{Op: delta.OpSet, Path: "name", Value: "mary"}, compacted[0])
+	assert.Equal(t, delta.OpSet, compacted[1].Op)
+	assert.Equal(t, "items", compacted[1].Path)
+	assert.Equal(t, "3", compacted[1].ID)
+	assert.Equal(t, "entity3_new", compacted[1].Value.(*testEntity).name)
+	assert.Equal(t, delta.PatchOp{Op: delta.OpRemove, Path: "items", ID: "1"}, compacted[2])
+	assert.Equal(t, compacted, tracker.Operations())
+
+	// replaying the compacted log produces the same changes
+	other := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	otherTracker := delta.NewTracker()
+	otherTracker.Register("name", delta.New("john"))
+	otherTracker.Register("items", other)
+	require.NoError(t, delta.ApplyPatch(otherTracker, compacted))
+	assert.Equal(t, slices.Collect(lazySlice.Changes().Items), slices.Collect(other.Changes().Items))
+}
+
+func TestTracker_OperationLog_Undo(t *testing.T) {
+	name := delta.New("john")
+	lazySlice := delta.NewSlice([]*testEntity{{id: "1", name: "entity1"}})
+	tracker := delta.NewTracker(delta.WithOperationLog())
+	tracker.Register("name", name)
+	tracker.Register("items", lazySlice)
+
+	require.NoError(t, tracker.Execute(delta.NewSetCommand(&name.LazyScalar, "jane")))
+	require.NoError(t, tracker.Execute(delta.NewSliceRemoveCommand(&lazySlice.LazySlice, "1")))
+	require.NoError(t, delta.ApplyPatch(tracker, delta.Patch{{Op: delta.OpSet, Path: "name", Value: "mary"}}))
+	require.Len(t, tracker.Operations(), 3)
+
+	// the operation of the undone command is dropped, even if it is not the last one
+	require.NoError(t, tracker.Undo())
+	assert.Equal(t, delta.Patch{
+		{Op: delta.OpSet, Path: "name", Value: "jane"},
+		{Op: delta.OpSet, Path: "name", Value: "mary"},
+	}, tracker.Operations())
+
+	require.NoError(t, tracker.Undo())
+	assert.Equal(t, delta.Patch{{Op: delta.OpSet, Path: "name", Value: "mary"}}, tracker.Operations())
+}
+
+func TestTracker_OperationLog_AcceptAll(t *testing.T) {
+	name := delta.New("john")
+	tracker := delta.NewTracker(delta.WithOperationLog())
+	tracker.Register("name", name)
+
+	require.NoError(t, tracker.Execute(delta.NewSetCommand(&name.LazyScalar, "jane")))
+	tracker.AcceptAll()
+	assert.Empty(t, tracker.Operations())
+
+	// commands executed before accepting have no operation to drop
+	require.NoError(t, tracker.Execute(delta.NewSetCommand(&name.LazyScalar, "mary")))
+	require.NoError(t, tracker.Undo())
+	assert.Empty(t, tracker.Operations())
+	require.NoError(t, tracker.Undo())
+	assert.Empty(t, tracker.Operations())
+}
+
+func TestTracker_ExecuteUndo_RestoresChangeState(t *testing.T) {
+	actor := "alice"
+	name := delta.New("john")
+	entities := delta.NewSlice([]*testEntity{{id: "1", name: "entity1"}})
+	tracker := delta.NewTracker(delta.WithActor(func() (string, string) { return actor, "" }))
+	tracker.Register("name", name)
+	tracker.Register("entities", entities)
+
+	require.NoError(t, tracker.Execute(delta.NewSetCommand(&name.LazyScalar, "jane")))
+	actor = "bob"
+	require.NoError(t, tracker.Execute(delta.NewSetCommand(&name.LazyScalar, "mary")))
+	require.NoError(t, tracker.Undo())
+	assert.Equal(t, "alice", name.Change().Meta.Actor)
+
+	require.NoError(t, tracker.Execute(delta.NewClearCommand(&entities.LazySlice)))
+	require.NoError(t, tracker.Undo())
+	assert.False(t, entities.IsReset())
+	entities.DiscardChanges()
+	result := slices.Collect(entities.GetAll())
+	require.Len(t, result, 1, "the undone reset is not discarded again")
+	assert.Equal(t, "1", result[0].id)
+}
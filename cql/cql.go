@@ -0,0 +1,206 @@
+// Package cql generates Cassandra CQL partial updates from deltas, so that only the changed columns are written.
+package cql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quintans/delta"
+)
+
+// Column is a named column value.
+type Column struct {
+	Name  string
+	Value any
+}
+
+// Row identifies the row to update.
+type Row struct {
+	Table      string // eg: keyspace.table
+	Partition  []Column
+	Clustering []Column
+}
+
+func (r Row) where() (string, []any) {
+	keys := append(append([]Column{}, r.Partition...), r.Clustering...)
+	conds := make([]string, len(keys))
+	args := make([]any, len(keys))
+	for i, k := range keys {
+		conds[i] = k.Name + " = ?"
+		args[i] = k.Value
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func (r Row) partition() string {
+	var sb strings.Builder
+	sb.WriteString(r.Table)
+	for _, k := range r.Partition {
+		fmt.Fprintf(&sb, "|%v", k.Value)
+	}
+	return sb.String()
+}
+
+// Statement is a CQL statement with its bind arguments.
+type Statement struct {
+	Query     string
+	Args      []any
+	partition string
+}
+
+// Batch groups the statements of a single partition.
+type Batch struct {
+	Statements []Statement
+}
+
+// Query returns the statements wrapped in a logged batch.
+func (b Batch) Query() string {
+	var sb strings.Builder
+	sb.WriteString("BEGIN BATCH\n")
+	for _, s := range b.Statements {
+		sb.WriteString(s.Query)
+		sb.WriteString(";\n")
+	}
+	sb.WriteString("APPLY BATCH")
+	return sb.String()
+}
+
+// Args returns the bind arguments of all the statements, in order.
+func (b Batch) Args() []any {
+	var args []any
+	for _, s := range b.Statements {
+		args = append(args, s.Args...)
+	}
+	return args
+}
+
+// Generator collects the statements that persist the changes of one or more rows.
+type Generator struct {
+	statements []Statement
+}
+
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Statements returns the collected statements, in order.
+func (g *Generator) Statements() []Statement {
+	return append([]Statement(nil), g.statements...)
+}
+
+// Batches returns the collected statements grouped per partition, in order of first appearance.
+func (g *Generator) Batches() []Batch {
+	var batches []Batch
+	index := map[string]int{}
+	for _, s := range g.statements {
+		i, ok := index[s.partition]
+		if !ok {
+			i = len(batches)
+			index[s.partition] = i
+			batches = append(batches, Batch{})
+		}
+		batches[i].Statements = append(batches[i].Statements, s)
+	}
+	return batches
+}
+
+func (g *Generator) update(row Row, assignment string, args ...any) {
+	where, keys := row.where()
+	g.statements = append(g.statements, Statement{
+		Query:     fmt.Sprintf("UPDATE %s SET %s WHERE %s", row.Table, assignment, where),
+		Args:      append(args, keys...),
+		partition: row.partition(),
+	})
+}
+
+func (g *Generator) delete(row Row, selection string, args ...any) {
+	where, keys := row.where()
+	g.statements = append(g.statements, Statement{
+		Query:     fmt.Sprintf("DELETE %s FROM %s WHERE %s", selection, row.Table, where),
+		Args:      append(args, keys...),
+		partition: row.partition(),
+	})
+}
+
+// Scalar adds the update of a column, if it changed. A Null change binds nil, to write NULL.
+func Scalar[T any](g *Generator, row Row, column string, change *delta.Change[T]) {
+	if change == nil {
+		return
+	}
+	if change.Null {
+		g.update(row, column+" = ?", nil)
+		return
+	}
+	g.update(row, column+" = ?", change.Value)
+}
+
+// Set adds the updates of a set column, converting each item to its set element.
+// Added items are appended and removed items are removed.
+// Since modified items cannot be matched by value, a reset or a modification rewrites the whole set,
+// and so does the removal of an item that was not fetched, since its element cannot be built.
+func Set[T delta.Identifiable[I], I comparable](g *Generator, row Row, column string, s *delta.LazySlice[T, I], element func(id I, value T) any) error {
+	changes := s.Changes()
+	var added, removed []any
+	rewrite := changes.Reset
+	for c := range changes.Items {
+		switch c.Status {
+		case delta.Added:
+			added = append(added, element(c.ID, c.Value))
+		case delta.Removed:
+			if !c.HasOld {
+				rewrite = true
+				continue
+			}
+			removed = append(removed, element(c.ID, c.Old))
+		case delta.Modified:
+			rewrite = true
+		}
+	}
+
+	if rewrite {
+		all, err := s.GetAll()
+		if err != nil {
+			return err
+		}
+		elems := []any{}
+		for v := range all {
+			elems = append(elems, element(v.ID(), v))
+		}
+		g.update(row, column+" = ?", elems)
+		return nil
+	}
+	if len(added) > 0 {
+		g.update(row, fmt.Sprintf("%s = %s + ?", column, column), added)
+	}
+	if len(removed) > 0 {
+		g.update(row, fmt.Sprintf("%s = %s - ?", column, column), removed)
+	}
+	return nil
+}
+
+// Map adds the updates of a map column keyed by the item ID, converting each item to its map value.
+// Added and modified items are put, removed items are deleted and a reset rewrites the whole map.
+func Map[T delta.Identifiable[I], I comparable](g *Generator, row Row, column string, s *delta.LazySlice[T, I], value func(T) any) error {
+	changes := s.Changes()
+	if changes.Reset {
+		all, err := s.GetAll()
+		if err != nil {
+			return err
+		}
+		m := map[I]any{}
+		for v := range all {
+			m[v.ID()] = value(v)
+		}
+		g.update(row, column+" = ?", m)
+		return nil
+	}
+	for c := range changes.Items {
+		switch c.Status {
+		case delta.Added, delta.Modified:
+			g.update(row, column+"[?] = ?", c.ID, value(c.Value))
+		case delta.Removed:
+			g.delete(row, column+"[?]", c.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package cql_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/quintans/delta/cql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type car struct {
+	id   string
+	make string
+}
+
+func (c *car) ID() string {
+	return c.id
+}
+
+func personRow(id string) cql.Row {
+	return cql.Row{
+		Table:     "ks.person",
+		Partition: []cql.Column{{Name: "id", Value: id}},
+	}
+}
+
+func TestScalar(t *testing.T) {
+	name := delta.New("John")
+	age := delta.New(30)
+	name.Set("Jane")
+
+	g := cql.NewGenerator()
+	cql.Scalar(g, personRow("1"), "name", name.Change())
+	cql.Scalar(g, personRow("1"), "age", age.Change())
+
+	assert.Equal(t, []cql.Statement{{
+		Query: "UPDATE ks.person SET name = ? WHERE id = ?",
+		Args:  []any{"Jane", "1"},
+	}}, stripped(g.Statements()))
+}
+
+func TestSet(t *testing.T) {
+	cars := delta.NewSlice([]*car{{id: "1"}, {id: "2"}})
+	cars.Remove("1")
+	cars.Set(&car{id: "3"})
+
+	g := cql.NewGenerator()
+	err := cql.Set(g, personRow("1"), "cars", &cars.LazySlice, func(id string, _ *car) any { return id })
+	require.NoError(t, err)
+	assert.Equal(t, []cql.Statement{
+		{Query: "UPDATE ks.person SET cars = cars + ? WHERE id = ?", Args: []any{[]any{"3"}, "1"}},
+		{Query: "UPDATE ks.person SET cars = cars - ? WHERE id = ?", Args: []any{[]any{"1"}, "1"}},
+	}, stripped(g.Statements()))
+
+	cars.Clear()
+	g = cql.NewGenerator()
+	err = cql.Set(g, personRow("1"), "cars", &cars.LazySlice, func(id string, _ *car) any { return id })
+	require.NoError(t, err)
+	assert.Equal(t, []cql.Statement{
+		{Query: "UPDATE ks.person SET cars = ? WHERE id = ?", Args: []any{[]any{}, "1"}},
+	}, stripped(g.Statements()))
+}
+
+func TestMap(t *testing.T) {
+	cars := delta.NewSlice([]*car{{id: "1", make: "Ford"}, {id: "2", make: "Fiat"}})
+	cars.Set(&car{id: "1", make: "Audi"})
+	cars.Remove("2")
+
+	row := cql.Row{
+		Table:      "ks.garage",
+		Partition:  []cql.Column{{Name: "city", Value: "Lisbon"}},
+		Clustering: []cql.Column{{Name: "owner", Value: "1"}},
+	}
+	g := cql.NewGenerator()
+	err := cql.Map(g, row, "cars", &cars.LazySlice, func(c *car) any { return c.make })
+	require.NoError(t, err)
+	assert.Equal(t, []cql.Statement{
+		{Query: "UPDATE ks.garage SET cars[?] = ? WHERE city = ? AND owner = ?", Args: []any{"1", "Audi", "Lisbon", "1"}},
+		{Query: "DELETE cars[?] FROM ks.garage WHERE city = ? AND owner = ?", Args: []any{"2", "Lisbon", "1"}},
+	}, stripped(g.Statements()))
+}
+
+func TestGenerator_Batches(t *testing.T) {
+	john := delta.New("John")
+	john.Set("Johnny")
+	jane := delta.New("Jane")
+	jane.Set("Janet")
+	age := delta.New(30)
+	age.Set(31)
+
+	g := cql.NewGenerator()
+	cql.Scalar(g, personRow("1"), "name", john.Change())
+	cql.Scalar(g, personRow("2"), "name", jane.Change())
+	cql.Scalar(g, personRow("1"), "age", age.Change())
+
+	batches := g.Batches()
+	require.Len(t, batches, 2)
+	assert.Equal(t, "BEGIN BATCH\n"+
+		"UPDATE ks.person SET name = ? WHERE id = ?;\n"+
+		"UPDATE ks.person SET age = ? WHERE id = ?;\n"+
+		"APPLY BATCH", batches[0].Query())
+	assert.Equal(t, []any{"Johnny", "1", 31, "1"}, batches[0].Args())
+	assert.Equal(t, []any{"Janet", "2"}, batches[1].Args())
+}
+
+// stripped drops the unexported fields, for comparison.
+func stripped(statements []cql.Statement) []cql.Statement {
+	result := make([]cql.Statement, len(statements))
+	for i, s := range statements {
+		result[i] = cql.Statement{Query: s.Query, Args: s.Args}
+	}
+	return result
+}
+
+func TestScalar_Null(t *testing.T) {
+	nickname := delta.New[*string](new(string))
+	nickname.SetNull()
+
+	g := cql.NewGenerator()
+	cql.Scalar(g, personRow("1"), "nickname", nickname.Change())
+
+	assert.Equal(t, []cql.Statement{{
+		Query: "UPDATE ks.person SET nickname = ? WHERE id = ?",
+		Args:  []any{nil, "1"},
+	}}, stripped(g.Statements()))
+}
+
+func TestSet_RemovedUnfetched(t *testing.T) {
+	cars := delta.NewLazySlice(func(id string) ([]*car, error) {
+		return []*car{{id: "1", make: "Ford"}, {id: "2", make: "Fiat"}}, nil
+	})
+	cars.Remove("1")
+
+	g := cql.NewGenerator()
+	err := cql.Set(g, personRow("1"), "cars", cars, func(_ string, c *car) any { return c.make })
+	require.NoError(t, err)
+	assert.Equal(t, []cql.Statement{
+		{Query: "UPDATE ks.person SET cars = ? WHERE id = ?", Args: []any{[]any{"Fiat"}, "1"}},
+	}, stripped(g.Statements()))
+}
@@ -0,0 +1,232 @@
+// Package deltasql generates SQL statements from deltas, so that only the changed columns and rows are written.
+package deltasql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/quintans/delta"
+)
+
+var ErrNotScalar = errors.New("not a scalar change")
+
+// Column is a named column value.
+type Column struct {
+	Name  string
+	Value any
+}
+
+// Statement is a SQL statement with its bind arguments.
+type Statement struct {
+	Query        string
+	Args         []any
+	ExpectedRows int64 // rows that must be affected, if not zero (see Exec)
+}
+
+// Placeholder returns the bind placeholder of the nth argument, starting at 1.
+type Placeholder func(n int) string
+
+// Question is the placeholder of MySQL and SQLite: ?
+func Question(int) string {
+	return "?"
+}
+
+// Dollar is the placeholder of PostgreSQL: $1, $2, ...
+func Dollar(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+type Option func(*Generator)
+
+// WithPlaceholder sets the placeholder of the bind arguments. Defaults to Question.
+func WithPlaceholder(p Placeholder) Option {
+	return func(g *Generator) {
+		g.placeholder = p
+	}
+}
+
+// Generator collects the statements that persist the changes of one or more aggregates.
+type Generator struct {
+	placeholder Placeholder
+	statements  []Statement
+}
+
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{placeholder: Question}
+	for _, opt := range options {
+		opt(g)
+	}
+	return g
+}
+
+// Statements returns the collected statements, in order.
+func (g *Generator) Statements() []Statement {
+	return slices.Clone(g.statements)
+}
+
+// add adds a statement whose bind arguments are written as ?.
+func (g *Generator) add(query string, args ...any) {
+	g.addExpecting(0, query, args...)
+}
+
+func (g *Generator) addExpecting(rows int64, query string, args ...any) {
+	var sb strings.Builder
+	n := 0
+	for part := range strings.SplitSeq(query, "?") {
+		if n > 0 {
+			sb.WriteString(g.placeholder(n))
+		}
+		sb.WriteString(part)
+		n++
+	}
+	g.statements = append(g.statements, Statement{Query: sb.String(), Args: args, ExpectedRows: rows})
+}
+
+func where(keys []Column) (string, []any) {
+	conds := make([]string, len(keys))
+	args := make([]any, len(keys))
+	for i, k := range keys {
+		conds[i] = k.Name + " = ?"
+		args[i] = k.Value
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func assignments(cols []Column) (string, []any) {
+	sets := make([]string, len(cols))
+	args := make([]any, len(cols))
+	for i, c := range cols {
+		sets[i] = c.Name + " = ?"
+		args[i] = c.Value
+	}
+	return strings.Join(sets, ", "), args
+}
+
+// Update adds the update of the changed columns of a row, if any changed.
+// The changes are the scalar changes by field name (eg: from Tracker.Changes or Collect)
+// and columns maps the field names to their columns. Fields without a column are ignored.
+// The columns are set in alphabetical order.
+func Update(g *Generator, table string, key []Column, columns map[string]string, changes map[string]any) error {
+	var cols []Column
+	for field, change := range changes {
+		name, ok := columns[field]
+		if !ok {
+			continue
+		}
+		value, ok := scalarValue(change)
+		if !ok {
+			return fmt.Errorf("%w: field %q has changes %T", ErrNotScalar, field, change)
+		}
+		cols = append(cols, Column{Name: name, Value: value})
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+	slices.SortFunc(cols, func(a, b Column) int { return strings.Compare(a.Name, b.Name) })
+	set, args := assignments(cols)
+	cond, keys := where(key)
+	g.add(fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, set, cond), append(args, keys...)...)
+	return nil
+}
+
+// scalarChange is implemented by *delta.Change[T].
+type scalarChange interface {
+	AnyValue() any
+}
+
+// scalarValue returns the value of a *delta.Change[T].
+func scalarValue(change any) (any, bool) {
+	c, ok := change.(scalarChange)
+	if !ok || reflect.ValueOf(c).IsNil() {
+		return nil, false
+	}
+	return c.AnyValue(), true
+}
+
+// Table describes the table of the items of a child collection.
+type Table struct {
+	Name   string
+	ID     string   // column of the item ID
+	Parent []Column // columns referencing the parent, written on insert and scoping the delete of a reset
+}
+
+// Rows adds the statements of the changed items of a child collection:
+// a single DELETE of the removed items, an INSERT of the added items and an UPDATE per modified item.
+// Deleting first allows an item to be removed and added again with the same ID.
+// Added items with different columns (eg: columns omitted when empty) are inserted by one INSERT per set of columns.
+// Modified items without columns other than the ID are not updated.
+// A reset deletes all the rows of the parent before inserting the items.
+// columns returns the columns of an item, including its ID, without the parent columns.
+func Rows[T delta.Identifiable[I], I comparable](g *Generator, table Table, changes delta.Changes[T, I], columns func(T) []Column) {
+	if changes.Reset {
+		if len(table.Parent) == 0 {
+			g.add("DELETE FROM " + table.Name)
+		} else {
+			cond, args := where(table.Parent)
+			g.add(fmt.Sprintf("DELETE FROM %s WHERE %s", table.Name, cond), args...)
+		}
+	}
+	var added []T
+	var removed []any
+	var modified []T
+	for c := range changes.Items {
+		switch c.Status {
+		case delta.Added:
+			added = append(added, c.Value)
+		case delta.Removed:
+			removed = append(removed, c.ID)
+		case delta.Modified:
+			modified = append(modified, c.Value)
+		}
+	}
+
+	if len(removed) > 0 {
+		in := strings.TrimSuffix(strings.Repeat("?, ", len(removed)), ", ")
+		g.add(fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", table.Name, table.ID, in), removed...)
+	}
+	insertRows(g, table, added, columns)
+	for _, v := range modified {
+		cols := slices.DeleteFunc(columns(v), func(c Column) bool { return c.Name == table.ID })
+		if len(cols) == 0 {
+			continue
+		}
+		set, args := assignments(cols)
+		g.add(fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", table.Name, set, table.ID), append(args, v.ID())...)
+	}
+}
+
+// insertRows adds an INSERT per set of column names of the added items, in the order the sets are first seen.
+func insertRows[T any](g *Generator, table Table, added []T, columns func(T) []Column) {
+	type insert struct {
+		names []string
+		rows  []string
+		args  []any
+	}
+	var inserts []*insert
+	byNames := map[string]*insert{}
+	for _, v := range added {
+		cols := append(slices.Clone(table.Parent), columns(v)...)
+		names := make([]string, len(cols))
+		for i, c := range cols {
+			names[i] = c.Name
+		}
+		key := strings.Join(names, ", ")
+		ins, ok := byNames[key]
+		if !ok {
+			ins = &insert{names: names}
+			byNames[key] = ins
+			inserts = append(inserts, ins)
+		}
+		for _, c := range cols {
+			ins.args = append(ins.args, c.Value)
+		}
+		ins.rows = append(ins.rows, "("+strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")+")")
+	}
+	for _, ins := range inserts {
+		g.add(fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table.Name, strings.Join(ins.names, ", "), strings.Join(ins.rows, ", ")), ins.args...)
+	}
+}
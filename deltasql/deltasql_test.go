@@ -0,0 +1,120 @@
+package deltasql_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/quintans/delta/deltasql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type car struct {
+	id   string
+	make string
+	kms  int
+}
+
+func (c *car) ID() string {
+	return c.id
+}
+
+func carColumns(c *car) []deltasql.Column {
+	return []deltasql.Column{{Name: "id", Value: c.id}, {Name: "make", Value: c.make}, {Name: "kms", Value: c.kms}}
+}
+
+func TestUpdate(t *testing.T) {
+	name := delta.New("John")
+	age := delta.New(30)
+	photo := delta.New([]byte("photo"))
+	tracker := delta.NewTracker()
+	tracker.Register("name", name)
+	tracker.Register("age", age)
+	tracker.Register("photo", photo)
+	name.Set("Jane")
+	age.Set(31)
+	photo.Set([]byte("new photo"))
+
+	g := deltasql.NewGenerator(deltasql.WithPlaceholder(deltasql.Dollar))
+	columns := map[string]string{"name": "full_name", "age": "age"}
+	err := deltasql.Update(g, "person", []deltasql.Column{{Name: "id", Value: "p1"}}, columns, tracker.Changes())
+	require.NoError(t, err)
+	assert.Equal(t, []deltasql.Statement{{
+		Query: "UPDATE person SET age = $1, full_name = $2 WHERE id = $3",
+		Args:  []any{31, "Jane", "p1"},
+	}}, g.Statements())
+
+	g = deltasql.NewGenerator()
+	require.NoError(t, deltasql.Update(g, "person", nil, columns, map[string]any{}))
+	assert.Empty(t, g.Statements())
+
+	cars := delta.NewSlice([]*car{{id: "1"}})
+	cars.Remove("1")
+	err = deltasql.Update(g, "person", nil, map[string]string{"cars": "cars"}, map[string]any{"cars": cars.Changes()})
+	require.ErrorIs(t, err, deltasql.ErrNotScalar)
+	err = deltasql.Update(g, "person", nil, map[string]string{"name": "name"}, map[string]any{"name": (*delta.Change[string])(nil)})
+	require.ErrorIs(t, err, deltasql.ErrNotScalar)
+}
+
+func TestRows(t *testing.T) {
+	cars := delta.NewSlice([]*car{{id: "1", make: "bmw"}, {id: "2", make: "vw"}, {id: "3", make: "fiat"}})
+	cars.Remove("1")
+	cars.Remove("2")
+	cars.Set(&car{id: "3", make: "fiat", kms: 100})
+	cars.Set(&car{id: "4", make: "audi"})
+	cars.Set(&car{id: "5", make: "seat"})
+	table := deltasql.Table{Name: "car", ID: "id", Parent: []deltasql.Column{{Name: "owner_id", Value: "p1"}}}
+
+	g := deltasql.NewGenerator()
+	deltasql.Rows(g, table, cars.Changes(), carColumns)
+	assert.Equal(t, []deltasql.Statement{
+		{Query: "DELETE FROM car WHERE id IN (?, ?)", Args: []any{"1", "2"}},
+		{
+			Query: "INSERT INTO car (owner_id, id, make, kms) VALUES (?, ?, ?, ?), (?, ?, ?, ?)",
+			Args:  []any{"p1", "4", "audi", 0, "p1", "5", "seat", 0},
+		},
+		{Query: "UPDATE car SET make = ?, kms = ? WHERE id = ?", Args: []any{"fiat", 100, "3"}},
+	}, g.Statements())
+
+	cars.SetAll([]*car{{id: "6", make: "kia"}})
+	g = deltasql.NewGenerator(deltasql.WithPlaceholder(deltasql.Dollar))
+	deltasql.Rows(g, table, cars.Changes(), carColumns)
+	assert.Equal(t, []deltasql.Statement{
+		{Query: "DELETE FROM car WHERE owner_id = $1", Args: []any{"p1"}},
+		{Query: "INSERT INTO car (owner_id, id, make, kms) VALUES ($1, $2, $3, $4)", Args: []any{"p1", "6", "kia", 0}},
+	}, g.Statements())
+}
+
+func TestRows_Columns(t *testing.T) {
+	cars := delta.NewSlice([]*car{{id: "1", make: "bmw"}})
+	cars.Set(&car{id: "1", make: "bmw", kms: 10})
+	cars.Set(&car{id: "2", make: "vw"})
+	cars.Set(&car{id: "3"})
+	cars.Set(&car{id: "4", make: "fiat"})
+	table := deltasql.Table{Name: "car", ID: "id"}
+
+	// empty columns are omitted
+	columns := func(c *car) []deltasql.Column {
+		cols := []deltasql.Column{{Name: "id", Value: c.id}}
+		if c.make != "" {
+			cols = append(cols, deltasql.Column{Name: "make", Value: c.make})
+		}
+		return cols
+	}
+	g := deltasql.NewGenerator()
+	deltasql.Rows(g, table, cars.Changes(), columns)
+	assert.Equal(t, []deltasql.Statement{
+		{Query: "INSERT INTO car (id, make) VALUES (?, ?), (?, ?)", Args: []any{"2", "vw", "4", "fiat"}},
+		{Query: "INSERT INTO car (id) VALUES (?)", Args: []any{"3"}},
+		{Query: "UPDATE car SET make = ? WHERE id = ?", Args: []any{"bmw", "1"}},
+	}, g.Statements())
+
+	// nothing to update without columns other than the ID
+	g = deltasql.NewGenerator()
+	deltasql.Rows(g, table, cars.Changes(), func(c *car) []deltasql.Column {
+		return []deltasql.Column{{Name: "id", Value: c.id}}
+	})
+	assert.Equal(t, []deltasql.Statement{
+		{Query: "INSERT INTO car (id) VALUES (?), (?), (?)", Args: []any{"2", "3", "4"}},
+	}, g.Statements())
+}
@@ -0,0 +1,103 @@
+package deltasql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrConcurrentModification = errors.New("concurrent modification")
+
+// Execer executes statements, eg: *sql.DB, *sql.Tx, *sql.Conn, *sqlx.DB or *sqlx.Tx.
+// The native pgx executors are adapted with Pgx.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// commandTag is the result of a pgx execution, pgconn.CommandTag.
+type commandTag interface {
+	RowsAffected() int64
+}
+
+// PgxExecer executes statements with pgx, eg: pgx.Tx, *pgx.Conn or *pgxpool.Pool.
+type PgxExecer[R commandTag] interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (R, error)
+}
+
+// Pgx adapts a pgx executor to Execer, without depending on pgx, eg: deltasql.Pgx[pgconn.CommandTag](tx).
+func Pgx[R commandTag](db PgxExecer[R]) Execer {
+	return pgxExecer[R]{db: db}
+}
+
+type pgxExecer[R commandTag] struct {
+	db PgxExecer[R]
+}
+
+func (e pgxExecer[R]) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	tag, err := e.db.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgxResult(tag.RowsAffected()), nil
+}
+
+type pgxResult int64
+
+func (r pgxResult) LastInsertId() (int64, error) {
+	return 0, errors.ErrUnsupported
+}
+
+func (r pgxResult) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
+
+// Version adds the optimistic lock of an aggregate row, incrementing its version only if it is still the expected one.
+// It should be the first statement, so that Exec fails with ErrConcurrentModification before writing anything else.
+func Version(g *Generator, table string, key []Column, column string, version int64) {
+	cond, keys := where(key)
+	args := append([]any{version + 1}, keys...)
+	g.addExpecting(1, fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s AND %s = ?", table, column, cond, column), append(args, version)...)
+}
+
+// Exec executes the statements, in order, stopping at the first failure.
+// It fails with ErrConcurrentModification if a statement does not affect the expected rows.
+func Exec(ctx context.Context, db Execer, statements []Statement) error {
+	for _, s := range statements {
+		res, err := db.ExecContext(ctx, s.Query, s.Args...)
+		if err != nil {
+			return fmt.Errorf("executing %q: %w", s.Query, err)
+		}
+		if s.ExpectedRows == 0 {
+			continue
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("executing %q: %w", s.Query, err)
+		}
+		if n != s.ExpectedRows {
+			return fmt.Errorf("%w: %q affected %d rows instead of %d", ErrConcurrentModification, s.Query, n, s.ExpectedRows)
+		}
+	}
+	return nil
+}
+
+// Transaction runs fn in a transaction, that is committed if fn succeeds and rolled back otherwise.
+func Transaction(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+// Save executes the statements of the generator in a database/sql transaction.
+// With pgx, the statements are executed with Exec in a pgx transaction (eg: pgx.BeginFunc).
+func Save(ctx context.Context, db *sql.DB, g *Generator) error {
+	return Transaction(ctx, db, func(tx *sql.Tx) error {
+		return Exec(ctx, tx, g.Statements())
+	})
+}
@@ -0,0 +1,112 @@
+package deltasql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/quintans/delta/deltasql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDB records the executed statements, affecting one row per statement, except for stale versions.
+type fakeDB struct {
+	version  int64
+	executed []string
+	commits  int
+	rollback int
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return fakeTx(c), nil }
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.executed = append(c.db.executed, query)
+	if strings.Contains(query, "version = ?") && args[len(args)-1].Value != c.db.version {
+		return driver.RowsAffected(0), nil
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type fakeTx fakeConn
+
+func (t fakeTx) Commit() error   { t.db.commits++; return nil }
+func (t fakeTx) Rollback() error { t.db.rollback++; return nil }
+
+func TestSave(t *testing.T) {
+	fake := &fakeDB{version: 3}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	name := delta.New("John")
+	name.Set("Jane")
+	key := []deltasql.Column{{Name: "id", Value: "p1"}}
+
+	g := deltasql.NewGenerator()
+	deltasql.Version(g, "person", key, "version", 3)
+	require.NoError(t, deltasql.Update(g, "person", key, map[string]string{"name": "name"}, map[string]any{"name": name.Change()}))
+	require.NoError(t, deltasql.Save(context.Background(), db, g))
+	assert.Equal(t, []string{
+		"UPDATE person SET version = ? WHERE id = ? AND version = ?",
+		"UPDATE person SET name = ? WHERE id = ?",
+	}, fake.executed)
+	assert.Equal(t, 1, fake.commits)
+
+	// stale version
+	fake.executed = nil
+	g = deltasql.NewGenerator()
+	deltasql.Version(g, "person", key, "version", 2)
+	require.NoError(t, deltasql.Update(g, "person", key, map[string]string{"name": "name"}, map[string]any{"name": name.Change()}))
+	err := deltasql.Save(context.Background(), db, g)
+	require.ErrorIs(t, err, deltasql.ErrConcurrentModification)
+	assert.Len(t, fake.executed, 1)
+	assert.Equal(t, 1, fake.rollback)
+}
+
+// fakeTag is like pgconn.CommandTag.
+type fakeTag struct{ rows int64 }
+
+func (t fakeTag) RowsAffected() int64 { return t.rows }
+
+// fakePgxTx is like pgx.Tx, affecting one row per statement, except for stale versions.
+type fakePgxTx struct {
+	version  int64
+	executed []string
+}
+
+func (tx *fakePgxTx) Exec(_ context.Context, sql string, args ...any) (fakeTag, error) {
+	tx.executed = append(tx.executed, sql)
+	if strings.Contains(sql, "version = $") && args[len(args)-1] != tx.version {
+		return fakeTag{}, nil
+	}
+	return fakeTag{rows: 1}, nil
+}
+
+func TestExec_Pgx(t *testing.T) {
+	key := []deltasql.Column{{Name: "id", Value: "p1"}}
+	g := deltasql.NewGenerator(deltasql.WithPlaceholder(deltasql.Dollar))
+	deltasql.Version(g, "person", key, "version", 3)
+	name := delta.New("John")
+	name.Set("Jane")
+	require.NoError(t, deltasql.Update(g, "person", key, map[string]string{"name": "name"}, map[string]any{"name": name.Change()}))
+
+	tx := &fakePgxTx{version: 3}
+	require.NoError(t, deltasql.Exec(context.Background(), deltasql.Pgx[fakeTag](tx), g.Statements()))
+	assert.Len(t, tx.executed, 2)
+
+	tx = &fakePgxTx{version: 4}
+	err := deltasql.Exec(context.Background(), deltasql.Pgx[fakeTag](tx), g.Statements())
+	require.ErrorIs(t, err, deltasql.ErrConcurrentModification)
+	assert.Len(t, tx.executed, 1)
+}
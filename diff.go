@@ -0,0 +1,125 @@
+package delta
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"unsafe"
+)
+
+// DiffSlices returns the changes, by ID, that turn old into new (eg: for a full snapshot received by an importer):
+// the removed items come first, in the old order, followed by the added and modified items, in the new order.
+// Items are compared with equal or, if it is nil, with reflect.DeepEqual.
+func DiffSlices[T Identifiable[I], I comparable](old, new []T, equal func(a, b T) bool) Changes[T, I] {
+	if equal == nil {
+		equal = func(a, b T) bool { return reflect.DeepEqual(a, b) }
+	}
+	before := make(map[I]T, len(old))
+	for _, v := range old {
+		before[v.ID()] = v
+	}
+	after := make(map[I]struct{}, len(new))
+	for _, v := range new {
+		after[v.ID()] = struct{}{}
+	}
+
+	var changes []SliceChange[I, T]
+	for _, v := range old {
+		if _, ok := after[v.ID()]; !ok {
+			changes = append(changes, SliceChange[I, T]{ID: v.ID(), Status: Removed, Old: v, HasOld: true})
+		}
+	}
+	for _, v := range new {
+		o, ok := before[v.ID()]
+		switch {
+		case !ok:
+			changes = append(changes, SliceChange[I, T]{ID: v.ID(), Value: v, Status: Added})
+		case !equal(o, v):
+			changes = append(changes, SliceChange[I, T]{ID: v.ID(), Value: v, Status: Modified, Old: o, HasOld: true})
+		}
+	}
+	return Changes[T, I]{Items: slices.Values(changes)}
+}
+
+// FieldChange is the change of a field of a plain struct.
+type FieldChange struct {
+	Field string
+	Old   any
+	New   any
+}
+
+// DiffStructs returns the changes of the fields of two structs of the same type, or pointers to them,
+// in the order the fields are declared. Unexported fields are compared as well,
+// and the fields of embedded structs are compared as if they were fields of the struct.
+// Fields are compared with reflect.DeepEqual, unless the delta struct tag selects a comparator registered with RegisterEqual.
+// The tag also renames (`delta:"name"`) or skips (`delta:"-"`) a field.
+func DiffStructs(old, new any) ([]FieldChange, error) {
+	ov, err := structValue(old)
+	if err != nil {
+		return nil, err
+	}
+	nv, err := structValue(new)
+	if err != nil {
+		return nil, err
+	}
+	if ov.Type() != nv.Type() {
+		return nil, fmt.Errorf("%w: %s and %s are different types", ErrInvalidValue, ov.Type(), nv.Type())
+	}
+	var changes []FieldChange
+	if err := diffStructs(ov, nv, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// structValue returns an addressable copy of the struct, so that its unexported fields can be read.
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%w: %T is not a struct", ErrInvalidValue, v)
+	}
+	c := reflect.New(rv.Type()).Elem()
+	c.Set(rv)
+	return c, nil
+}
+
+func diffStructs(ov, nv reflect.Value, changes *[]FieldChange) error {
+	rt := ov.Type()
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		t, err := parseTag(sf.Tag)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", sf.Name, err)
+		}
+		if t.skip {
+			continue
+		}
+		of := reflect.NewAt(sf.Type, unsafe.Pointer(ov.Field(i).UnsafeAddr())).Elem()
+		nf := reflect.NewAt(sf.Type, unsafe.Pointer(nv.Field(i).UnsafeAddr())).Elem()
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if err := diffStructs(of, nf, changes); err != nil {
+				return err
+			}
+			continue
+		}
+		equal := reflect.DeepEqual
+		if t.equal != "" {
+			if equal, err = comparator(t.equal); err != nil {
+				return fmt.Errorf("field %s: %w", sf.Name, err)
+			}
+		}
+		o, n := of.Interface(), nf.Interface()
+		if equal(o, n) {
+			continue
+		}
+		name := sf.Name
+		if t.name != "" {
+			name = t.name
+		}
+		*changes = append(*changes, FieldChange{Field: name, Old: o, New: n})
+	}
+	return nil
+}
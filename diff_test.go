@@ -0,0 +1,85 @@
+package delta_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSlices(t *testing.T) {
+	e1 := &testEntity{id: "1", name: "entity1"}
+	e2 := &testEntity{id: "2", name: "entity2"}
+	e3 := &testEntity{id: "3", name: "entity3"}
+	e2b := &testEntity{id: "2", name: "changed"}
+	e4 := &testEntity{id: "4", name: "entity4"}
+
+	changes := delta.DiffSlices([]*testEntity{e1, e2, e3}, []*testEntity{e4, e2b, e3}, nil)
+
+	assert.False(t, changes.Reset)
+	assert.Equal(t, []delta.SliceChange[string, *testEntity]{
+		{ID: "1", Status: delta.Removed, Old: e1, HasOld: true},
+		{ID: "4", Value: e4, Status: delta.Added},
+		{ID: "2", Value: e2b, Status: delta.Modified, Old: e2, HasOld: true},
+	}, slices.Collect(changes.Items))
+}
+
+func TestDiffSlices_Equal(t *testing.T) {
+	old := []*testEntity{{id: "1", name: "entity1"}}
+	new := []*testEntity{{id: "1", name: "ENTITY1"}}
+	sameID := func(a, b *testEntity) bool { return a.id == b.id }
+
+	assert.Empty(t, slices.Collect(delta.DiffSlices(old, new, sameID).Items))
+	assert.Len(t, slices.Collect(delta.DiffSlices(old, new, nil).Items), 1)
+}
+
+type legacyAudit struct {
+	UpdatedBy string
+}
+
+type legacyPerson struct {
+	legacyAudit
+	Name    string
+	age     int
+	Tags    []string `delta:"tags"`
+	Cache   string   `delta:"-"`
+	Surname string   `delta:",equal=diff-insensitive"`
+}
+
+func TestDiffStructs(t *testing.T) {
+	delta.RegisterEqual("diff-insensitive", strings.EqualFold)
+	old := legacyPerson{
+		legacyAudit: legacyAudit{UpdatedBy: "john"},
+		Name:        "Paulo",
+		age:         40,
+		Tags:        []string{"a"},
+		Cache:       "x",
+		Surname:     "quintans",
+	}
+	new := old
+	new.UpdatedBy = "mary"
+	new.age = 41
+	new.Tags = []string{"a", "b"}
+	new.Cache = "y"
+	new.Surname = "Quintans"
+
+	changes, err := delta.DiffStructs(old, &new)
+	require.NoError(t, err)
+	assert.Equal(t, []delta.FieldChange{
+		{Field: "UpdatedBy", Old: "john", New: "mary"},
+		{Field: "age", Old: 40, New: 41},
+		{Field: "tags", Old: []string{"a"}, New: []string{"a", "b"}},
+	}, changes)
+
+	changes, err = delta.DiffStructs(old, old)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = delta.DiffStructs(old, legacyAudit{})
+	require.ErrorIs(t, err, delta.ErrInvalidValue)
+	_, err = delta.DiffStructs(old, 1)
+	require.ErrorIs(t, err, delta.ErrInvalidValue)
+}
@@ -0,0 +1,147 @@
+package delta
+
+import "slices"
+
+// DiscardChanges throws away the pending change, restoring the last fetched value.
+// If the value was set without being fetched, it will be loaded on the next access.
+func (v *LazyScalar[T]) DiscardChanges() {
+	v.mut.begin()
+	defer v.mut.end()
+
+	if !v.isDirty {
+		return
+	}
+	v.isDirty = false
+	if v.hasOrig {
+		v.value = v.original
+	} else {
+		var zero T
+		v.value = zero
+		v.isSet = false
+	}
+	v.updated()
+}
+
+// DiscardChanges throws away the pending changes, restoring the last fetched items.
+// Items changed without being fetched are dropped, to be loaded on the next access.
+func (s *LazySlice[T, I]) DiscardChanges() {
+	s.mut.begin()
+	defer s.mut.end()
+
+	fetched := s.fetched
+	if s.isReset {
+		fetched = s.beforeReset
+		s.isSet = s.wasSet
+		s.isReset = false
+		s.beforeReset = nil
+	}
+	s.replaceFetched(filterItems(fetched, func(item Item[T, I]) (Item[T, I], bool) {
+		switch item.status {
+		case Added:
+			return item, false
+		case Modified, Removed:
+			if !item.hasOld {
+				return item, false
+			}
+			return Item[T, I]{value: item.old, status: Unchanged}, true
+		}
+		return item, true
+	}))
+}
+
+// DiscardChanges throws away the pending changes, restoring the last fetched keys.
+// Keys changed without being fetched are dropped, to be loaded on the next access.
+func (d *DynamicFields) DiscardChanges() {
+	d.mut.begin()
+	defer d.mut.end()
+
+	fetched := d.fetched
+	if d.isReset {
+		fetched = d.beforeReset
+		d.isSet = d.wasSet
+		d.isReset = false
+		d.beforeReset = nil
+	}
+	d.fetched = filterItems(fetched, discardMapItem[any])
+	d.recount()
+}
+
+// DiscardChanges throws away the pending changes, restoring the last fetched keys.
+// Keys changed without being fetched are dropped, to be loaded on the next access.
+func (m *LazyMap[K, V]) DiscardChanges() {
+	m.mut.begin()
+	defer m.mut.end()
+
+	fetched := m.fetched
+	if m.isReset {
+		fetched = m.beforeReset
+		m.isSet = m.wasSet
+		m.isReset = false
+		m.beforeReset = nil
+	}
+	m.fetched = filterItems(fetched, discardMapItem[V])
+	m.recount()
+}
+
+// DiscardChanges throws away the pending changes, restoring the last fetched members.
+// Members removed without being fetched are dropped, to be loaded on the next access.
+func (s *LazySet[T]) DiscardChanges() {
+	s.mut.begin()
+	defer s.mut.end()
+
+	fetched := s.fetched
+	if s.isReset {
+		fetched = s.beforeReset
+		s.isSet = s.wasSet
+		s.isReset = false
+		s.beforeReset = nil
+	}
+	s.fetched = filterItems(fetched, func(item setItem) (setItem, bool) {
+		switch item.status {
+		case Added:
+			return item, false
+		case Removed:
+			return setItem{status: Unchanged, fetched: true}, item.fetched
+		}
+		return item, true
+	})
+	s.recount()
+}
+
+// DiscardChanges throws away the pending change, restoring the last fetched reference.
+// If the reference was changed without being fetched, it will be loaded on the next access.
+func (r *LazyRef[T, I]) DiscardChanges() {
+	r.mut.begin()
+	defer r.mut.end()
+
+	if !r.isDirty {
+		return
+	}
+	r.isDirty = false
+	r.value = r.orig
+	r.exists = r.isSet && r.origExists
+	r.syncGauge()
+}
+
+// DiscardChanges throws away the pending changes, restoring the fetched items in their fetched order.
+func (l *LazyList[T, I]) DiscardChanges() {
+	l.mut.begin()
+	defer l.mut.end()
+
+	l.items = slices.Clone(l.fetched)
+	clear(l.set)
+	l.syncGauge()
+}
+
+func discardMapItem[V any](item mapItem[V]) (mapItem[V], bool) {
+	switch item.status {
+	case Added:
+		return item, false
+	case Modified, Removed:
+		if !item.hasOld {
+			return item, false
+		}
+		return mapItem[V]{value: item.old, status: Unchanged}, true
+	}
+	return item, true
+}
@@ -0,0 +1,163 @@
+package delta_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyScalar_DiscardChanges(t *testing.T) {
+	callCount := 0
+	scalar := delta.NewLazy(func() (string, error) {
+		callCount++
+		return "loaded", nil
+	})
+	_, err := scalar.Get()
+	require.NoError(t, err)
+	scalar.Set("new value")
+	scalar.DiscardChanges()
+	assert.Nil(t, scalar.Change())
+	value, err := scalar.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "loaded", value)
+	assert.Equal(t, 1, callCount)
+
+	// set without being fetched
+	scalar = delta.NewLazy(func() (string, error) {
+		callCount++
+		return "loaded", nil
+	})
+	scalar.Set("new value")
+	scalar.DiscardChanges()
+	value, err = scalar.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "loaded", value)
+	assert.Equal(t, 2, callCount)
+}
+
+func TestLazySlice_DiscardChanges(t *testing.T) {
+	callCount := 0
+	base := fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+		{id: "3", name: "entity3"},
+	})
+	lazySlice := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		callCount++
+		return base(id)
+	})
+
+	_, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	lazySlice.Set(&testEntity{id: "1", name: "entity1_new"})
+	lazySlice.Set(&testEntity{id: "1", name: "entity1_newer"})
+	lazySlice.Remove("2")
+	lazySlice.Set(&testEntity{id: "4", name: "entity4"})
+
+	lazySlice.DiscardChanges()
+	assert.Empty(t, slices.Collect(lazySlice.Changes().Items))
+	all, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, []*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+		{id: "3", name: "entity3"},
+	}, slices.Collect(all))
+
+	lazySlice.Clear()
+	lazySlice.Set(&testEntity{id: "5", name: "entity5"})
+	lazySlice.DiscardChanges()
+	changes := lazySlice.Changes()
+	assert.False(t, changes.Reset)
+	assert.Empty(t, slices.Collect(changes.Items))
+	all, err = lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, ids(slices.Collect(all)))
+	assert.Equal(t, 1, callCount)
+}
+
+func TestLazySlice_DiscardChanges_NotFetched(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	lazySlice.Set(&testEntity{id: "1", name: "entity1_new"})
+	lazySlice.Remove("2")
+
+	lazySlice.DiscardChanges()
+	all, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, []*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}, slices.Collect(all))
+}
+
+func TestLazyMap_DiscardChanges(t *testing.T) {
+	var calls []string
+	settings := delta.NewLazyMap(mapFetcher(map[string]int{"volume": 5, "brightness": 80}, &calls))
+	_, err := settings.GetAll()
+	require.NoError(t, err)
+	settings.Put("volume", 7)
+	settings.Delete("brightness")
+	settings.Put("contrast", 50)
+
+	settings.DiscardChanges()
+	assert.Empty(t, slices.Collect(settings.Changes().Items))
+	all, err := settings.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"volume": 5, "brightness": 80}, maps.Collect(all))
+}
+
+func TestDynamicFields_DiscardChanges(t *testing.T) {
+	fields, err := delta.NewDynamicFieldsFrom(map[string]any{"color": "red"})
+	require.NoError(t, err)
+	fields.Clear()
+	require.NoError(t, fields.Set("size", 1))
+
+	fields.DiscardChanges()
+	assert.False(t, fields.IsReset())
+	all, err := fields.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"color": "red"}, maps.Collect(all))
+}
+
+func TestLazySet_DiscardChanges(t *testing.T) {
+	var calls []string
+	tags := delta.NewLazySet(setFetcher([]string{"go", "ddd"}, &calls))
+	ok, err := tags.Contains("go")
+	require.NoError(t, err)
+	require.True(t, ok)
+	tags.Remove("go")
+	tags.Remove("ddd") // not fetched
+	tags.Add("rust")
+
+	tags.DiscardChanges()
+	assert.Empty(t, setChanges(tags))
+	all, err := tags.GetAll()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"go", "ddd"}, slices.Collect(all))
+}
+
+func TestLazyRef_DiscardChanges(t *testing.T) {
+	address := delta.NewRef(&testEntity{id: "1"})
+	address.Remove()
+	address.DiscardChanges()
+	assert.Nil(t, address.Change())
+	v, err := address.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "1", v.id)
+
+	calls := 0
+	address = refTo(&testEntity{id: "1"}, &calls)
+	address.Set(&testEntity{id: "2"})
+	address.DiscardChanges()
+	v, err = address.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "1", v.id)
+	assert.Equal(t, 1, calls)
+}
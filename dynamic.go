@@ -0,0 +1,371 @@
+package delta
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// DynamicFields is a bag of string keyed attributes, for user defined attributes that cannot be modeled as struct fields.
+// Values are lazily loaded per key and changes are tracked per key.
+type DynamicFields struct {
+	mapItems[string, any]
+	isSet     bool
+	isReset   bool
+	fn        func(key string) (map[string]any, error) // function to load a key. If key is empty, load all keys.
+	fetchedAt time.Time
+	loadedAt  time.Time // when the oldest cached key was loaded
+	ttl       time.Duration
+	clock     Clock
+	counters  *fieldCounters
+	budget    *memoryBudget
+	mu        sync.Mutex // serializes the loads, so that concurrent reads share a single load
+	schema    Schema
+	stride    int
+	mut       mutations
+
+	// state before the fields were cleared, to be able to discard the changes
+	beforeReset *linkedmap.Map[string, mapItem[any]]
+	wasSet      bool
+}
+
+func NewDynamicFields(fn func(key string) (map[string]any, error), options ...Option) *DynamicFields {
+	opts := newOptions(options)
+	opts.only(dynamicOptions, (*DynamicFields)(nil))
+	return &DynamicFields{
+		mapItems: newMapItems[string, any](opts.capacity),
+		fn:       loader(opts, fn),
+		clock:    opts.clock,
+		ttl:      opts.ttl,
+		schema:   opts.schema,
+		stride:   opts.stride,
+	}
+}
+
+// NewDynamicFieldsFrom creates dynamic fields with all the values already loaded, ordered by key.
+// It fails if the values do not comply with the schema, when one is provided.
+func NewDynamicFieldsFrom(values map[string]any, options ...Option) (*DynamicFields, error) {
+	opts := newOptions(options)
+	opts.only(dynamicOptions, (*DynamicFields)(nil))
+	d := &DynamicFields{
+		mapItems: newMapItems[string, any](len(values)),
+		isSet:    true,
+		clock:    opts.clock,
+		ttl:      opts.ttl,
+		schema:   opts.schema,
+		stride:   opts.stride,
+	}
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		v, err := d.check(k, values[k])
+		if err != nil {
+			return nil, err
+		}
+		d.fetched.Put(k, mapItem[any]{value: v, status: Unchanged})
+	}
+	return d, nil
+}
+
+func (d *DynamicFields) GetAll() (iter.Seq2[string, any], error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.expire()
+	if d.isSet {
+		d.counters.hit()
+		d.budget.touch(d)
+		return d.present(d.stride), nil
+	}
+	d.counters.miss()
+	start := d.now()
+	values, err := d.fn("")
+	if err != nil {
+		d.counters.failed(d.now().Sub(start))
+		return nil, err
+	}
+	d.fetchedAt = d.now()
+	d.counters.loaded(sizeOfMap(values), d.fetchedAt.Sub(start))
+	d.markLoaded(d.fetchedAt)
+	values, err = d.checkAll(values)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mergeAll(values)
+
+	d.isSet = true
+	d.budget.loaded(d)
+	return d.present(d.stride), nil
+}
+
+func (d *DynamicFields) Get(key string) (any, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.expire()
+	item, exists := d.fetched.Get(key)
+	if exists {
+		d.counters.hit()
+		d.budget.touch(d)
+		if item.status == Absent || item.status == Removed {
+			return nil, ErrNotFound
+		}
+		return item.value, nil
+	}
+	if d.isSet {
+		d.counters.hit()
+		return nil, ErrNotFound
+	}
+
+	d.counters.miss()
+	start := d.now()
+	values, err := d.fn(key)
+	if err != nil {
+		d.counters.failed(d.now().Sub(start))
+		return nil, err
+	}
+	loadedAt := d.now()
+	d.counters.loaded(sizeOfMap(values), loadedAt.Sub(start))
+	d.markLoaded(loadedAt)
+	value, ok := values[key]
+	if !ok {
+		d.put(key, mapItem[any]{status: Absent})
+		return nil, ErrNotFound
+	}
+	value, err = d.check(key, value)
+	if err != nil {
+		return nil, err
+	}
+	d.put(key, mapItem[any]{value: value, status: Unchanged})
+	d.budget.loaded(d)
+	return value, nil
+}
+
+// Set sets the value of a key.
+// It fails if the value does not comply with the schema, when one is provided.
+func (d *DynamicFields) Set(key string, value any) error {
+	value, err := d.check(key, value)
+	if err != nil {
+		return err
+	}
+	d.mut.begin()
+	defer d.mut.end()
+
+	item := d.setKey(key, value)
+	d.mut.log(OpSet, "id", key, "status", item.status)
+	return nil
+}
+
+func (d *DynamicFields) Remove(key string) bool {
+	d.mut.begin()
+	defer d.mut.end()
+	d.mut.log(OpRemove, "id", key)
+
+	return d.removeKey(key)
+}
+
+func (d *DynamicFields) Clear() {
+	d.mut.begin()
+	defer d.mut.end()
+
+	if !d.isReset {
+		d.beforeReset = d.fetched
+		d.wasSet = d.isSet
+	}
+	d.isSet = true
+	d.isReset = true
+	d.fetched = linkedmap.New[string, mapItem[any]]()
+	d.recount()
+	d.mut.log(OpClear)
+}
+
+func (d *DynamicFields) IsReset() bool {
+	return d.isReset
+}
+
+// DynamicChange is a change of a key of DynamicFields.
+type DynamicChange struct {
+	Key    string
+	Value  any // nil for removed keys
+	Status Status
+	Old    any          // fetched value of modified and removed keys
+	HasOld bool         // false if the key was changed without being fetched
+	Type   reflect.Type // declared type, when there is a schema
+}
+
+// Changes returns the changed keys.
+func (d *DynamicFields) Changes() iter.Seq[DynamicChange] {
+	it := d.changed(d.stride)
+	return func(yield func(DynamicChange) bool) {
+		for k, v := range it {
+			change := DynamicChange{
+				Key:    k,
+				Value:  v.value,
+				Status: v.status,
+				Old:    v.old,
+				HasOld: v.hasOld,
+			}
+			if a, ok := d.schema[k]; ok {
+				change.Type = a.Type
+			}
+			if !yield(change) {
+				return
+			}
+		}
+	}
+}
+
+// FetchedAt returns when all the keys were loaded, or the zero time if they were not loaded.
+func (d *DynamicFields) FetchedAt() time.Time {
+	return d.fetchedAt
+}
+
+func (d *DynamicFields) now() time.Time {
+	if d.clock == nil {
+		return SystemClock.Now()
+	}
+	return d.clock.Now()
+}
+
+// ============ Schema ======================
+
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// Attribute declares the type and validation of a key of DynamicFields.
+type Attribute struct {
+	Type     reflect.Type
+	Validate func(any) error // optional
+}
+
+// AttributeOf declares an attribute of type T, with an optional validator.
+func AttributeOf[T any](validate func(T) error) Attribute {
+	a := Attribute{Type: reflect.TypeFor[T]()}
+	if validate != nil {
+		a.Validate = func(v any) error {
+			return validate(v.(T))
+		}
+	}
+	return a
+}
+
+// Schema declares the allowed keys of DynamicFields.
+type Schema map[string]Attribute
+
+// WithSchema restricts the keys of DynamicFields to the ones declared in the schema.
+// Written and loaded values are converted to the declared type (eg: float64 decoded from JSON into an int) and validated.
+func WithSchema(schema Schema) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optSchema
+		o.schema = schema
+	})
+}
+
+// check converts and validates the value against the schema, if any.
+func (d *DynamicFields) check(key string, value any) (any, error) {
+	if d.schema == nil {
+		return value, nil
+	}
+	a, ok := d.schema[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownField, key)
+	}
+	if value != nil && reflect.TypeOf(value) != a.Type {
+		v, err := convertTo(value, a.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q expects %s, got %T: %w", ErrTypeMismatch, key, a.Type, value, err)
+		}
+		value = v
+	}
+	if a.Validate != nil && value != nil {
+		if err := a.Validate(value); err != nil {
+			return nil, fmt.Errorf("%w: key %q: %w", ErrInvalidValue, key, err)
+		}
+	}
+	return value, nil
+}
+
+func (d *DynamicFields) checkAll(values map[string]any) (map[string]any, error) {
+	if d.schema == nil {
+		return values, nil
+	}
+	checked := make(map[string]any, len(values))
+	for k, v := range values {
+		c, err := d.check(k, v)
+		if err != nil {
+			return nil, err
+		}
+		checked[k] = c
+	}
+	return checked, nil
+}
+
+// GetAs returns the value of a key as T.
+func GetAs[T any](d *DynamicFields, key string) (T, error) {
+	var zero T
+	v, err := d.Get(key)
+	if err != nil {
+		return zero, err
+	}
+	if v == nil {
+		return zero, nil
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: key %q holds %T, not %s", ErrTypeMismatch, key, v, reflect.TypeFor[T]())
+	}
+	return t, nil
+}
+
+// ============ Field ======================
+
+func (d *DynamicFields) patch(op PatchOp) error {
+	switch op.Op {
+	case OpSet:
+		key, err := convert[string](op.ID)
+		if err != nil {
+			return err
+		}
+		return d.Set(key, op.Value)
+	case OpRemove:
+		key, err := convert[string](op.ID)
+		if err != nil {
+			return err
+		}
+		d.Remove(key)
+	case OpClear:
+		d.Clear()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedOperation, op.Op)
+	}
+	return nil
+}
+
+func (d *DynamicFields) operations() []PatchOp {
+	return d.keyOperations(d.isReset)
+}
+
+func (d *DynamicFields) base() Field {
+	return d
+}
+
+func (d *DynamicFields) instrument(c *fieldCounters) {
+	d.counters = c
+	if d.gauge != nil {
+		// withdraw the contribution of a previous registration
+		d.gauge.release()
+	}
+	d.gauge = &fieldGauge{counters: c}
+	d.recount()
+	runtime.AddCleanup(d, (*fieldGauge).release, d.gauge)
+}
+
+func (d *DynamicFields) setClock(clock Clock) {
+	d.clock = clock
+}
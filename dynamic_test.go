@@ -0,0 +1,149 @@
+package delta_test
+
+import (
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dynamicFetcher(values map[string]any, calls *[]string) func(key string) (map[string]any, error) {
+	return func(key string) (map[string]any, error) {
+		*calls = append(*calls, key)
+		if key == "" {
+			return values, nil
+		}
+		v, ok := values[key]
+		if !ok {
+			return nil, nil
+		}
+		return map[string]any{key: v}, nil
+	}
+}
+
+func TestDynamicFields_Get(t *testing.T) {
+	var calls []string
+	fields := delta.NewDynamicFields(dynamicFetcher(map[string]any{"color": "red", "size": 42}, &calls))
+
+	v, err := fields.Get("color")
+	require.NoError(t, err)
+	assert.Equal(t, "red", v)
+	_, err = fields.Get("color")
+	require.NoError(t, err)
+
+	_, err = fields.Get("unknown")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	_, err = fields.Get("unknown")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	assert.Equal(t, []string{"color", "unknown"}, calls)
+
+	all, err := fields.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"color": "red", "size": 42}, maps.Collect(all))
+	assert.Equal(t, []string{"color", "unknown", ""}, calls)
+}
+
+func TestDynamicFields_GetAllAfterAbsent(t *testing.T) {
+	values := map[string]any{}
+	fields := delta.NewDynamicFields(dynamicFetcher(values, new([]string)))
+
+	_, err := fields.Get("color")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+
+	values["color"] = "red" // eg: created meanwhile
+	all, err := fields.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"color": "red"}, maps.Collect(all))
+	assert.False(t, fields.IsDirty())
+}
+
+func TestDynamicFields_Changes(t *testing.T) {
+	var calls []string
+	fields := delta.NewDynamicFields(dynamicFetcher(map[string]any{"color": "red", "size": 42}, &calls))
+
+	require.NoError(t, fields.Set("weight", 10))
+	fields.Remove("color")
+	require.NoError(t, fields.Set("temp", 1))
+	fields.Remove("temp")
+
+	all, err := fields.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"weight": 10, "size": 42}, maps.Collect(all))
+
+	changes := slices.Collect(fields.Changes())
+	require.Len(t, changes, 2)
+	assert.Equal(t, delta.DynamicChange{Key: "weight", Value: 10, Status: delta.Added}, changes[0])
+	assert.Equal(t, delta.DynamicChange{Key: "color", Status: delta.Removed, Old: "red", HasOld: true}, changes[1])
+}
+
+func TestDynamicFields_Eager(t *testing.T) {
+	fields, err := delta.NewDynamicFieldsFrom(map[string]any{"b": 2, "a": 1})
+	require.NoError(t, err)
+	require.NoError(t, fields.Set("a", 3))
+
+	all, err := fields.GetAll()
+	require.NoError(t, err)
+	keys := slices.Collect(maps.Keys(maps.Collect(all)))
+	assert.ElementsMatch(t, []string{"a", "b"}, keys)
+
+	changes := slices.Collect(fields.Changes())
+	require.Len(t, changes, 1)
+	assert.Equal(t, delta.DynamicChange{Key: "a", Value: 3, Status: delta.Modified, Old: 1, HasOld: true}, changes[0])
+}
+
+func TestDynamicFields_Patch(t *testing.T) {
+	fields, err := delta.NewDynamicFieldsFrom(map[string]any{"a": 1})
+	require.NoError(t, err)
+	tracker := delta.NewTracker()
+	tracker.Register("attributes", fields)
+
+	err = delta.ApplyPatch(tracker, delta.Patch{
+		{Op: delta.OpSet, Path: "attributes", ID: "b", Value: "two"},
+		{Op: delta.OpRemove, Path: "attributes", ID: "a"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, delta.Patch{
+		{Op: delta.OpRemove, Path: "attributes", ID: "a"},
+		{Op: delta.OpSet, Path: "attributes", ID: "b", Value: "two"},
+	}, tracker.Compact())
+}
+
+func TestDynamicFields_Schema(t *testing.T) {
+	schema := delta.Schema{
+		"size": delta.AttributeOf(func(v int) error {
+			if v < 0 {
+				return errors.New("size must be positive")
+			}
+			return nil
+		}),
+		"color": delta.AttributeOf[string](nil),
+	}
+	var calls []string
+	// values as decoded from JSON
+	fields := delta.NewDynamicFields(dynamicFetcher(map[string]any{"color": "red", "size": 42.0}, &calls), delta.WithSchema(schema))
+
+	size, err := delta.GetAs[int](fields, "size")
+	require.NoError(t, err)
+	assert.Equal(t, 42, size)
+
+	_, err = delta.GetAs[string](fields, "size")
+	require.ErrorIs(t, err, delta.ErrTypeMismatch)
+
+	require.ErrorIs(t, fields.Set("size", -1), delta.ErrInvalidValue)
+	require.ErrorIs(t, fields.Set("size", "big"), delta.ErrTypeMismatch)
+	require.ErrorIs(t, fields.Set("weight", 1), delta.ErrUnknownField)
+	require.NoError(t, fields.Set("size", 43.0))
+
+	changes := slices.Collect(fields.Changes())
+	require.Len(t, changes, 1)
+	assert.Equal(t, delta.DynamicChange{Key: "size", Value: 43, Status: delta.Modified, Old: 42, HasOld: true, Type: reflect.TypeFor[int]()}, changes[0])
+
+	_, err = delta.NewDynamicFieldsFrom(map[string]any{"weight": 1}, delta.WithSchema(schema))
+	require.ErrorIs(t, err, delta.ErrUnknownField)
+}
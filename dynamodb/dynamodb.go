@@ -0,0 +1,178 @@
+// Package dynamodb converts deltas into DynamoDB update expressions, so that only the changed attributes are written.
+//
+// To avoid depending on the AWS SDK, the expression attribute values are plain Go values,
+// to be converted with the SDK, eg:
+//
+//	u := enc.Update()
+//	values, err := attributevalue.MarshalMap(u.Values)
+//	...
+//	client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+//		UpdateExpression:          aws.String(u.Expression),
+//		ExpressionAttributeNames:  u.Names,
+//		ExpressionAttributeValues: values,
+//		...
+//	})
+package dynamodb
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/quintans/delta"
+)
+
+// Update is an update expression with its attribute names and values.
+type Update struct {
+	Expression string            // eg: SET #n0 = :v0 REMOVE #n1.#n2
+	Names      map[string]string // placeholder -> attribute name
+	Values     map[string]any    // placeholder -> value
+}
+
+// Encoder collects the actions of the changed attributes of an item.
+type Encoder struct {
+	sets    []string
+	removes []string
+	names   map[string]string // attribute name -> placeholder
+	values  map[string]any
+}
+
+func NewEncoder() *Encoder {
+	return &Encoder{
+		names:  map[string]string{},
+		values: map[string]any{},
+	}
+}
+
+// Update returns the update expression of the collected actions. The expression is empty if nothing changed.
+func (e *Encoder) Update() Update {
+	var clauses []string
+	if len(e.sets) > 0 {
+		clauses = append(clauses, "SET "+strings.Join(e.sets, ", "))
+	}
+	if len(e.removes) > 0 {
+		clauses = append(clauses, "REMOVE "+strings.Join(e.removes, ", "))
+	}
+	names := make(map[string]string, len(e.names))
+	for name, p := range e.names {
+		names[p] = name
+	}
+	return Update{
+		Expression: strings.Join(clauses, " "),
+		Names:      names,
+		Values:     maps.Clone(e.values),
+	}
+}
+
+// path returns the document path of the attribute names, with a placeholder per name.
+func (e *Encoder) path(names ...string) string {
+	ps := make([]string, len(names))
+	for i, n := range names {
+		p, ok := e.names[n]
+		if !ok {
+			p = fmt.Sprintf("#n%d", len(e.names))
+			e.names[n] = p
+		}
+		ps[i] = p
+	}
+	return strings.Join(ps, ".")
+}
+
+func (e *Encoder) value(v any) string {
+	p := fmt.Sprintf(":v%d", len(e.values))
+	e.values[p] = v
+	return p
+}
+
+func (e *Encoder) set(path string, value any) {
+	e.sets = append(e.sets, path+" = "+e.value(value))
+}
+
+// Scalar adds the update of a scalar attribute, if it changed.
+func Scalar[T any](e *Encoder, attr string, change *delta.Change[T]) {
+	if change == nil {
+		return
+	}
+	e.set(e.path(attr), change.Value)
+}
+
+// Map adds the updates of a map attribute keyed by the item ID, one per changed item, converting each item to its map value.
+// Added and modified items are set, removed items are removed and a reset rewrites the whole map.
+func Map[T delta.Identifiable[I], I comparable](e *Encoder, attr string, changes delta.Changes[T, I], value func(T) any) {
+	if changes.Reset {
+		m := map[string]any{}
+		for c := range changes.Items {
+			if c.Status != delta.Removed {
+				m[fmt.Sprint(c.ID)] = value(c.Value)
+			}
+		}
+		e.set(e.path(attr), m)
+		return
+	}
+	for c := range changes.Items {
+		p := e.path(attr, fmt.Sprint(c.ID))
+		switch c.Status {
+		case delta.Added, delta.Modified:
+			e.set(p, value(c.Value))
+		case delta.Removed:
+			e.removes = append(e.removes, p)
+		}
+	}
+}
+
+// List adds the update of a list attribute, converting each item to its list element.
+// Items only added are appended with list_append.
+// Since list elements are addressed by index, a reset, a modification or a removal rewrites the whole list.
+func List[T delta.Identifiable[I], I comparable](e *Encoder, attr string, s *delta.LazySlice[T, I], element func(T) any) error {
+	changes := s.Changes()
+	var added []any
+	rewrite := changes.Reset
+	for c := range changes.Items {
+		switch c.Status {
+		case delta.Added:
+			added = append(added, element(c.Value))
+		case delta.Modified, delta.Removed:
+			rewrite = true
+		}
+	}
+
+	switch {
+	case rewrite:
+		all, err := s.GetAll()
+		if err != nil {
+			return err
+		}
+		elems := []any{}
+		for v := range all {
+			elems = append(elems, element(v))
+		}
+		e.set(e.path(attr), elems)
+	case len(added) > 0:
+		p := e.path(attr)
+		e.sets = append(e.sets, fmt.Sprintf("%s = list_append(%[1]s, %s)", p, e.value(added)))
+	}
+	return nil
+}
+
+// Dynamic adds the updates of a map attribute, one per changed key.
+// Removed keys are removed and a cleared map is rewritten.
+func Dynamic(e *Encoder, attr string, d *delta.DynamicFields) error {
+	if d.IsReset() {
+		all, err := d.GetAll()
+		if err != nil {
+			return err
+		}
+		e.set(e.path(attr), maps.Collect(all))
+		return nil
+	}
+	for c := range d.Changes() {
+		p := e.path(attr, c.Key)
+		switch c.Status {
+		case delta.Added, delta.Modified:
+			e.set(p, c.Value)
+		case delta.Removed:
+			e.removes = append(e.removes, p)
+		}
+	}
+	return nil
+}
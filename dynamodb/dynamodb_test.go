@@ -0,0 +1,111 @@
+package dynamodb_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/quintans/delta/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type car struct {
+	id   string
+	make string
+}
+
+func (c *car) ID() string {
+	return c.id
+}
+
+func carMake(c *car) any {
+	return c.make
+}
+
+func TestScalar(t *testing.T) {
+	name := delta.New("John")
+	age := delta.New(30)
+	name.Set("Jane")
+
+	enc := dynamodb.NewEncoder()
+	dynamodb.Scalar(enc, "name", name.Change())
+	dynamodb.Scalar(enc, "age", age.Change())
+	assert.Equal(t, dynamodb.Update{
+		Expression: "SET #n0 = :v0",
+		Names:      map[string]string{"#n0": "name"},
+		Values:     map[string]any{":v0": "Jane"},
+	}, enc.Update())
+
+	assert.Equal(t, "", dynamodb.NewEncoder().Update().Expression)
+}
+
+func TestMap(t *testing.T) {
+	cars := delta.NewSlice([]*car{{id: "1", make: "bmw"}, {id: "2", make: "vw"}})
+	cars.Remove("1")
+	cars.Set(&car{id: "2", make: "audi"})
+	cars.Set(&car{id: "3", make: "fiat"})
+
+	enc := dynamodb.NewEncoder()
+	dynamodb.Map(enc, "cars", cars.Changes(), carMake)
+	assert.Equal(t, dynamodb.Update{
+		Expression: "SET #n0.#n2 = :v0, #n0.#n3 = :v1 REMOVE #n0.#n1",
+		Names:      map[string]string{"#n0": "cars", "#n1": "1", "#n2": "2", "#n3": "3"},
+		Values:     map[string]any{":v0": "audi", ":v1": "fiat"},
+	}, enc.Update())
+
+	cars.SetAll([]*car{{id: "4", make: "kia"}})
+	enc = dynamodb.NewEncoder()
+	dynamodb.Map(enc, "cars", cars.Changes(), carMake)
+	assert.Equal(t, dynamodb.Update{
+		Expression: "SET #n0 = :v0",
+		Names:      map[string]string{"#n0": "cars"},
+		Values:     map[string]any{":v0": map[string]any{"4": "kia"}},
+	}, enc.Update())
+}
+
+func TestList(t *testing.T) {
+	cars := delta.NewSlice([]*car{{id: "1", make: "bmw"}})
+	cars.Set(&car{id: "2", make: "vw"})
+
+	enc := dynamodb.NewEncoder()
+	require.NoError(t, dynamodb.List(enc, "cars", &cars.LazySlice, carMake))
+	assert.Equal(t, dynamodb.Update{
+		Expression: "SET #n0 = list_append(#n0, :v0)",
+		Names:      map[string]string{"#n0": "cars"},
+		Values:     map[string]any{":v0": []any{"vw"}},
+	}, enc.Update())
+
+	cars.Remove("1")
+	enc = dynamodb.NewEncoder()
+	require.NoError(t, dynamodb.List(enc, "cars", &cars.LazySlice, carMake))
+	assert.Equal(t, dynamodb.Update{
+		Expression: "SET #n0 = :v0",
+		Names:      map[string]string{"#n0": "cars"},
+		Values:     map[string]any{":v0": []any{"vw"}},
+	}, enc.Update())
+}
+
+func TestList_Unchanged(t *testing.T) {
+	cars := delta.NewSlice([]*car{{id: "1", make: "bmw"}})
+
+	enc := dynamodb.NewEncoder()
+	require.NoError(t, dynamodb.List(enc, "cars", &cars.LazySlice, carMake))
+	u := enc.Update()
+	assert.Empty(t, u.Expression)
+	assert.Empty(t, u.Names, "no unused placeholders")
+}
+
+func TestDynamic(t *testing.T) {
+	fields, err := delta.NewDynamicFieldsFrom(map[string]any{"color": "red", "size": 10})
+	require.NoError(t, err)
+	require.NoError(t, fields.Set("color", "blue"))
+	fields.Remove("size")
+
+	enc := dynamodb.NewEncoder()
+	require.NoError(t, dynamodb.Dynamic(enc, "attrs", fields))
+	assert.Equal(t, dynamodb.Update{
+		Expression: "SET #n0.#n1 = :v0 REMOVE #n0.#n2",
+		Names:      map[string]string{"#n0": "attrs", "#n1": "color", "#n2": "size"},
+		Values:     map[string]any{":v0": "blue"},
+	}, enc.Update())
+}
@@ -0,0 +1,28 @@
+package delta
+
+import "iter"
+
+// ItemView is a cached item of a collection with its current status (eg: to render pending changes).
+type ItemView[T any] struct {
+	Value  T // fetched value, if known, for a removed item
+	Status Status
+}
+
+// Entries returns the cached items, including the removed ones, with their status, without loading them.
+// Items whose own changes are tracked (see Dirtyable) are reported as modified.
+func (s *LazySlice[T, I]) Entries() iter.Seq2[I, ItemView[T]] {
+	return func(yield func(I, ItemView[T]) bool) {
+		for id, item := range s.fetched.Entries() {
+			if item.status == Absent {
+				continue
+			}
+			v := ItemView[T]{Value: item.value, Status: item.effectiveStatus()}
+			if item.status == Removed && item.hasOld {
+				v.Value = item.old
+			}
+			if !yield(id, v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntries(t *testing.T) {
+	items := delta.NewSlice([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+		{id: "3", name: "entity3"},
+	})
+	items.Set(&testEntity{id: "1", name: "changed"})
+	items.Remove("2")
+	items.Set(&testEntity{id: "4", name: "entity4"})
+
+	views := map[string]delta.ItemView[*testEntity]{}
+	for id, v := range items.Entries() {
+		views[id] = v
+	}
+	assert.Equal(t, map[string]delta.ItemView[*testEntity]{
+		"1": {Value: &testEntity{id: "1", name: "changed"}, Status: delta.Modified},
+		"2": {Value: &testEntity{id: "2", name: "entity2"}, Status: delta.Removed},
+		"3": {Value: &testEntity{id: "3", name: "entity3"}, Status: delta.Unchanged},
+		"4": {Value: &testEntity{id: "4", name: "entity4"}, Status: delta.Added},
+	}, views)
+}
@@ -0,0 +1,86 @@
+package delta
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+// WithEqual sets the comparator used by scalars of type T to ignore the setting of a value equal to the current one,
+// and by collections of items of type T to detect the modified items in ReplaceAll.
+// By default, comparable values are compared with == and []byte with bytes.Equal.
+// Pointers are not compared by default, since the pointed value may have been changed in place.
+// Supported by scalars, LazySlice and LazyList.
+// It panics when used with a scalar or collection of a different type.
+func WithEqual[T any](equal func(a, b T) bool) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optEqual
+		o.equal = equal
+	})
+}
+
+func equalFor[T any](opts options) func(a, b T) bool {
+	if opts.equal == nil {
+		return defaultEqual[T]()
+	}
+	equal, ok := opts.equal.(func(a, b T) bool)
+	if !ok {
+		panic(fmt.Sprintf("delta: WithEqual comparator %T used with a value of %s", opts.equal, reflect.TypeFor[T]()))
+	}
+	return equal
+}
+
+// defaultEqual returns the comparator for T, or nil if T cannot be compared.
+func defaultEqual[T any]() func(a, b T) bool {
+	t := reflect.TypeFor[T]()
+	switch {
+	case t == reflect.TypeFor[[]byte]():
+		return func(a, b T) bool {
+			return bytes.Equal(any(a).([]byte), any(b).([]byte))
+		}
+	case t.Kind() == reflect.Pointer:
+		return nil
+	case t.Kind() == reflect.Interface:
+		// only comparable if the dynamic types are
+		return func(a, b T) bool {
+			va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+			if !va.IsValid() || !vb.IsValid() {
+				return va.IsValid() == vb.IsValid()
+			}
+			return va.Type() == vb.Type() && va.Comparable() && va.Equal(vb)
+		}
+	case t.Comparable() && holdsInterface(t):
+		// == panics if an interface holds a value that is not comparable,
+		// in which case the values are compared in depth
+		return func(a, b T) bool {
+			va, vb := reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem()
+			if va.Comparable() && vb.Comparable() {
+				return va.Equal(vb)
+			}
+			return reflect.DeepEqual(a, b)
+		}
+	case t.Comparable():
+		return func(a, b T) bool {
+			return any(a) == any(b)
+		}
+	default:
+		return nil
+	}
+}
+
+// holdsInterface returns true if a value of the comparable type t can hold an interface.
+func holdsInterface(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Array:
+		return holdsInterface(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if holdsInterface(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package delta_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyScalar_Set_Equal(t *testing.T) {
+	photo := delta.NewLazy(func() ([]byte, error) {
+		return []byte("photo"), nil
+	})
+	photo.MustGet()
+	photo.Set([]byte("photo"))
+	assert.False(t, photo.IsDirty())
+
+	photo.Set([]byte("new photo"))
+	assert.True(t, photo.IsDirty())
+	photo.Set([]byte("new photo"))
+	assert.True(t, photo.IsDirty())
+
+	// back to the fetched value
+	photo.Set([]byte("photo"))
+	assert.False(t, photo.IsDirty())
+	assert.Nil(t, photo.Change())
+
+	name := delta.New("John")
+	name.Set("John")
+	assert.Nil(t, name.Change())
+}
+
+func TestLazyScalar_Set_NotLoaded(t *testing.T) {
+	name := delta.NewLazy(func() (string, error) {
+		return "John", nil
+	})
+	// cannot be compared without loading
+	name.Set("John")
+	assert.True(t, name.IsDirty())
+}
+
+func TestLazyScalar_Set_Pointer(t *testing.T) {
+	entity := &testEntity{id: "1", name: "entity1"}
+	scalar := delta.New(entity)
+	entity.name = "changed"
+	scalar.Set(entity)
+	assert.True(t, scalar.IsDirty())
+}
+
+func TestWithEqual(t *testing.T) {
+	name := delta.NewLazy(func() (string, error) {
+		return "John", nil
+	}, delta.WithEqual(strings.EqualFold))
+	name.MustGet()
+	name.Set("JOHN")
+	assert.False(t, name.IsDirty())
+
+	assert.Panics(t, func() {
+		delta.NewLazy(func() (string, error) {
+			return "", nil
+		}, delta.WithEqual(bytes.Equal))
+	})
+}
+
+func TestLazyScalar_Set_InterfaceField(t *testing.T) {
+	type tagged struct {
+		Name  string
+		Value any
+	}
+	scalar := delta.New(tagged{Name: "a", Value: []string{"x"}})
+	assert.NotPanics(t, func() {
+		scalar.Set(tagged{Name: "a", Value: []string{"x"}})
+	})
+	assert.False(t, scalar.IsDirty())
+
+	scalar.Set(tagged{Name: "a", Value: []string{"y"}})
+	assert.True(t, scalar.IsDirty())
+
+	// comparable dynamic values are compared with ==
+	ints := delta.New(tagged{Name: "a", Value: 1})
+	ints.Set(tagged{Name: "a", Value: 1})
+	assert.False(t, ints.IsDirty())
+	ints.Set(tagged{Name: "a", Value: 2})
+	assert.True(t, ints.IsDirty())
+}
@@ -0,0 +1,69 @@
+package delta
+
+import "time"
+
+// WithErrorCache caches the load errors of scalars and of LazySlice for ttl, so that the loader is not called again until then.
+// Invalidate and Reload drop the cached errors.
+func WithErrorCache(ttl time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optErrorCache
+		o.errorTTL = ttl
+	})
+}
+
+// WithoutAbsentCache does not cache the items found missing in a LazySlice,
+// so that they are looked up again on the next access.
+func WithoutAbsentCache() Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optNoAbsent
+		o.noAbsent = true
+	})
+}
+
+type cachedError struct {
+	err error
+	at  time.Time
+}
+
+// errorCache keeps the load errors per key. A nil cache caches nothing.
+type errorCache[K comparable] struct {
+	ttl  time.Duration
+	errs map[K]cachedError
+}
+
+func newErrorCache[K comparable](ttl time.Duration) *errorCache[K] {
+	if ttl <= 0 {
+		return nil
+	}
+	return &errorCache[K]{ttl: ttl, errs: map[K]cachedError{}}
+}
+
+// get returns the cached error of the key, if it did not expire.
+func (c *errorCache[K]) get(key K, now time.Time) error {
+	if c == nil {
+		return nil
+	}
+	e, ok := c.errs[key]
+	if !ok {
+		return nil
+	}
+	if now.Sub(e.at) >= c.ttl {
+		delete(c.errs, key)
+		return nil
+	}
+	return e.err
+}
+
+func (c *errorCache[K]) put(key K, err error, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.errs[key] = cachedError{err: err, at: now}
+}
+
+func (c *errorCache[K]) clear() {
+	if c == nil {
+		return
+	}
+	clear(c.errs)
+}
@@ -0,0 +1,66 @@
+package delta_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithErrorCache(t *testing.T) {
+	clock := newFakeClock()
+	calls := 0
+	scalar := delta.NewLazy(func() (string, error) {
+		calls++
+		return "", errors.New("boom")
+	}, delta.WithErrorCache(time.Minute), delta.WithClock(clock))
+
+	_, err := scalar.Get()
+	require.EqualError(t, err, "boom")
+	_, err = scalar.Get()
+	require.EqualError(t, err, "boom")
+	assert.Equal(t, 1, calls)
+
+	clock.Advance(time.Minute)
+	_, err = scalar.Get()
+	require.EqualError(t, err, "boom")
+	assert.Equal(t, 2, calls)
+
+	require.EqualError(t, scalar.Reload(), "boom")
+	assert.Equal(t, 3, calls)
+}
+
+func TestLazySlice_WithErrorCache(t *testing.T) {
+	calls := 0
+	lazySlice := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		calls++
+		return nil, errors.New("boom")
+	}, delta.WithErrorCache(time.Minute))
+
+	_, err := lazySlice.GetAll()
+	require.EqualError(t, err, "boom")
+	_, err = lazySlice.Get("1")
+	require.EqualError(t, err, "boom")
+	_, err = lazySlice.GetAll()
+	require.EqualError(t, err, "boom")
+	_, err = lazySlice.Get("1")
+	require.EqualError(t, err, "boom")
+	assert.Equal(t, 2, calls)
+}
+
+func TestLazySlice_WithoutAbsentCache(t *testing.T) {
+	calls := 0
+	lazySlice := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		calls++
+		return nil, nil
+	}, delta.WithoutAbsentCache())
+
+	_, err := lazySlice.Get("1")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	_, err = lazySlice.Get("1")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,96 @@
+package delta
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// EventMapper converts the changes of named fields (eg: from Tracker.Changes or Collect) into domain events,
+// with the factories registered with MapScalarEvent and MapItemEvents.
+type EventMapper struct {
+	factories *linkedmap.Map[string, []func(change any) []any]
+}
+
+func NewEventMapper() *EventMapper {
+	return &EventMapper{factories: linkedmap.New[string, []func(change any) []any]()}
+}
+
+func (m *EventMapper) add(field string, factory func(change any) []any) {
+	factories, _ := m.factories.Get(field)
+	m.factories.Put(field, append(factories, factory))
+}
+
+// MapScalarEvent registers the factory of the event of a changed scalar field, eg: PhotoUpdated.
+// The factory returns nil if there is no event.
+// Events panics if the changes of the field are not a *Change[T].
+func MapScalarEvent[T any](m *EventMapper, field string, factory func(c *Change[T]) any) {
+	m.add(field, func(change any) []any {
+		c, ok := change.(*Change[T])
+		if !ok {
+			panic(fmt.Sprintf("delta: event factory of %s used with changes %T", field, change))
+		}
+		if e := factory(c); e != nil {
+			return []any{e}
+		}
+		return nil
+	})
+}
+
+// MapItemEvents registers the factory of the events of the changed items of a collection field, eg: CarAdded.
+// The factory is called for each changed item and returns nil if there is no event.
+// Events panics if the changes of the field are not a Changes[T, I].
+func MapItemEvents[T Identifiable[I], I comparable](m *EventMapper, field string, factory func(c SliceChange[I, T]) any) {
+	m.add(field, func(change any) []any {
+		c, ok := change.(Changes[T, I])
+		if !ok {
+			panic(fmt.Sprintf("delta: event factory of %s used with changes %T", field, change))
+		}
+		var events []any
+		for item := range c.Items {
+			if e := factory(item); e != nil {
+				events = append(events, e)
+			}
+		}
+		return events
+	})
+}
+
+// Events returns the events of the changes, in the order their factories were registered.
+func (m *EventMapper) Events(changes map[string]any) []any {
+	var events []any
+	for field, factories := range m.factories.Entries() {
+		change, ok := changes[field]
+		if !ok {
+			continue
+		}
+		for _, factory := range factories {
+			events = append(events, factory(change)...)
+		}
+	}
+	return events
+}
+
+// Events collects the domain events of an aggregate until they are drained on save.
+// It is meant to be embedded in the aggregate.
+type Events struct {
+	pending []any
+}
+
+// Record records the events.
+func (e *Events) Record(events ...any) {
+	e.pending = append(e.pending, events...)
+}
+
+// PendingEvents returns the recorded events, oldest first.
+func (e *Events) PendingEvents() []any {
+	return slices.Clone(e.pending)
+}
+
+// Drain returns the recorded events, oldest first, and forgets them.
+func (e *Events) Drain() []any {
+	events := e.pending
+	e.pending = nil
+	return events
+}
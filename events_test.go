@@ -0,0 +1,58 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+)
+
+type photoUpdated struct{ photo string }
+
+type carAdded struct{ id string }
+
+type carRemoved struct{ id string }
+
+type eventsAggregate struct {
+	delta.Events
+	tracker *delta.Tracker
+	photo   *delta.Scalar[string]
+	cars    *delta.Slice[*testEntity, string]
+}
+
+func TestEventMapper(t *testing.T) {
+	mapper := delta.NewEventMapper()
+	delta.MapItemEvents(mapper, "cars", func(c delta.SliceChange[string, *testEntity]) any {
+		switch c.Status {
+		case delta.Added:
+			return carAdded{id: c.ID}
+		case delta.Removed:
+			return carRemoved{id: c.ID}
+		}
+		return nil
+	})
+	delta.MapScalarEvent(mapper, "photo", func(c *delta.Change[string]) any {
+		return photoUpdated{photo: c.Value}
+	})
+
+	agg := &eventsAggregate{
+		tracker: delta.NewTracker(),
+		photo:   delta.New("photo"),
+		cars:    delta.NewSlice([]*testEntity{{id: "1", name: "car1"}, {id: "2", name: "car2"}}),
+	}
+	agg.tracker.Register("photo", agg.photo)
+	agg.tracker.Register("cars", agg.cars)
+	agg.photo.Set("new photo")
+	agg.cars.Remove("1")
+	agg.cars.Set(&testEntity{id: "2", name: "car2_new"})
+	agg.cars.Set(&testEntity{id: "3", name: "car3"})
+
+	agg.Record(mapper.Events(agg.tracker.Changes())...)
+	assert.Equal(t, []any{carRemoved{id: "1"}, carAdded{id: "3"}, photoUpdated{photo: "new photo"}}, agg.PendingEvents())
+	assert.Len(t, agg.Drain(), 3)
+	assert.Empty(t, agg.Drain())
+
+	assert.Panics(t, func() {
+		mapper.Events(map[string]any{"photo": &delta.Change[int]{Value: 1}})
+	})
+}
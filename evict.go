@@ -0,0 +1,136 @@
+package delta
+
+import (
+	"sync"
+	"time"
+	"weak"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// Evictable is implemented by lazy values whose loaded, but unmodified, data can be dropped
+// and transparently reloaded on the next access.
+type Evictable interface {
+	// Evict drops the loaded data, unless there are pending changes.
+	// It returns true if something was dropped.
+	Evict() bool
+}
+
+// Evict drops the loaded value, unless it was modified or there is no loader to reload it.
+func (v *LazyScalar[T]) Evict() bool {
+	if !v.isSet || v.isDirty || v.fn == nil {
+		return false
+	}
+	var zero T
+	v.value = zero
+	v.original, v.hasOrig = zero, false
+	v.isSet = false
+	v.fetchedAt = time.Time{}
+	v.updated()
+	return true
+}
+
+// Evict drops the loaded items that have no pending changes, unless the collection was reset
+// or there is no loader to reload them.
+func (s *LazySlice[T, I]) Evict() bool {
+	if s.isReset || s.fn == nil {
+		return false
+	}
+	fetched := linkedmap.New[I, Item[T, I]]()
+	for id, item := range s.fetched.Entries() {
+		if item.status == Unchanged || item.status == Absent {
+			continue
+		}
+		fetched.Put(id, item)
+	}
+	if fetched.Size() == s.fetched.Size() && !s.isSet {
+		return false
+	}
+	s.isSet = false
+	s.fetchedAt = time.Time{}
+	s.loadedAt = time.Time{}
+	s.queries = nil
+	s.existing = nil
+	// not a change of the collection, so it is not recorded in the history
+	s.fetched = fetched
+	s.recount()
+	return true
+}
+
+// Evict drops the loaded keys that have no pending changes, unless the fields were cleared
+// or there is no loader to reload them.
+func (d *DynamicFields) Evict() bool {
+	if d.isReset || d.fn == nil {
+		return false
+	}
+	if d.dropUnchanged() == 0 && !d.isSet {
+		return false
+	}
+	d.isSet = false
+	d.fetchedAt = time.Time{}
+	d.loadedAt = time.Time{}
+	return true
+}
+
+// MemoryManager keeps weak references to evictable values so that their data can be dropped under memory pressure.
+// Managed values are not kept alive by the manager.
+//
+// Evicting must not happen concurrently with the use of the managed values.
+type MemoryManager struct {
+	mu      sync.Mutex
+	entries []func() (alive, evicted bool)
+}
+
+func NewMemoryManager() *MemoryManager {
+	return &MemoryManager{}
+}
+
+// Manage registers an evictable value in the memory manager.
+func Manage[T any, P interface {
+	*T
+	Evictable
+}](m *MemoryManager, value P) {
+	wp := weak.Make((*T)(value))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = append(m.entries, func() (bool, bool) {
+		v := wp.Value()
+		if v == nil {
+			return false, false
+		}
+		return true, P(v).Evict()
+	})
+}
+
+// Evict evicts all the managed values, returning how many were evicted.
+// Values that were garbage collected are forgotten.
+func (m *MemoryManager) Evict() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	alive := m.entries[:0]
+	for _, evict := range m.entries {
+		ok, evicted := evict()
+		if !ok {
+			continue
+		}
+		alive = append(alive, evict)
+		if evicted {
+			count++
+		}
+	}
+	clear(m.entries[len(alive):])
+	m.entries = alive
+	return count
+}
+
+// Len returns the number of managed values, including the ones that might have been garbage collected
+// since the last eviction.
+func (m *MemoryManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.entries)
+}
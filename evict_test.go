@@ -0,0 +1,103 @@
+package delta_test
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyScalar_Evict(t *testing.T) {
+	callCount := 0
+	scalar := delta.NewLazy(func() (string, error) {
+		callCount++
+		return "loaded", nil
+	})
+	assert.False(t, scalar.Evict())
+
+	_, err := scalar.Get()
+	require.NoError(t, err)
+	assert.True(t, scalar.Evict())
+
+	value, err := scalar.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "loaded", value)
+	assert.Equal(t, 2, callCount)
+
+	// dirty values are never evicted
+	scalar.Set("new value")
+	assert.False(t, scalar.Evict())
+	value, err = scalar.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "new value", value)
+	assert.Equal(t, 2, callCount)
+
+	// eager values cannot be reloaded
+	assert.False(t, delta.New("eager").Evict())
+}
+
+func TestLazySlice_Evict(t *testing.T) {
+	callCount := 0
+	base := fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+		{id: "3", name: "entity3"},
+	})
+	lazySlice := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		callCount++
+		return base(id)
+	})
+
+	_, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	lazySlice.Set(&testEntity{id: "2", name: "entity2_new"})
+	lazySlice.Remove("3")
+
+	assert.True(t, lazySlice.Evict())
+
+	seq, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, 2, callCount)
+	result := slices.Collect(seq)
+	require.Len(t, result, 2)
+	assert.Equal(t, "entity2_new", result[0].name)
+	assert.Equal(t, "entity1", result[1].name)
+
+	changes := slices.Collect(lazySlice.Changes().Items)
+	require.Len(t, changes, 2)
+	assert.Equal(t, delta.Modified, changes[0].Status)
+	assert.Equal(t, delta.Removed, changes[1].Status)
+
+	// reset collections cannot be reloaded
+	lazySlice.Clear()
+	assert.False(t, lazySlice.Evict())
+}
+
+func TestMemoryManager(t *testing.T) {
+	manager := delta.NewMemoryManager()
+
+	scalar := delta.NewLazy(func() (string, error) {
+		return "loaded", nil
+	})
+	delta.Manage(manager, scalar)
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{{id: "1", name: "entity1"}}))
+	delta.Manage(manager, lazySlice)
+	delta.Manage(manager, delta.NewLazy(func() (int, error) {
+		return 1, nil
+	}))
+
+	_, err := scalar.Get()
+	require.NoError(t, err)
+	_, err = lazySlice.GetAll()
+	require.NoError(t, err)
+
+	runtime.GC()
+	assert.Equal(t, 2, manager.Evict())
+	assert.Equal(t, 2, manager.Len())
+
+	runtime.KeepAlive(scalar)
+	runtime.KeepAlive(lazySlice)
+}
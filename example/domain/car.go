@@ -45,6 +45,11 @@ func (c *Car) Kms() int {
 	return c.kms.Get()
 }
 
+// HasChanges reports the car as modified in the cars of its owner, when driven.
+func (c *Car) HasChanges() bool {
+	return c.kms.IsDirty()
+}
+
 type CarDelta struct {
 	Kms *delta.Change[int]
 }
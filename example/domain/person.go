@@ -93,7 +93,6 @@ func (p *Person) DriveCar(carID uuid.UUID, kms int) error {
 	for car := range cars {
 		if car.ID() == carID {
 			car.drive(kms)
-			p.cars.Set(car)
 			return nil
 		}
 	}
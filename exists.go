@@ -0,0 +1,73 @@
+package delta
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// WithExistsLoader sets the loader used by LazySlice.Exists to check if an item exists without loading it.
+// It panics when used with a collection of a different ID type.
+func WithExistsLoader[I comparable](fn func(I) (bool, error)) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optExistsLoader
+		o.exister = fn
+	})
+}
+
+func existerFor[I comparable](opts options) func(I) (bool, error) {
+	if opts.exister == nil {
+		return nil
+	}
+	exister, ok := opts.exister.(func(I) (bool, error))
+	if !ok {
+		panic(fmt.Sprintf("delta: WithExistsLoader loader %T used with a collection of IDs of %s", opts.exister, reflect.TypeFor[I]()))
+	}
+	return exister
+}
+
+// Exists returns true if the item exists, taking into account the pending changes.
+// Unknown items are checked with the exists loader (see WithExistsLoader), or loaded if there is none.
+func (s *LazySlice[T, I]) Exists(id I) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	if item, ok := s.fetched.Get(id); ok {
+		s.counters.hit()
+		return item.status != Absent && item.status != Removed, nil
+	}
+	if _, ok := s.existing[id]; ok || s.isSet {
+		s.counters.hit()
+		return ok, nil
+	}
+	if s.exister == nil {
+		_, err := s.get(id)
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	s.counters.miss()
+	start := s.now()
+	exists, err := s.exister(id)
+	if err != nil {
+		s.counters.failed(s.now().Sub(start))
+		return false, err
+	}
+	loadedAt := s.now()
+	s.counters.loaded(0, loadedAt.Sub(start))
+	if s.loadedAt.IsZero() {
+		s.loadedAt = loadedAt
+	}
+	if !exists {
+		s.absent(id)
+		return false, nil
+	}
+	if s.existing == nil {
+		s.existing = map[I]struct{}{}
+	}
+	s.existing[id] = struct{}{}
+	return true, nil
+}
@@ -0,0 +1,58 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazySlice_Exists(t *testing.T) {
+	calls := 0
+	lazySlice := delta.NewLazySlice(
+		func(id string) ([]*testEntity, error) {
+			t.Fatal("items should not be loaded")
+			return nil, nil
+		},
+		delta.WithExistsLoader(func(id string) (bool, error) {
+			calls++
+			return id == "1" || id == "2", nil
+		}),
+	)
+
+	exists, err := lazySlice.Exists("1")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = lazySlice.Exists("3")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// results are cached
+	exists, err = lazySlice.Exists("1")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 2, calls)
+
+	// pending changes are taken into account
+	lazySlice.Remove("2")
+	lazySlice.Set(&testEntity{id: "3", name: "entity3"})
+	exists, err = lazySlice.Exists("2")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = lazySlice.Exists("3")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 2, calls)
+}
+
+func TestLazySlice_Exists_WithoutExistsLoader(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{{id: "1", name: "entity1"}}))
+
+	exists, err := lazySlice.Exists("1")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = lazySlice.Exists("2")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,28 @@
+package exporter
+
+import (
+	"expvar"
+
+	"github.com/quintans/delta"
+)
+
+// Snapshot is the state of the internal statistics at a point in time.
+type Snapshot struct {
+	ActiveTrackers map[string]int64   `json:"activeTrackers"`
+	Fields         []delta.FieldStats `json:"fields"`
+}
+
+func TakeSnapshot() Snapshot {
+	return Snapshot{
+		ActiveTrackers: delta.ActiveTrackers(),
+		Fields:         delta.Stats(),
+	}
+}
+
+// PublishExpvar publishes the internal statistics as an expvar variable with the given name.
+// Like expvar.Publish, it panics if the name is already in use.
+func PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return TakeSnapshot()
+	}))
+}
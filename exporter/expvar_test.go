@@ -0,0 +1,34 @@
+package exporter_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/quintans/delta/exporter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	photo := delta.NewLazy(func() ([]byte, error) {
+		return []byte("photo"), nil
+	})
+	tracker := delta.NewTracker(delta.WithAggregateType("expvar-person"))
+	tracker.Register("photo", photo)
+	_, err := photo.Get()
+	require.NoError(t, err)
+
+	exporter.PublishExpvar("delta")
+
+	v := expvar.Get("delta")
+	require.NotNil(t, v)
+	var snapshot exporter.Snapshot
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &snapshot))
+	assert.Equal(t, int64(1), snapshot.ActiveTrackers["expvar-person"])
+	require.Len(t, snapshot.Fields, 1)
+	assert.Equal(t, "photo", snapshot.Fields[0].Field)
+	assert.Equal(t, int64(1), snapshot.Fields[0].Loads)
+	assert.Equal(t, int64(1), snapshot.Fields[0].Cached)
+}
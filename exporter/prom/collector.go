@@ -0,0 +1,82 @@
+package prom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/quintans/delta"
+)
+
+const namespace = "delta"
+
+// Collector is a prometheus.Collector publishing the internal statistics of the delta package.
+type Collector struct {
+	activeTrackers *prometheus.Desc
+	loads          *prometheus.Desc
+	errors         *prometheus.Desc
+	hits           *prometheus.Desc
+	misses         *prometheus.Desc
+	bytes          *prometheus.Desc
+	cached         *prometheus.Desc
+	pending        *prometheus.Desc
+	latency        *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+func NewCollector() *Collector {
+	fieldLabels := []string{"aggregate_type", "field"}
+	return &Collector{
+		activeTrackers: prometheus.NewDesc(namespace+"_active_trackers", "Number of live trackers.", []string{"aggregate_type"}, nil),
+		loads:          prometheus.NewDesc(namespace+"_loads_total", "Number of successful loader calls.", fieldLabels, nil),
+		errors:         prometheus.NewDesc(namespace+"_load_errors_total", "Number of failed loader calls.", fieldLabels, nil),
+		hits:           prometheus.NewDesc(namespace+"_cache_hits_total", "Reads served from the cache.", fieldLabels, nil),
+		misses:         prometheus.NewDesc(namespace+"_cache_misses_total", "Reads that required a load.", fieldLabels, nil),
+		bytes:          prometheus.NewDesc(namespace+"_loaded_bytes_total", "Bytes loaded.", fieldLabels, nil),
+		cached:         prometheus.NewDesc(namespace+"_cached_items", "Items currently cached.", fieldLabels, nil),
+		pending:        prometheus.NewDesc(namespace+"_pending_changes", "Pending changes.", fieldLabels, nil),
+		latency:        prometheus.NewDesc(namespace+"_load_duration_seconds", "Load latencies.", fieldLabels, nil),
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.activeTrackers
+	ch <- c.loads
+	ch <- c.errors
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.bytes
+	ch <- c.cached
+	ch <- c.pending
+	ch <- c.latency
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for aggType, n := range delta.ActiveTrackers() {
+		ch <- prometheus.MustNewConstMetric(c.activeTrackers, prometheus.GaugeValue, float64(n), aggType)
+	}
+
+	for _, s := range delta.Stats() {
+		labels := []string{s.AggregateType, s.Field}
+		ch <- prometheus.MustNewConstMetric(c.loads, prometheus.CounterValue, float64(s.Loads), labels...)
+		ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, float64(s.Errors), labels...)
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(s.Hits), labels...)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(s.Misses), labels...)
+		ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.CounterValue, float64(s.Bytes), labels...)
+		ch <- prometheus.MustNewConstMetric(c.cached, prometheus.GaugeValue, float64(s.Cached), labels...)
+		ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(s.Pending), labels...)
+
+		// prometheus buckets are cumulative
+		buckets := make(map[float64]uint64, len(s.LoadLatency.Bounds))
+		var cumulative uint64
+		for i, bound := range s.LoadLatency.Bounds {
+			cumulative += uint64(s.LoadLatency.Counts[i])
+			buckets[bound.Seconds()] = cumulative
+		}
+		ch <- prometheus.MustNewConstHistogram(
+			c.latency,
+			uint64(s.LoadLatency.Count),
+			s.LoadLatency.Sum.Seconds(),
+			buckets,
+			labels...,
+		)
+	}
+}
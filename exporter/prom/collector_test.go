@@ -0,0 +1,49 @@
+package prom_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/quintans/delta"
+	"github.com/quintans/delta/exporter/prom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	photo := delta.NewLazy(func() ([]byte, error) {
+		return []byte("photo"), nil
+	})
+	tracker := delta.NewTracker(delta.WithAggregateType("person"))
+	tracker.Register("photo", photo)
+	_, err := photo.Get()
+	require.NoError(t, err)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(prom.NewCollector()))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	values := map[string]float64{}
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			switch {
+			case m.GetCounter() != nil:
+				values[f.GetName()] = m.GetCounter().GetValue()
+			case m.GetGauge() != nil:
+				values[f.GetName()] = m.GetGauge().GetValue()
+			case m.GetHistogram() != nil:
+				values[f.GetName()] = float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	assert.Equal(t, 1.0, values["delta_active_trackers"])
+	assert.Equal(t, 1.0, values["delta_loads_total"])
+	assert.Equal(t, 1.0, values["delta_cache_misses_total"])
+	assert.Equal(t, 5.0, values["delta_loaded_bytes_total"])
+	assert.Equal(t, 1.0, values["delta_cached_items"])
+	assert.Equal(t, 1.0, values["delta_load_duration_seconds"])
+	runtime.KeepAlive(tracker)
+}
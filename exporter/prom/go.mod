@@ -0,0 +1,25 @@
+module github.com/quintans/delta/exporter/prom
+
+go 1.25.1
+
+require (
+	github.com/prometheus/client_golang v1.20.5
+	github.com/quintans/delta v0.0.0-20261016142743-2cec2e8f7103
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.55.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	github.com/quintans/ds v0.0.0-20251112153132-ec0d93363ad2 // indirect
+	github.com/quintans/faults v1.7.1 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
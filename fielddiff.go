@@ -0,0 +1,42 @@
+package delta
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithFieldDiff reports, in Change.Fields, the fields of a struct scalar that changed from the fetched value
+// (eg: only the zip code of an Address), compared as in DiffStructs, so that only those need to be audited or written.
+// It panics when used with a value that is not a struct or a pointer to a struct.
+func WithFieldDiff() Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optFieldDiff
+		o.fieldDiff = true
+	})
+}
+
+func fieldDiffFor[T any](opts options) bool {
+	if !opts.fieldDiff {
+		return false
+	}
+	rt := reflect.TypeFor[T]()
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("delta: WithFieldDiff used with a value of %s", reflect.TypeFor[T]()))
+	}
+	return true
+}
+
+// fieldChanges returns the changed fields, or nil if they cannot be compared (eg: there is no fetched value or it is a nil pointer).
+func (v *LazyScalar[T]) fieldChanges() []FieldChange {
+	if !v.fieldDiff || !v.hasOrig || isNull(v.original) || isNull(v.value) {
+		return nil
+	}
+	changes, err := DiffStructs(v.original, v.value)
+	if err != nil {
+		return nil
+	}
+	return changes
+}
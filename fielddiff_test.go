@@ -0,0 +1,38 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	Street string
+	Zip    string `delta:"zip_code"`
+}
+
+func TestWithFieldDiff(t *testing.T) {
+	addr := delta.New(address{Street: "Main St", Zip: "1000"}, delta.WithFieldDiff())
+	addr.Set(address{Street: "Main St", Zip: "2000"})
+
+	c := addr.Change()
+	require.NotNil(t, c)
+	assert.Equal(t, []delta.FieldChange{{Field: "zip_code", Old: "1000", New: "2000"}}, c.Fields)
+
+	ptr := delta.NewLazy(func() (*address, error) { return &address{Street: "Main St"}, nil }, delta.WithFieldDiff())
+	_, err := ptr.Get()
+	require.NoError(t, err)
+	ptr.Set(&address{Street: "Side St"})
+	assert.Equal(t, []delta.FieldChange{{Field: "Street", Old: "Main St", New: "Side St"}}, ptr.Change().Fields)
+
+	ptr.SetNull()
+	assert.Nil(t, ptr.Change().Fields)
+
+	plain := delta.New(address{Zip: "1000"})
+	plain.Set(address{Zip: "2000"})
+	assert.Nil(t, plain.Change().Fields)
+
+	assert.Panics(t, func() { delta.New("name", delta.WithFieldDiff()) })
+}
@@ -0,0 +1,56 @@
+package delta
+
+import "iter"
+
+// Find loads all the items, if not loaded yet, and returns the first one matching the predicate, in the GetAll order.
+// Pending changes are taken into account. It returns ErrNotFound if no item matches.
+func (s *LazySlice[T, I]) Find(predicate func(T) bool) (T, error) {
+	var zero T
+	all, err := s.GetAll()
+	if err != nil {
+		return zero, err
+	}
+	for v := range all {
+		if predicate(v) {
+			return v, nil
+		}
+	}
+	return zero, ErrNotFound
+}
+
+// Filter loads all the items, if not loaded yet, and iterates over the ones matching the predicate.
+// Pending changes are taken into account.
+func (s *LazySlice[T, I]) Filter(predicate func(T) bool) (iter.Seq[T], error) {
+	all, err := s.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return matching(all, predicate), nil
+}
+
+// RemoveWhere loads all the items, if not loaded yet, and removes the ones matching the predicate,
+// returning how many were removed.
+func (s *LazySlice[T, I]) RemoveWhere(predicate func(T) bool) (int, error) {
+	if err := s.mut.writable(); err != nil {
+		return 0, err
+	}
+	matches, err := s.Filter(predicate)
+	if err != nil {
+		return 0, err
+	}
+	var ids []I
+	for v := range matches {
+		ids = append(ids, v.ID())
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	s.mut.begin()
+	defer s.mut.end()
+
+	for _, id := range ids {
+		s.Remove(id)
+	}
+	return len(ids), nil
+}
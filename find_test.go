@@ -0,0 +1,84 @@
+package delta_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazySlice_Find(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "a"},
+		{id: "2", name: "b"},
+	}))
+	items.Remove("1")
+	items.Set(&testEntity{id: "3", name: "a"})
+
+	found, err := items.Find(func(e *testEntity) bool { return e.name == "a" })
+	require.NoError(t, err)
+	assert.Equal(t, "3", found.id)
+
+	_, err = items.Find(func(e *testEntity) bool { return e.name == "z" })
+	require.ErrorIs(t, err, delta.ErrNotFound)
+}
+
+func TestLazySlice_Filter(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "a"},
+		{id: "2", name: "b"},
+		{id: "3", name: "a"},
+	}))
+	items.Remove("3")
+	items.Set(&testEntity{id: "4", name: "a"})
+
+	found, err := items.Filter(func(e *testEntity) bool { return e.name == "a" })
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1", "4"}, ids(slices.Collect(found)))
+
+	errBoom := errors.New("connection refused")
+	failing := delta.NewLazySlice(func(string) ([]*testEntity, error) { return nil, errBoom })
+	_, err = failing.Filter(func(*testEntity) bool { return true })
+	require.ErrorIs(t, err, errBoom)
+}
+
+func TestLazySlice_RemoveWhere(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "a"},
+		{id: "2", name: "b"},
+		{id: "3", name: "a"},
+	}))
+	items.Set(&testEntity{id: "4", name: "a"})
+
+	n, err := items.RemoveWhere(func(e *testEntity) bool { return e.name == "a" })
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	all, err := items.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, ids(slices.Collect(all)))
+	changes := changesByID(items.Changes())
+	assert.Len(t, changes, 2)
+	assert.Equal(t, delta.Removed, changes["1"].Status)
+	assert.Equal(t, delta.Removed, changes["3"].Status)
+}
+
+func TestLazySlice_RemoveWhere_Undo(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "a"},
+		{id: "2", name: "a"},
+	}))
+	tracker := delta.NewTracker(delta.WithJournal())
+	tracker.Register("items", items)
+
+	n, err := items.RemoveWhere(func(e *testEntity) bool { return e.name == "a" })
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// the removals are a single mutation
+	require.NoError(t, tracker.Undo())
+	assert.False(t, items.IsDirty())
+}
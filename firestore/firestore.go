@@ -0,0 +1,181 @@
+// Package firestore converts deltas into Firestore field updates, so that only the changed fields are written.
+//
+// To avoid depending on the Firestore SDK, updates are built with this package's Update struct
+// and sentinel values, which are converted to the SDK ones with WithTransforms, eg:
+//
+//	enc := firestore.NewEncoder(firestore.WithTransforms(firestore.Transforms{
+//		ArrayUnion:  func(elems ...any) any { return fs.ArrayUnion(elems...) },
+//		ArrayRemove: func(elems ...any) any { return fs.ArrayRemove(elems...) },
+//		Delete:      fs.Delete,
+//	}))
+//	...
+//	for _, u := range enc.Updates() {
+//		updates = append(updates, fs.Update{Path: u.Path, Value: u.Value})
+//	}
+package firestore
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/quintans/delta"
+)
+
+// Update mirrors the Update struct of the Firestore SDK.
+type Update struct {
+	Path  string
+	Value any
+}
+
+// ArrayUnion is the default sentinel value to add elements to an array.
+type ArrayUnion []any
+
+// ArrayRemove is the default sentinel value to remove elements from an array.
+type ArrayRemove []any
+
+type deleteField struct{}
+
+// Delete is the default sentinel value to delete a field.
+var Delete any = deleteField{}
+
+// Transforms create the sentinel values of the updates.
+type Transforms struct {
+	ArrayUnion  func(elems ...any) any
+	ArrayRemove func(elems ...any) any
+	Delete      any
+}
+
+type Option func(*Encoder)
+
+// WithTransforms replaces the default sentinel values, typically by the ones of the Firestore SDK.
+func WithTransforms(t Transforms) Option {
+	return func(e *Encoder) {
+		e.transforms = t
+	}
+}
+
+// Encoder collects the updates of the changed fields of a document.
+type Encoder struct {
+	transforms Transforms
+	updates    []Update
+}
+
+func NewEncoder(options ...Option) *Encoder {
+	e := &Encoder{
+		transforms: Transforms{
+			ArrayUnion: func(elems ...any) any {
+				return ArrayUnion(elems)
+			},
+			ArrayRemove: func(elems ...any) any {
+				return ArrayRemove(elems)
+			},
+			Delete: Delete,
+		},
+	}
+	for _, opt := range options {
+		opt(e)
+	}
+	return e
+}
+
+// Updates returns the collected updates.
+func (e *Encoder) Updates() []Update {
+	return slices.Clone(e.updates)
+}
+
+func (e *Encoder) add(path string, value any) {
+	e.updates = append(e.updates, Update{Path: path, Value: value})
+}
+
+// Scalar adds the update of a scalar field, if it changed.
+func Scalar[T any](e *Encoder, path string, change *delta.Change[T]) {
+	if change == nil {
+		return
+	}
+	e.add(path, change.Value)
+}
+
+// Slice adds the update of an array field, converting each item to its array element.
+// Items only added use ArrayUnion and items only removed use ArrayRemove.
+// Since an update cannot transform the same field twice, and modified items cannot be matched by value,
+// a reset, a modification or a mix of additions and removals rewrites the whole array.
+// The element of a removed item is built from its fetched value, or from the zero value if it was not fetched,
+// in which case ArrayRemove is only accurate for arrays whose elements derive from the ID.
+func Slice[T delta.Identifiable[I], I comparable](e *Encoder, path string, s *delta.LazySlice[T, I], element func(id I, value T) any) error {
+	changes := s.Changes()
+	var added, removed []any
+	rewrite := changes.Reset
+	for c := range changes.Items {
+		switch c.Status {
+		case delta.Added:
+			added = append(added, element(c.ID, c.Value))
+		case delta.Removed:
+			removed = append(removed, element(c.ID, c.Old))
+		case delta.Modified:
+			rewrite = true
+		}
+	}
+	if len(added) > 0 && len(removed) > 0 {
+		rewrite = true
+	}
+
+	switch {
+	case rewrite:
+		all, err := s.GetAll()
+		if err != nil {
+			return err
+		}
+		elems := []any{}
+		for v := range all {
+			elems = append(elems, element(v.ID(), v))
+		}
+		e.add(path, elems)
+	case len(added) > 0:
+		e.add(path, e.transforms.ArrayUnion(added...))
+	case len(removed) > 0:
+		e.add(path, e.transforms.ArrayRemove(removed...))
+	}
+	return nil
+}
+
+// Dynamic adds the updates of a map field, one per changed key.
+// Removed keys are deleted and a cleared map is rewritten.
+func Dynamic(e *Encoder, path string, d *delta.DynamicFields) error {
+	if d.IsReset() {
+		all, err := d.GetAll()
+		if err != nil {
+			return err
+		}
+		e.add(path, maps.Collect(all))
+		return nil
+	}
+	for c := range d.Changes() {
+		p := path + "." + FieldPath(c.Key)
+		switch c.Status {
+		case delta.Added, delta.Modified:
+			e.add(p, c.Value)
+		case delta.Removed:
+			e.add(p, e.transforms.Delete)
+		}
+	}
+	return nil
+}
+
+var simpleField = regexp.MustCompile(`^[A-Za-z_][A-Za-z_0-9]*$`)
+
+// FieldPath joins the field names into a dotted path, quoting the names that are not simple identifiers.
+func FieldPath(names ...string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		if simpleField.MatchString(n) {
+			quoted[i] = n
+			continue
+		}
+		n = strings.ReplaceAll(n, `\`, `\\`)
+		n = strings.ReplaceAll(n, "`", "\\`")
+		quoted[i] = "`" + n + "`"
+	}
+	return strings.Join(quoted, ".")
+}
@@ -0,0 +1,140 @@
+package firestore_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/quintans/delta/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type car struct {
+	id   string
+	make string
+}
+
+func (c *car) ID() string {
+	return c.id
+}
+
+func carID(id string, _ *car) any {
+	return id
+}
+
+func carsOf(cars ...*car) *delta.LazySlice[*car, string] {
+	return &delta.NewSlice(cars).LazySlice
+}
+
+func TestScalar(t *testing.T) {
+	name := delta.New("John")
+	age := delta.New(30)
+	name.Set("Jane")
+
+	enc := firestore.NewEncoder()
+	firestore.Scalar(enc, "name", name.Change())
+	firestore.Scalar(enc, "age", age.Change())
+
+	assert.Equal(t, []firestore.Update{{Path: "name", Value: "Jane"}}, enc.Updates())
+}
+
+func TestSlice(t *testing.T) {
+	t.Run("added", func(t *testing.T) {
+		cars := carsOf(&car{id: "1"})
+		cars.Set(&car{id: "2"})
+		cars.Set(&car{id: "3"})
+
+		enc := firestore.NewEncoder()
+		require.NoError(t, firestore.Slice(enc, "cars", cars, carID))
+		assert.Equal(t, []firestore.Update{{Path: "cars", Value: firestore.ArrayUnion{"2", "3"}}}, enc.Updates())
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		cars := carsOf(&car{id: "1"}, &car{id: "2"})
+		cars.Remove("1")
+
+		enc := firestore.NewEncoder()
+		require.NoError(t, firestore.Slice(enc, "cars", cars, carID))
+		assert.Equal(t, []firestore.Update{{Path: "cars", Value: firestore.ArrayRemove{"1"}}}, enc.Updates())
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		cars := carsOf(&car{id: "1"}, &car{id: "2"})
+		cars.Remove("1")
+		cars.Set(&car{id: "3"})
+
+		enc := firestore.NewEncoder()
+		require.NoError(t, firestore.Slice(enc, "cars", cars, carID))
+		assert.Equal(t, []firestore.Update{{Path: "cars", Value: []any{"2", "3"}}}, enc.Updates())
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		cars := carsOf(&car{id: "1", make: "Ford"})
+		cars.Set(&car{id: "1", make: "Fiat"})
+
+		enc := firestore.NewEncoder()
+		require.NoError(t, firestore.Slice(enc, "cars", cars, func(_ string, c *car) any {
+			return map[string]any{"id": c.id, "make": c.make}
+		}))
+		assert.Equal(t, []firestore.Update{{Path: "cars", Value: []any{map[string]any{"id": "1", "make": "Fiat"}}}}, enc.Updates())
+	})
+
+	t.Run("cleared", func(t *testing.T) {
+		cars := carsOf(&car{id: "1"})
+		cars.Clear()
+
+		enc := firestore.NewEncoder()
+		require.NoError(t, firestore.Slice(enc, "cars", cars, carID))
+		assert.Equal(t, []firestore.Update{{Path: "cars", Value: []any{}}}, enc.Updates())
+	})
+}
+
+func TestSlice_WithTransforms(t *testing.T) {
+	type union struct{ elems []any }
+	cars := carsOf()
+	cars.Set(&car{id: "1"})
+
+	enc := firestore.NewEncoder(firestore.WithTransforms(firestore.Transforms{
+		ArrayUnion: func(elems ...any) any {
+			return union{elems: elems}
+		},
+	}))
+	require.NoError(t, firestore.Slice(enc, "cars", cars, carID))
+	assert.Equal(t, []firestore.Update{{Path: "cars", Value: union{elems: []any{"1"}}}}, enc.Updates())
+}
+
+func TestDynamic(t *testing.T) {
+	fields, err := delta.NewDynamicFieldsFrom(map[string]any{"height": 180, "eye color": "blue"})
+	require.NoError(t, err)
+	require.NoError(t, fields.Set("height", 181))
+	fields.Remove("eye color")
+
+	enc := firestore.NewEncoder()
+	require.NoError(t, firestore.Dynamic(enc, "attributes", fields))
+	assert.Equal(t, []firestore.Update{
+		{Path: "attributes.`eye color`", Value: firestore.Delete},
+		{Path: "attributes.height", Value: 181},
+	}, enc.Updates())
+
+	fields.Clear()
+	require.NoError(t, fields.Set("weight", 80))
+	enc = firestore.NewEncoder()
+	require.NoError(t, firestore.Dynamic(enc, "attributes", fields))
+	assert.Equal(t, []firestore.Update{{Path: "attributes", Value: map[string]any{"weight": 80}}}, enc.Updates())
+}
+
+func TestFieldPath(t *testing.T) {
+	assert.Equal(t, "a.b_1", firestore.FieldPath("a", "b_1"))
+	assert.Equal(t, "a.`b.c`.`1x`.`a\\`b`", firestore.FieldPath("a", "b.c", "1x", "a`b"))
+}
+
+func TestSlice_RemovedValues(t *testing.T) {
+	cars := carsOf(&car{id: "1", make: "Ford"})
+	cars.Remove("1")
+
+	enc := firestore.NewEncoder()
+	require.NoError(t, firestore.Slice(enc, "cars", cars, func(_ string, c *car) any {
+		return c.make
+	}))
+	assert.Equal(t, []firestore.Update{{Path: "cars", Value: firestore.ArrayRemove{"Ford"}}}, enc.Updates())
+}
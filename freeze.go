@@ -0,0 +1,71 @@
+package delta
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrFrozen = errors.New("frozen")
+
+// writable returns ErrFrozen if the field is frozen.
+func (m *mutations) writable() error {
+	if !m.frozen {
+		return nil
+	}
+	if m.field != "" {
+		return fmt.Errorf("%w: %s", ErrFrozen, m.field)
+	}
+	return ErrFrozen
+}
+
+// Freeze makes the value read-only: Set and DiscardChanges panic with ErrFrozen,
+// and the mutating methods with an error result (eg: Rebase) return it. Loads are still allowed.
+func (v *LazyScalar[T]) Freeze() {
+	v.mut.frozen = true
+}
+
+// Freeze makes the collection read-only: Set, Remove, SetAll, Clear and DiscardChanges panic with ErrFrozen,
+// and the mutating methods with an error result (eg: Add, ReplaceAll) return it. Loads are still allowed.
+func (s *LazySlice[T, I]) Freeze() {
+	s.mut.frozen = true
+}
+
+// Freeze makes the fields read-only, as LazySlice.Freeze.
+func (d *DynamicFields) Freeze() {
+	d.mut.frozen = true
+}
+
+// Freeze makes the map read-only, as LazySlice.Freeze.
+func (m *LazyMap[K, V]) Freeze() {
+	m.mut.frozen = true
+}
+
+// Freeze makes the set read-only, as LazySlice.Freeze.
+func (s *LazySet[T]) Freeze() {
+	s.mut.frozen = true
+}
+
+// Freeze makes the reference read-only, as LazyScalar.Freeze.
+func (r *LazyRef[T, I]) Freeze() {
+	r.mut.frozen = true
+}
+
+// Freeze makes the list read-only: its mutating methods return ErrFrozen and DiscardChanges panics with it.
+func (l *LazyList[T, I]) Freeze() {
+	l.mut.frozen = true
+}
+
+// IsFrozen returns true if the tracker was frozen.
+func (t *Tracker) IsFrozen() bool {
+	return t.frozen
+}
+
+// Freeze makes the registered fields, and the ones registered later, read-only (see LazySlice.Freeze)
+// before handing the aggregate to read-only consumers.
+// Execute, ApplyPatch, Rebase, Undo, Redo and RollbackTo return ErrFrozen.
+func (t *Tracker) Freeze() {
+	t.frozen = true
+	for field := range t.fields.Values() {
+		field.Freeze()
+	}
+}
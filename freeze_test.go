@@ -0,0 +1,36 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazySlice_Freeze(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{{id: "1", name: "entity1"}}))
+	items.Freeze()
+
+	v, err := items.Get("1")
+	require.NoError(t, err)
+	assert.Equal(t, "entity1", v.name)
+	assert.PanicsWithError(t, "frozen", func() { items.Remove("1") })
+	assert.ErrorIs(t, items.Add(&testEntity{id: "2"}), delta.ErrFrozen)
+	assert.False(t, items.IsDirty())
+}
+
+func TestTracker_Freeze(t *testing.T) {
+	name := delta.New("Paulo")
+	tracker := delta.NewTracker()
+	tracker.Register("name", name)
+	tracker.Freeze()
+	age := delta.New(40)
+	tracker.Register("age", age)
+
+	assert.True(t, tracker.IsFrozen())
+	assert.PanicsWithError(t, "frozen: name", func() { name.Set("Quintans") })
+	assert.Panics(t, func() { age.Set(41) })
+	assert.Equal(t, "Paulo", name.Get())
+	assert.ErrorIs(t, delta.ApplyPatch(tracker, delta.Patch{{Op: delta.OpSet, Path: "name", Value: "Quintans"}}), delta.ErrFrozen)
+}
@@ -0,0 +1,140 @@
+package delta
+
+import (
+	"bytes"
+	"encoding/gob"
+	"slices"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// RegisterGob registers the tracked types of T, so that they can be gob encoded as interface values (eg: in a session store).
+func RegisterGob[T any]() {
+	gob.Register(&Scalar[T]{})
+	gob.Register(Change[T]{})
+}
+
+// RegisterGobSlice registers the collection types of T, so that they can be gob encoded as interface values.
+func RegisterGobSlice[T Identifiable[I], I comparable]() {
+	gob.Register(&Slice[T, I]{})
+	gob.Register(Changes[T, I]{})
+}
+
+func gobEncode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gobDecode(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+type scalarGob[T any] struct {
+	Value    T
+	Dirty    bool
+	Original T
+	HasOrig  bool
+}
+
+// MarshalBinary encodes the value and its change state with gob.
+func (e *Scalar[T]) MarshalBinary() ([]byte, error) {
+	return gobEncode(scalarGob[T]{Value: e.value, Dirty: e.isDirty, Original: e.original, HasOrig: e.hasOrig})
+}
+
+func (e *Scalar[T]) UnmarshalBinary(data []byte) error {
+	var v scalarGob[T]
+	if err := gobDecode(data, &v); err != nil {
+		return err
+	}
+	if e.clock == nil {
+		// zero value, eg: allocated by the decoder
+		e.init(nil, newOptions(nil))
+	}
+	e.isSet = true
+	e.value, e.isDirty = v.Value, v.Dirty
+	e.original, e.hasOrig = v.Original, v.HasOrig
+	e.updated()
+	return nil
+}
+
+type itemGob[T any, I comparable] struct {
+	ID     I
+	Value  T
+	Status Status
+	Old    T
+	HasOld bool
+}
+
+type sliceGob[T any, I comparable] struct {
+	Reset bool
+	Items []itemGob[T, I]
+}
+
+// MarshalBinary encodes the items and their change state with gob.
+func (e *Slice[T, I]) MarshalBinary() ([]byte, error) {
+	v := sliceGob[T, I]{Reset: e.isReset, Items: make([]itemGob[T, I], 0, e.fetched.Size())}
+	for id, item := range e.fetched.Entries() {
+		v.Items = append(v.Items, itemGob[T, I]{ID: id, Value: item.value, Status: item.status, Old: item.old, HasOld: item.hasOld})
+	}
+	return gobEncode(v)
+}
+
+func (e *Slice[T, I]) UnmarshalBinary(data []byte) error {
+	var v sliceGob[T, I]
+	if err := gobDecode(data, &v); err != nil {
+		return err
+	}
+	if e.fetched == nil {
+		// zero value, eg: allocated by the decoder
+		e.init(nil, newOptions(nil))
+	}
+	fetched := linkedmap.New(linkedmap.WithCapacity[I, Item[T, I]](len(v.Items)))
+	for _, item := range v.Items {
+		fetched.Put(item.ID, Item[T, I]{value: item.Value, status: item.Status, old: item.Old, hasOld: item.HasOld})
+	}
+	e.isSet = true
+	e.isReset = v.Reset
+	e.replaceFetched(fetched)
+	return nil
+}
+
+// changeGob has the fields of Change, without its methods, to encode it with gob.
+type changeGob[T any] Change[T]
+
+// MarshalBinary encodes the change with gob.
+func (c Change[T]) MarshalBinary() ([]byte, error) {
+	return gobEncode(changeGob[T](c))
+}
+
+func (c *Change[T]) UnmarshalBinary(data []byte) error {
+	return gobDecode(data, (*changeGob[T])(c))
+}
+
+type changesGob[T Identifiable[I], I comparable] struct {
+	Reset bool
+	Items []SliceChange[I, T]
+}
+
+// MarshalBinary encodes the changes with gob.
+func (c Changes[T, I]) MarshalBinary() ([]byte, error) {
+	v := changesGob[T, I]{Reset: c.Reset}
+	if c.Items != nil {
+		for item := range c.Items {
+			item.Delta = nil
+			v.Items = append(v.Items, item)
+		}
+	}
+	return gobEncode(v)
+}
+
+func (c *Changes[T, I]) UnmarshalBinary(data []byte) error {
+	var v changesGob[T, I]
+	if err := gobDecode(data, &v); err != nil {
+		return err
+	}
+	*c = Changes[T, I]{Reset: v.Reset, Items: slices.Values(v.Items)}
+	return nil
+}
@@ -0,0 +1,50 @@
+package delta_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGob(t *testing.T) {
+	delta.RegisterGob[int]()
+	delta.RegisterGobSlice[*jsonEntity, string]()
+
+	scalar := delta.New(10)
+	scalar.Set(20)
+	cars := delta.NewSlice([]*jsonEntity{
+		{Key: "1", Name: "car1"},
+		{Key: "2", Name: "car2"},
+	})
+	cars.Set(&jsonEntity{Key: "1", Name: "car1_new"})
+	cars.Remove("2")
+
+	session := map[string]any{
+		"age":     scalar,
+		"cars":    cars,
+		"change":  *scalar.Change(),
+		"changes": cars.Changes(),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(session))
+
+	var decoded map[string]any
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+
+	age := decoded["age"].(*delta.Scalar[int])
+	assert.Equal(t, 20, age.Get())
+	assert.Equal(t, scalar.Change(), age.Change())
+
+	decodedCars := decoded["cars"].(*delta.Slice[*jsonEntity, string])
+	assert.Equal(t, slices.Collect(cars.GetAll()), slices.Collect(decodedCars.GetAll()))
+	assert.Equal(t, slices.Collect(cars.Changes().Items), slices.Collect(decodedCars.Changes().Items))
+
+	assert.Equal(t, *scalar.Change(), decoded["change"])
+	changes := decoded["changes"].(delta.Changes[*jsonEntity, string])
+	assert.Equal(t, slices.Collect(cars.Changes().Items), slices.Collect(changes.Items))
+}
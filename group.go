@@ -0,0 +1,85 @@
+package delta
+
+import "sync"
+
+// LazyGroup loads, with a single fetch, the values of several lazy fields (eg: a photo and its metadata stored in the same row).
+// Each field joins the group with the loader returned by Join or JoinSlice,
+// and the first one to be loaded fetches the values of all of them.
+type LazyGroup[R any] struct {
+	mu        sync.Mutex
+	fn        func() (R, error)
+	value     R
+	loaded    bool
+	members   int
+	delivered map[int]bool // members that already consumed the fetched value
+}
+
+// NewLazyGroup creates a group whose members are loaded from the value returned by fn.
+func NewLazyGroup[R any](fn func() (R, error)) *LazyGroup[R] {
+	return &LazyGroup[R]{
+		fn:        fn,
+		delivered: map[int]bool{},
+	}
+}
+
+// fetch returns the fetched value to member, fetching it if it was not fetched yet or if the member already consumed it,
+// so that a reload fetches fresh values.
+func (g *LazyGroup[R]) fetch(member int) (R, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.loaded || g.delivered[member] {
+		value, err := g.fn()
+		if err != nil {
+			return value, err
+		}
+		g.value, g.loaded = value, true
+		clear(g.delivered)
+	}
+	g.delivered[member] = true
+	return g.value, nil
+}
+
+func (g *LazyGroup[R]) join() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.members++
+	return g.members
+}
+
+// Join returns the loader of a LazyScalar or LazyRef that is a member of the group,
+// whose value is extracted from the value fetched by the group.
+func Join[R, T any](g *LazyGroup[R], extract func(R) (T, error)) func() (T, error) {
+	member := g.join()
+	return func() (T, error) {
+		value, err := g.fetch(member)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		return extract(value)
+	}
+}
+
+// JoinSlice returns the loader of a LazySlice that is a member of the group, as Join.
+// Loading a single item also fetches all the items.
+func JoinSlice[R any, T Identifiable[I], I comparable](g *LazyGroup[R], extract func(R) ([]T, error)) func(I) ([]T, error) {
+	load := Join(g, extract)
+	return func(id I) ([]T, error) {
+		values, err := load()
+		if err != nil {
+			return nil, err
+		}
+		var zero I
+		if id == zero {
+			return values, nil
+		}
+		for _, v := range values {
+			if v.ID() == id {
+				return []T{v}, nil
+			}
+		}
+		return nil, nil
+	}
+}
@@ -0,0 +1,68 @@
+package delta_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type personRow struct {
+	photo    []byte
+	metadata string
+	items    []*testEntity
+}
+
+func TestLazyGroup(t *testing.T) {
+	calls := 0
+	group := delta.NewLazyGroup(func() (personRow, error) {
+		calls++
+		return personRow{
+			photo:    []byte("photo"),
+			metadata: "png",
+			items:    []*testEntity{{id: "1", name: "entity1"}, {id: "2", name: "entity2"}},
+		}, nil
+	})
+	photo := delta.NewLazy(delta.Join(group, func(r personRow) ([]byte, error) { return r.photo, nil }))
+	metadata := delta.NewLazy(delta.Join(group, func(r personRow) (string, error) { return r.metadata, nil }))
+	items := delta.NewLazySlice(delta.JoinSlice(group, func(r personRow) ([]*testEntity, error) { return r.items, nil }))
+
+	m, err := metadata.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "png", m)
+	p, err := photo.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("photo"), p)
+	item, err := items.Get("2")
+	require.NoError(t, err)
+	assert.Equal(t, "entity2", item.name)
+	assert.Equal(t, 1, calls)
+
+	// a reload fetches fresh values
+	require.NoError(t, photo.Reload())
+	assert.Equal(t, 2, calls)
+	_, err = metadata.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestLazyGroup_Error(t *testing.T) {
+	errBoom := errors.New("connection refused")
+	fail := true
+	group := delta.NewLazyGroup(func() (personRow, error) {
+		if fail {
+			return personRow{}, errBoom
+		}
+		return personRow{metadata: "png"}, nil
+	})
+	metadata := delta.NewLazy(delta.Join(group, func(r personRow) (string, error) { return r.metadata, nil }))
+
+	_, err := metadata.Get()
+	require.ErrorIs(t, err, errBoom)
+	fail = false
+	m, err := metadata.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "png", m)
+}
@@ -0,0 +1,120 @@
+package delta
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+var ErrNoHistory = errors.New("no history at the given time")
+
+// WithHistory records the values taken over time, enabling reads as of a point in time.
+// Supported by scalars and LazySlice.
+func WithHistory() Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optHistory
+		o.history = true
+	})
+}
+
+// HistoryEntry is a value taken by a scalar at a point in time.
+type HistoryEntry[T any] struct {
+	At    time.Time
+	Value T
+}
+
+// updated must be called after every change of state of the scalar.
+func (v *LazyScalar[T]) updated() {
+	v.syncGauge()
+	if v.keepHistory && v.isSet {
+		v.history = append(v.history, HistoryEntry[T]{At: v.now(), Value: v.value})
+	}
+}
+
+// History returns the recorded values, oldest first.
+func (v *LazyScalar[T]) History() []HistoryEntry[T] {
+	return slices.Clone(v.history)
+}
+
+// GetAt returns the value as of t.
+// It returns ErrNoHistory if history recording is not enabled or if there was no value at t.
+func (v *LazyScalar[T]) GetAt(t time.Time) (T, error) {
+	var zero T
+	// first entry after t
+	i, _ := slices.BinarySearchFunc(v.history, t, func(e HistoryEntry[T], t time.Time) int {
+		if e.At.After(t) {
+			return 1
+		}
+		return -1
+	})
+	if i == 0 {
+		return zero, ErrNoHistory
+	}
+	return v.history[i-1].Value, nil
+}
+
+type sliceEvent[T Identifiable[I], I comparable] struct {
+	at      time.Time
+	id      I
+	item    Item[T, I]
+	deleted bool
+	reset   bool
+}
+
+func (s *LazySlice[T, I]) recordPut(id I, item Item[T, I]) {
+	if s.keepHistory {
+		s.history = append(s.history, sliceEvent[T, I]{at: s.now(), id: id, item: item})
+	}
+}
+
+func (s *LazySlice[T, I]) recordDelete(id I) {
+	if s.keepHistory {
+		s.history = append(s.history, sliceEvent[T, I]{at: s.now(), id: id, deleted: true})
+	}
+}
+
+// recordReset records the replacement of the whole cache by the current one.
+func (s *LazySlice[T, I]) recordReset() {
+	if !s.keepHistory {
+		return
+	}
+	now := s.now()
+	s.history = append(s.history, sliceEvent[T, I]{at: now, reset: true})
+	for id, item := range s.fetched.Entries() {
+		s.history = append(s.history, sliceEvent[T, I]{at: now, id: id, item: item})
+	}
+}
+
+// GetAllAt returns the items as of t, replaying the recorded history.
+// It returns ErrNoHistory if history recording is not enabled or if nothing was recorded until t.
+func (s *LazySlice[T, I]) GetAllAt(t time.Time) ([]T, error) {
+	if len(s.history) == 0 || s.history[0].at.After(t) {
+		return nil, ErrNoHistory
+	}
+
+	fetched := linkedmap.New[I, Item[T, I]]()
+	for _, e := range s.history {
+		if e.at.After(t) {
+			break
+		}
+		switch {
+		case e.reset:
+			fetched.Clear()
+		case e.deleted:
+			fetched.Delete(e.id)
+		default:
+			fetched.Put(e.id, e.item)
+		}
+	}
+
+	var values []T
+	for item := range fetched.Values() {
+		if item.status == Removed || item.status == Absent {
+			continue
+		}
+		values = append(values, item.value)
+	}
+	return values, nil
+}
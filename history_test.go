@@ -0,0 +1,102 @@
+package delta_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyScalar_GetAt(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+
+	scalar := delta.NewLazy(func() (string, error) {
+		return "v1", nil
+	}, delta.WithClock(clock), delta.WithHistory())
+
+	_, err := scalar.GetAt(start)
+	require.ErrorIs(t, err, delta.ErrNoHistory)
+
+	_, err = scalar.Get()
+	require.NoError(t, err)
+	clock.Advance(time.Minute)
+	scalar.Set("v2")
+	clock.Advance(time.Minute)
+	scalar.Set("v3")
+
+	tests := []struct {
+		at       time.Time
+		expected string
+	}{
+		{start, "v1"},
+		{start.Add(30 * time.Second), "v1"},
+		{start.Add(time.Minute), "v2"},
+		{start.Add(90 * time.Second), "v2"},
+		{start.Add(time.Hour), "v3"},
+	}
+	for _, tt := range tests {
+		value, err := scalar.GetAt(tt.at)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, value)
+	}
+
+	_, err = scalar.GetAt(start.Add(-time.Second))
+	require.ErrorIs(t, err, delta.ErrNoHistory)
+	assert.Len(t, scalar.History(), 3)
+}
+
+func TestLazyScalar_GetAt_Disabled(t *testing.T) {
+	scalar := delta.NewLazy(func() (string, error) {
+		return "v1", nil
+	})
+	_, err := scalar.Get()
+	require.NoError(t, err)
+
+	_, err = scalar.GetAt(time.Now())
+	require.ErrorIs(t, err, delta.ErrNoHistory)
+	assert.Empty(t, scalar.History())
+}
+
+func TestLazySlice_GetAllAt(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}), delta.WithClock(clock), delta.WithHistory())
+
+	_, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	clock.Advance(time.Minute)
+	lazySlice.Remove("1")
+	lazySlice.Set(&testEntity{id: "3", name: "entity3"})
+	clock.Advance(time.Minute)
+	lazySlice.SetAll([]*testEntity{{id: "4", name: "entity4"}})
+
+	_, err = lazySlice.GetAllAt(start.Add(-time.Second))
+	require.ErrorIs(t, err, delta.ErrNoHistory)
+
+	values, err := lazySlice.GetAllAt(start)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, ids(values))
+
+	values, err = lazySlice.GetAllAt(start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, ids(values))
+
+	values, err = lazySlice.GetAllAt(start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4"}, ids(values))
+}
+
+func ids(values []*testEntity) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		result = append(result, v.ID())
+	}
+	return result
+}
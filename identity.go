@@ -0,0 +1,72 @@
+package delta
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// IdentityMap keeps the aggregates loaded in a unit of work by ID, so that loading an aggregate again
+// returns the same instance, with its pending changes. It is meant to be used within a request scope.
+type IdentityMap[K comparable, T any] struct {
+	mu    sync.Mutex
+	fn    func(id K) (T, error)
+	roots *linkedmap.Map[K, T]
+}
+
+// NewIdentityMap creates an identity map that loads the aggregates missing from it with fn.
+func NewIdentityMap[K comparable, T any](fn func(id K) (T, error)) *IdentityMap[K, T] {
+	return &IdentityMap[K, T]{
+		fn:    fn,
+		roots: linkedmap.New[K, T](),
+	}
+}
+
+// Get returns the aggregate with the ID, loading it only if it is not in the map.
+// Load errors are not kept, so the next Get loads it again.
+func (m *IdentityMap[K, T]) Get(id K) (T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if root, ok := m.roots.Get(id); ok {
+		return root, nil
+	}
+	root, err := m.fn(id)
+	if err != nil {
+		return root, err
+	}
+	m.roots.Put(id, root)
+	return root, nil
+}
+
+// Add adds an aggregate to the map (eg: a new one), replacing any aggregate with the same ID.
+func (m *IdentityMap[K, T]) Add(id K, root T) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.roots.Put(id, root)
+}
+
+// Remove removes the aggregate with the ID from the map (eg: a deleted one), returning true if it was there.
+func (m *IdentityMap[K, T]) Remove(id K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.roots.Delete(id)
+	return ok
+}
+
+// All iterates over the aggregates in the map, in the order they were added.
+func (m *IdentityMap[K, T]) All() iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		m.mu.Lock()
+		roots := copyMap(m.roots)
+		m.mu.Unlock()
+		for k, v := range roots.Entries() {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
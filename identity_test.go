@@ -0,0 +1,50 @@
+package delta_test
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityMap(t *testing.T) {
+	errLoad := errors.New("load failed")
+	var loads []string
+	identities := delta.NewIdentityMap(func(id string) (*testEntity, error) {
+		loads = append(loads, id)
+		if id == "bad" {
+			return nil, errLoad
+		}
+		return &testEntity{id: id, name: "name_" + id}, nil
+	})
+
+	e1, err := identities.Get("1")
+	require.NoError(t, err)
+	e1.name = "changed"
+	again, err := identities.Get("1")
+	require.NoError(t, err)
+	assert.Same(t, e1, again)
+	assert.Equal(t, "changed", again.name)
+
+	_, err = identities.Get("bad")
+	require.ErrorIs(t, err, errLoad)
+	_, err = identities.Get("bad")
+	require.ErrorIs(t, err, errLoad)
+	assert.Equal(t, []string{"1", "bad", "bad"}, loads)
+
+	e2 := &testEntity{id: "2", name: "new"}
+	identities.Add("2", e2)
+	got, err := identities.Get("2")
+	require.NoError(t, err)
+	assert.Same(t, e2, got)
+	assert.Equal(t, map[string]*testEntity{"1": e1, "2": e2}, maps.Collect(identities.All()))
+
+	assert.True(t, identities.Remove("1"))
+	assert.False(t, identities.Remove("1"))
+	_, err = identities.Get("1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "bad", "bad", "1"}, loads)
+}
@@ -0,0 +1,107 @@
+package delta
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+var ErrUnknownIndex = errors.New("unknown index")
+
+type indexDef struct {
+	name string
+	key  any // func(T) any
+}
+
+// WithIndex indexes the items of a LazySlice of T by an alternative key (eg: the plate of a car), to be looked up with GetByIndex.
+// Keys are indexed when the items are cached or set, so they must not be changed in place.
+// It panics when used with a collection of a different type.
+func WithIndex[T any, K comparable](name string, key func(T) K) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optIndex
+		o.indexes = append(o.indexes, indexDef{name: name, key: func(v T) any { return key(v) }})
+	})
+}
+
+// sliceIndex keeps the IDs of the items per key.
+// Entries may be stale, eg: after accepting the removal of an item, so they are checked on lookup.
+type sliceIndex[T Identifiable[I], I comparable] struct {
+	key func(T) any
+	ids map[any][]I
+}
+
+func (x *sliceIndex[T, I]) add(id I, value T) {
+	k := x.key(value)
+	if !slices.Contains(x.ids[k], id) {
+		x.ids[k] = append(x.ids[k], id)
+	}
+}
+
+func (x *sliceIndex[T, I]) remove(id I, value T) {
+	k := x.key(value)
+	x.ids[k] = slices.DeleteFunc(x.ids[k], func(i I) bool { return i == id })
+	if len(x.ids[k]) == 0 {
+		delete(x.ids, k)
+	}
+}
+
+func indexesFor[T Identifiable[I], I comparable](opts options) map[string]*sliceIndex[T, I] {
+	if len(opts.indexes) == 0 {
+		return nil
+	}
+	indexes := make(map[string]*sliceIndex[T, I], len(opts.indexes))
+	for _, def := range opts.indexes {
+		key, ok := def.key.(func(T) any)
+		if !ok {
+			panic(fmt.Sprintf("delta: WithIndex %q used with a collection of %s", def.name, reflect.TypeFor[T]()))
+		}
+		indexes[def.name] = &sliceIndex[T, I]{key: key, ids: map[any][]I{}}
+	}
+	return indexes
+}
+
+// GetByIndex loads all the items, if not loaded yet, and returns the ones whose key, in the named index, is key.
+// The key must be of the type returned by the key function of WithIndex.
+func (s *LazySlice[T, I]) GetByIndex(name string, key any) ([]T, error) {
+	x, ok := s.indexes[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIndex, name)
+	}
+	if _, err := s.GetAll(); err != nil {
+		return nil, err
+	}
+	var values []T
+	for _, id := range x.ids[key] {
+		item, ok := s.fetched.Get(id)
+		if !ok || item.status == Removed || item.status == Absent || x.key(item.value) != key {
+			continue
+		}
+		values = append(values, item.value)
+	}
+	return values, nil
+}
+
+// indexPut updates the indexes with an item put in the cache, replacing old.
+func (s *LazySlice[T, I]) indexPut(id I, item Item[T, I], old Item[T, I], existed bool) {
+	for _, x := range s.indexes {
+		if existed && old.status != Removed && old.status != Absent {
+			x.remove(id, old.value)
+		}
+		if item.status != Removed && item.status != Absent {
+			x.add(id, item.value)
+		}
+	}
+}
+
+// reindex rebuilds the indexes from the cache.
+func (s *LazySlice[T, I]) reindex() {
+	for _, x := range s.indexes {
+		clear(x.ids)
+		for id, item := range s.fetched.Entries() {
+			if item.status != Removed && item.status != Absent {
+				x.add(id, item.value)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithIndex(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "a"},
+		{id: "2", name: "b"},
+		{id: "3", name: "a"},
+	}), delta.WithIndex("name", func(e *testEntity) string { return e.name }))
+
+	found, err := items.GetByIndex("name", "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, ids(found))
+
+	items.Set(&testEntity{id: "1", name: "c"})
+	items.Remove("3")
+	items.Set(&testEntity{id: "4", name: "b"})
+
+	found, err = items.GetByIndex("name", "a")
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	found, err = items.GetByIndex("name", "b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "4"}, ids(found))
+
+	// discarding the changes restores the indexed items
+	items.DiscardChanges()
+	found, err = items.GetByIndex("name", "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, ids(found))
+
+	_, err = items.GetByIndex("plate", "a")
+	require.ErrorIs(t, err, delta.ErrUnknownIndex)
+}
+
+func TestWithIndex_Slice(t *testing.T) {
+	items := delta.NewSlice([]*testEntity{{id: "1", name: "a"}, {id: "2", name: "b"}},
+		delta.WithIndex("name", func(e *testEntity) string { return e.name }))
+
+	found, err := items.GetByIndex("name", "b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, ids(found))
+
+	clone := items.Clone(nil)
+	clone.SetAll([]*testEntity{{id: "3", name: "b"}})
+	found, err = clone.GetByIndex("name", "b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3"}, ids(found))
+	assert.Equal(t, []string{"1", "2"}, ids(slices.Collect(items.GetAll())))
+}
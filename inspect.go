@@ -0,0 +1,119 @@
+package delta
+
+import (
+	"iter"
+)
+
+// IsLoaded returns true if the value is available without calling the loader.
+func (v *LazyScalar[T]) IsLoaded() bool {
+	return v.isSet
+}
+
+// IsDirty returns true if the value has a pending change.
+func (v *LazyScalar[T]) IsDirty() bool {
+	return v.isDirty
+}
+
+// IsLoaded returns true if all the items are available without calling the loader.
+func (s *LazySlice[T, I]) IsLoaded() bool {
+	return s.isSet
+}
+
+// IsDirty returns true if the collection was reset or has pending changes.
+func (s *LazySlice[T, I]) IsDirty() bool {
+	return s.isReset || hasChanges(s.fetched.Values(), Item[T, I].effectiveStatus)
+}
+
+// IsLoaded returns true if all the keys are available without calling the loader.
+func (d *DynamicFields) IsLoaded() bool {
+	return d.isSet
+}
+
+// IsDirty returns true if the fields were cleared or have pending changes.
+func (d *DynamicFields) IsDirty() bool {
+	return d.isReset || hasChanges(d.fetched.Values(), func(item mapItem[any]) Status {
+		return item.status
+	})
+}
+
+// IsLoaded returns true if all the keys are available without calling the loader.
+func (m *LazyMap[K, V]) IsLoaded() bool {
+	return m.isSet
+}
+
+// IsDirty returns true if the map was cleared or has pending changes.
+func (m *LazyMap[K, V]) IsDirty() bool {
+	return m.isReset || hasChanges(m.fetched.Values(), func(item mapItem[V]) Status {
+		return item.status
+	})
+}
+
+// IsLoaded returns true if all the members are available without calling the loader.
+func (s *LazySet[T]) IsLoaded() bool {
+	return s.isSet
+}
+
+// IsDirty returns true if the set was cleared or has pending changes.
+func (s *LazySet[T]) IsDirty() bool {
+	return s.isReset || hasChanges(s.fetched.Values(), func(item setItem) Status {
+		return item.status
+	})
+}
+
+// IsLoaded returns true if the reference is available without calling the loader.
+func (r *LazyRef[T, I]) IsLoaded() bool {
+	return r.isSet || r.isDirty
+}
+
+// IsDirty returns true if the reference has a pending change.
+func (r *LazyRef[T, I]) IsDirty() bool {
+	return r.Change() != nil
+}
+
+// IsLoaded returns true if the items are available without calling the loader.
+func (l *LazyList[T, I]) IsLoaded() bool {
+	return l.isSet
+}
+
+// IsDirty returns true if the list has pending changes, including moves.
+func (l *LazyList[T, I]) IsDirty() bool {
+	return len(l.listChanges()) > 0
+}
+
+func (v *LazyScalar[T]) changes() any {
+	return v.Change()
+}
+
+func (s *LazySlice[T, I]) changes() any {
+	return s.Changes()
+}
+
+func (d *DynamicFields) changes() any {
+	return d.Changes()
+}
+
+func (m *LazyMap[K, V]) changes() any {
+	return m.Changes()
+}
+
+func (s *LazySet[T]) changes() any {
+	return s.Changes()
+}
+
+func (r *LazyRef[T, I]) changes() any {
+	return r.Change()
+}
+
+func (l *LazyList[T, I]) changes() any {
+	return l.Changes()
+}
+
+func hasChanges[V any](items iter.Seq[V], status func(V) Status) bool {
+	for item := range items {
+		switch status(item) {
+		case Added, Modified, Removed:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyScalar_IsLoaded_IsDirty(t *testing.T) {
+	scalar := delta.NewLazy(func() (string, error) {
+		return "loaded", nil
+	})
+	assert.False(t, scalar.IsLoaded())
+	assert.False(t, scalar.IsDirty())
+
+	_, err := scalar.Get()
+	require.NoError(t, err)
+	assert.True(t, scalar.IsLoaded())
+	assert.False(t, scalar.IsDirty())
+
+	scalar.Set("new value")
+	assert.True(t, scalar.IsDirty())
+	scalar.AcceptChanges()
+	assert.False(t, scalar.IsDirty())
+}
+
+func TestLazySlice_IsLoaded_IsDirty(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	assert.False(t, lazySlice.IsLoaded())
+
+	// loading a single item does not load the collection
+	_, err := lazySlice.Get("1")
+	require.NoError(t, err)
+	assert.False(t, lazySlice.IsLoaded())
+	assert.False(t, lazySlice.IsDirty())
+
+	_, err = lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.True(t, lazySlice.IsLoaded())
+	assert.False(t, lazySlice.IsDirty())
+
+	lazySlice.Remove("2")
+	assert.True(t, lazySlice.IsDirty())
+	lazySlice.DiscardChanges()
+	assert.False(t, lazySlice.IsDirty())
+
+	lazySlice.Clear()
+	assert.True(t, lazySlice.IsDirty())
+}
+
+func TestLazyRef_IsDirty(t *testing.T) {
+	address := delta.NewNilRef[*testEntity]()
+	assert.True(t, address.IsLoaded())
+	address.Set(&testEntity{id: "1"})
+	assert.True(t, address.IsDirty())
+	address.Remove()
+	assert.False(t, address.IsDirty())
+}
@@ -0,0 +1,223 @@
+package delta
+
+import (
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+var ErrNothingToRedo = errors.New("nothing to redo")
+
+// mutations notifies the mutations of a field made by its methods, once per outermost call.
+// Every mutating method must start with:
+//
+//	x.mut.begin()
+//	defer x.mut.end()
+type mutations struct {
+	before func() // called before the mutation
+	depth  int
+	logger *slog.Logger // see WithLogger
+	field  string
+	actor  func() (actor, reason string) // see WithActor
+	frozen bool                          // see Freeze
+}
+
+// begin panics with ErrFrozen if the field is frozen, for the mutating methods without an error result.
+func (m *mutations) begin() {
+	if err := m.writable(); err != nil {
+		panic(err)
+	}
+	if m.depth == 0 && m.before != nil {
+		m.before()
+	}
+	m.depth++
+}
+
+func (m *mutations) end() {
+	m.depth--
+}
+
+// WithJournal records the mutations of the registered fields made with their methods (eg: Set, Remove, SetAll),
+// so that they can be stepped backwards and forwards with Undo and Redo.
+// Commands executed with Execute are journaled as the mutations they make.
+func WithJournal() TrackerOption {
+	return trackerOptionFunc(func(t *Tracker) {
+		t.journal = &journal{}
+	})
+}
+
+type journalEntry struct {
+	field  Field
+	before any // state of the field before the mutation
+	after  any // state of the field after the mutation, once undone
+}
+
+type journal struct {
+	entries []journalEntry
+	done    int // entries[:done] are done and entries[done:] are undone
+}
+
+// record records the state of the field before a mutation, forgetting the undone mutations.
+func (j *journal) record(field Field) {
+	j.entries = append(j.entries[:j.done], journalEntry{field: field, before: field.snapshot()})
+	j.done++
+}
+
+func (j *journal) undo() error {
+	if j.done == 0 {
+		return ErrNothingToUndo
+	}
+	j.done--
+	e := &j.entries[j.done]
+	e.after = e.field.snapshot()
+	e.field.restoreSnapshot(e.before)
+	return nil
+}
+
+func (j *journal) redo() error {
+	if j.done == len(j.entries) {
+		return ErrNothingToRedo
+	}
+	e := &j.entries[j.done]
+	e.before = e.field.snapshot()
+	e.field.restoreSnapshot(e.after)
+	j.done++
+	return nil
+}
+
+func (v *LazyScalar[T]) observe(before func())   { v.mut.before = before }
+func (s *LazySlice[T, I]) observe(before func()) { s.mut.before = before }
+func (d *DynamicFields) observe(before func())   { d.mut.before = before }
+func (m *LazyMap[K, V]) observe(before func())   { m.mut.before = before }
+func (s *LazySet[T]) observe(before func())      { s.mut.before = before }
+func (r *LazyRef[T, I]) observe(before func())   { r.mut.before = before }
+func (l *LazyList[T, I]) observe(before func())  { l.mut.before = before }
+
+func (v *LazyScalar[T]) snapshot() any {
+	return v.Snapshot()
+}
+
+func (v *LazyScalar[T]) restoreSnapshot(s any) {
+	v.Restore(s.(ScalarSnapshot[T]))
+}
+
+func (s *LazySlice[T, I]) snapshot() any {
+	return s.Snapshot()
+}
+
+func (s *LazySlice[T, I]) restoreSnapshot(snap any) {
+	s.Restore(snap.(SliceSnapshot[T, I]))
+}
+
+// collectionState is the state of a map like collection.
+type collectionState[K comparable, V any] struct {
+	isSet       bool
+	isReset     bool
+	fetched     *linkedmap.Map[K, V]
+	beforeReset *linkedmap.Map[K, V]
+	wasSet      bool
+}
+
+func newCollectionState[K comparable, V any](isSet, isReset bool, fetched, beforeReset *linkedmap.Map[K, V], wasSet bool) collectionState[K, V] {
+	s := collectionState[K, V]{isSet: isSet, isReset: isReset, fetched: copyMap(fetched), wasSet: wasSet}
+	if beforeReset != nil {
+		s.beforeReset = copyMap(beforeReset)
+	}
+	return s
+}
+
+// restore returns copies of the maps, so that the state can be restored more than once.
+func (s collectionState[K, V]) restore() (fetched, beforeReset *linkedmap.Map[K, V]) {
+	fetched = copyMap(s.fetched)
+	if s.beforeReset != nil {
+		beforeReset = copyMap(s.beforeReset)
+	}
+	return fetched, beforeReset
+}
+
+func (d *DynamicFields) snapshot() any {
+	return newCollectionState(d.isSet, d.isReset, d.fetched, d.beforeReset, d.wasSet)
+}
+
+func (d *DynamicFields) restoreSnapshot(snap any) {
+	s := snap.(collectionState[string, mapItem[any]])
+	d.isSet, d.isReset, d.wasSet = s.isSet, s.isReset, s.wasSet
+	d.fetched, d.beforeReset = s.restore()
+	d.recount()
+}
+
+func (m *LazyMap[K, V]) snapshot() any {
+	return newCollectionState(m.isSet, m.isReset, m.fetched, m.beforeReset, m.wasSet)
+}
+
+func (m *LazyMap[K, V]) restoreSnapshot(snap any) {
+	s := snap.(collectionState[K, mapItem[V]])
+	m.isSet, m.isReset, m.wasSet = s.isSet, s.isReset, s.wasSet
+	m.fetched, m.beforeReset = s.restore()
+	m.recount()
+}
+
+func (s *LazySet[T]) snapshot() any {
+	return newCollectionState(s.isSet, s.isReset, s.fetched, s.beforeReset, s.wasSet)
+}
+
+func (s *LazySet[T]) restoreSnapshot(snap any) {
+	st := snap.(collectionState[T, setItem])
+	s.isSet, s.isReset, s.wasSet = st.isSet, st.isReset, st.wasSet
+	s.fetched, s.beforeReset = st.restore()
+	s.recount()
+}
+
+type refState[T any, I comparable] struct {
+	isSet      bool
+	origExists bool
+	origID     I
+	orig       T
+	value      T
+	exists     bool
+	isDirty    bool
+}
+
+func (r *LazyRef[T, I]) snapshot() any {
+	return refState[T, I]{
+		isSet:      r.isSet,
+		origExists: r.origExists,
+		origID:     r.origID,
+		orig:       r.orig,
+		value:      r.value,
+		exists:     r.exists,
+		isDirty:    r.isDirty,
+	}
+}
+
+func (r *LazyRef[T, I]) restoreSnapshot(snap any) {
+	s := snap.(refState[T, I])
+	r.isSet, r.origExists, r.origID, r.orig = s.isSet, s.origExists, s.origID, s.orig
+	r.value, r.exists, r.isDirty = s.value, s.exists, s.isDirty
+	r.syncGauge()
+}
+
+type listState[T any, I comparable] struct {
+	isSet   bool
+	fetched []T
+	items   []T
+	set     map[I]bool
+}
+
+func (l *LazyList[T, I]) snapshot() any {
+	return listState[T, I]{
+		isSet:   l.isSet,
+		fetched: slices.Clone(l.fetched),
+		items:   slices.Clone(l.items),
+		set:     maps.Clone(l.set),
+	}
+}
+
+func (l *LazyList[T, I]) restoreSnapshot(snap any) {
+	s := snap.(listState[T, I])
+	l.isSet, l.fetched, l.items, l.set = s.isSet, slices.Clone(s.fetched), slices.Clone(s.items), maps.Clone(s.set)
+	l.syncGauge()
+}
@@ -0,0 +1,62 @@
+package delta_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_Journal(t *testing.T) {
+	name := delta.New("John")
+	cars := delta.NewSlice([]*testEntity{{id: "1", name: "car1"}})
+	tags := delta.NewLazySet(func(string) ([]string, error) { return []string{"a"}, nil })
+	settings := delta.NewLazyMap(func(string) (map[string]int, error) { return map[string]int{"x": 1}, nil })
+	address := delta.NewRef(&testEntity{id: "a1", name: "address1"})
+	tracker := delta.NewTracker(delta.WithJournal())
+	tracker.Register("name", name)
+	tracker.Register("cars", cars)
+	tracker.Register("tags", tags)
+	tracker.Register("settings", settings)
+	tracker.Register("address", address)
+
+	name.Set("Jane")
+	require.NoError(t, cars.ReplaceAll([]*testEntity{{id: "2", name: "car2"}}))
+	tags.Add("b")
+	settings.Put("y", 2)
+	address.Remove()
+
+	for range 5 {
+		require.NoError(t, tracker.Undo())
+	}
+	assert.ErrorIs(t, tracker.Undo(), delta.ErrNothingToUndo)
+	assert.False(t, tracker.IsDirty())
+	assert.Equal(t, "John", name.Get())
+	assert.Equal(t, []string{"1"}, ids(slices.Collect(cars.GetAll())))
+
+	for range 5 {
+		require.NoError(t, tracker.Redo())
+	}
+	assert.ErrorIs(t, tracker.Redo(), delta.ErrNothingToRedo)
+	assert.Equal(t, "Jane", name.Get())
+	assert.Equal(t, []string{"2"}, ids(slices.Collect(cars.GetAll())))
+	members, err := tags.GetAll()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, slices.Collect(members))
+	all, err := settings.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"x": 1, "y": 2}, maps.Collect(all))
+	ref, err := address.Get()
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	assert.Nil(t, ref)
+
+	// a new mutation forgets the undone ones
+	require.NoError(t, tracker.Undo())
+	name.Set("Joe")
+	assert.ErrorIs(t, tracker.Redo(), delta.ErrNothingToRedo)
+	require.NoError(t, tracker.Undo())
+	assert.Equal(t, "Jane", name.Get())
+}
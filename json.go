@@ -0,0 +1,274 @@
+package delta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var statusNames = [...]string{
+	Unchanged: "unchanged",
+	Added:     "added",
+	Removed:   "removed",
+	Modified:  "modified",
+	Absent:    "absent",
+}
+
+// String returns the name of the status (eg: "added"), to be readable in logs and persisted change tables.
+func (s Status) String() string {
+	if s < 0 || int(s) >= len(statusNames) {
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+	return statusNames[s]
+}
+
+// MarshalText encodes the status by its name, so that it is encoded in JSON as a string.
+func (s Status) MarshalText() ([]byte, error) {
+	if s < 0 || int(s) >= len(statusNames) {
+		return nil, fmt.Errorf("%w: status %d", ErrInvalidValue, int(s))
+	}
+	return []byte(statusNames[s]), nil
+}
+
+func (s *Status) UnmarshalText(text []byte) error {
+	status, err := ParseStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
+
+// ParseStatus returns the status with the name returned by Status.String (eg: "added").
+func ParseStatus(name string) (Status, error) {
+	i := slices.Index(statusNames[:], name)
+	if i < 0 {
+		return 0, fmt.Errorf("%w: status %q", ErrInvalidValue, name)
+	}
+	return Status(i), nil
+}
+
+type changeJSON[T any] struct {
+	Value T                `json:"value"`
+	Old   *json.RawMessage `json:"old,omitempty"`
+	Meta  *ChangeMeta      `json:"meta,omitempty"`
+}
+
+// metaOrNil returns nil for no metadata, to omit it.
+func metaOrNil(m ChangeMeta) *ChangeMeta {
+	if m.IsZero() {
+		return nil
+	}
+	return &m
+}
+
+func metaOrZero(m *ChangeMeta) ChangeMeta {
+	if m == nil {
+		return ChangeMeta{}
+	}
+	return *m
+}
+
+// MarshalJSON encodes the change as {"value": ..., "old": ..., "meta": ...},
+// without old if there is no old value and without meta if there is no metadata.
+func (c Change[T]) MarshalJSON() ([]byte, error) {
+	old, err := rawOld(c.Old, c.HasOld)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(changeJSON[T]{Value: c.Value, Old: old, Meta: metaOrNil(c.Meta)})
+}
+
+func (c *Change[T]) UnmarshalJSON(data []byte) error {
+	var v changeJSON[T]
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	var err error
+	*c = Change[T]{Value: v.Value, Null: isNull(v.Value), Meta: metaOrZero(v.Meta)}
+	c.Old, c.HasOld, err = decodeOld[T](v.Old)
+	return err
+}
+
+type sliceChangeJSON[I comparable, T any] struct {
+	ID     I                `json:"id"`
+	Status Status           `json:"status"`
+	Value  *T               `json:"value,omitempty"`
+	Old    *json.RawMessage `json:"old,omitempty"`
+	Meta   *ChangeMeta      `json:"meta,omitempty"`
+}
+
+// MarshalJSON encodes the change as {"id": ..., "status": ..., "value": ..., "old": ..., "meta": ...},
+// without value for removed items, without old if there is no old value and without meta if there is no metadata.
+func (c SliceChange[I, T]) MarshalJSON() ([]byte, error) {
+	old, err := rawOld(c.Old, c.HasOld)
+	if err != nil {
+		return nil, err
+	}
+	v := sliceChangeJSON[I, T]{ID: c.ID, Status: c.Status, Old: old, Meta: metaOrNil(c.Meta)}
+	if c.Status != Removed {
+		v.Value = &c.Value
+	}
+	return json.Marshal(v)
+}
+
+func (c *SliceChange[I, T]) UnmarshalJSON(data []byte) error {
+	var v sliceChangeJSON[I, T]
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	var err error
+	*c = SliceChange[I, T]{ID: v.ID, Status: v.Status, Meta: metaOrZero(v.Meta)}
+	if v.Value != nil {
+		c.Value = *v.Value
+	}
+	c.Old, c.HasOld, err = decodeOld[T](v.Old)
+	return err
+}
+
+type changesJSON[T Identifiable[I], I comparable] struct {
+	Reset bool                `json:"reset"`
+	Items []SliceChange[I, T] `json:"items"`
+}
+
+// MarshalJSON encodes the changes as {"reset": ..., "items": [...]}.
+func (c Changes[T, I]) MarshalJSON() ([]byte, error) {
+	v := changesJSON[T, I]{Reset: c.Reset, Items: []SliceChange[I, T]{}}
+	if c.Items != nil {
+		v.Items = slices.AppendSeq(v.Items, c.Items)
+	}
+	return json.Marshal(v)
+}
+
+func (c *Changes[T, I]) UnmarshalJSON(data []byte) error {
+	var v changesJSON[T, I]
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = Changes[T, I]{Reset: v.Reset, Items: slices.Values(v.Items)}
+	return nil
+}
+
+func rawOld[T any](old T, hasOld bool) (*json.RawMessage, error) {
+	if !hasOld {
+		return nil, nil
+	}
+	b, err := json.Marshal(old)
+	if err != nil {
+		return nil, err
+	}
+	raw := json.RawMessage(b)
+	return &raw, nil
+}
+
+func decodeOld[T any](raw *json.RawMessage) (T, bool, error) {
+	var old T
+	if raw == nil {
+		return old, false, nil
+	}
+	if err := json.Unmarshal(*raw, &old); err != nil {
+		return old, false, err
+	}
+	return old, true, nil
+}
+
+// ErrNotLoaded is returned when encoding a field that is not loaded, with ErrorIfUnloaded.
+var ErrNotLoaded = errors.New("not loaded")
+
+// MarshalMode is how a scalar or collection that is not loaded is encoded as JSON.
+type MarshalMode int
+
+const (
+	// SkipUnloaded encodes null or, if there are pending changes, the values known so far (eg: the items set without loading).
+	SkipUnloaded MarshalMode = iota
+	// LoadOnMarshal loads the value.
+	LoadOnMarshal
+	// ErrorIfUnloaded fails with ErrNotLoaded.
+	ErrorIfUnloaded
+)
+
+// WithMarshalMode sets how a scalar or LazySlice that is not loaded is encoded as JSON. Defaults to SkipUnloaded.
+func WithMarshalMode(mode MarshalMode) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optMarshalMode
+		o.marshal = mode
+	})
+}
+
+// unloaded returns the encoding of a field that is not loaded, or nil if the loaded values are to be encoded.
+// The pending changes of a dirty field are never skipped.
+func unloaded(mode MarshalMode, dirty bool, load func() error) ([]byte, error) {
+	switch {
+	case mode == LoadOnMarshal:
+		return nil, load()
+	case mode == ErrorIfUnloaded:
+		return nil, ErrNotLoaded
+	case dirty:
+		return nil, nil
+	default:
+		return []byte("null"), nil
+	}
+}
+
+// MarshalJSON encodes the value. If it is not loaded, it is encoded according to the marshal mode (see WithMarshalMode).
+func (v *LazyScalar[T]) MarshalJSON() ([]byte, error) {
+	if !v.isSet {
+		b, err := unloaded(v.marshal, v.isDirty, func() error {
+			_, err := v.Get()
+			return err
+		})
+		if b != nil || err != nil {
+			return b, err
+		}
+	}
+	return json.Marshal(v.value)
+}
+
+// UnmarshalJSON sets the decoded value, marking it as changed. A null is ignored.
+func (v *LazyScalar[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if v.clock == nil {
+		// zero value, eg: allocated by the decoder
+		v.init(nil, newOptions(nil))
+	}
+	v.Set(value)
+	return nil
+}
+
+// MarshalJSON encodes the items. If not all are loaded, they are encoded according to the marshal mode (see WithMarshalMode).
+func (s *LazySlice[T, I]) MarshalJSON() ([]byte, error) {
+	if !s.isSet {
+		b, err := unloaded(s.marshal, s.IsDirty(), func() error {
+			_, err := s.GetAll()
+			return err
+		})
+		if b != nil || err != nil {
+			return b, err
+		}
+	}
+	return json.Marshal(slices.AppendSeq([]T{}, s.ordered(filterRemoved(s.fetched.Values()))))
+}
+
+// UnmarshalJSON replaces all the items with the decoded ones, as SetAll. A null is ignored.
+func (s *LazySlice[T, I]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if s.fetched == nil {
+		// zero value, eg: allocated by the decoder
+		s.init(nil, newOptions(nil))
+	}
+	s.SetAll(values)
+	return nil
+}
@@ -0,0 +1,152 @@
+package delta_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonEntity struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+func (e jsonEntity) ID() string {
+	return e.Key
+}
+
+func TestChanges_JSON(t *testing.T) {
+	lazySlice := delta.NewSlice([]jsonEntity{
+		{Key: "1", Name: "entity1"},
+		{Key: "2", Name: "entity2"},
+	})
+	lazySlice.Set(jsonEntity{Key: "1", Name: "entity1_new"})
+	lazySlice.Remove("2")
+	lazySlice.Set(jsonEntity{Key: "3", Name: "entity3"})
+
+	b, err := json.Marshal(lazySlice.Changes())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"reset": false,
+		"items": [
+			{"id": "1", "status": "modified", "value": {"key": "1", "name": "entity1_new"}, "old": {"key": "1", "name": "entity1"}},
+			{"id": "2", "status": "removed", "old": {"key": "2", "name": "entity2"}},
+			{"id": "3", "status": "added", "value": {"key": "3", "name": "entity3"}}
+		]
+	}`, string(b))
+
+	var decoded delta.Changes[jsonEntity, string]
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.False(t, decoded.Reset)
+	assert.Equal(t, slices.Collect(lazySlice.Changes().Items), slices.Collect(decoded.Items))
+}
+
+func TestChange_JSON(t *testing.T) {
+	scalar := delta.New(10)
+	scalar.Set(20)
+
+	b, err := json.Marshal(scalar.Change())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value": 20, "old": 10}`, string(b))
+
+	var decoded delta.Change[int]
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, *scalar.Change(), decoded)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"value": 5}`), &decoded))
+	assert.Equal(t, delta.Change[int]{Value: 5}, decoded)
+}
+
+func TestStatus_Text(t *testing.T) {
+	var s delta.Status
+	require.NoError(t, s.UnmarshalText([]byte("modified")))
+	assert.Equal(t, delta.Modified, s)
+	assert.Equal(t, "modified", s.String())
+	require.ErrorIs(t, s.UnmarshalText([]byte("bogus")), delta.ErrInvalidValue)
+
+	for _, status := range []delta.Status{delta.Unchanged, delta.Added, delta.Removed, delta.Modified, delta.Absent} {
+		parsed, err := delta.ParseStatus(status.String())
+		require.NoError(t, err)
+		assert.Equal(t, status, parsed)
+	}
+	_, err := delta.ParseStatus("Added")
+	require.ErrorIs(t, err, delta.ErrInvalidValue)
+}
+
+type personDTO struct {
+	Name *delta.LazyScalar[string]            `json:"name"`
+	Cars *delta.LazySlice[jsonEntity, string] `json:"cars"`
+	Age  *delta.Scalar[int]                   `json:"age,omitempty"`
+}
+
+func TestLazyTypes_JSON(t *testing.T) {
+	dto := personDTO{
+		Name: delta.NewLazy(func() (string, error) { return "Paulo", nil }),
+		Cars: delta.NewLazySlice(func(id string) ([]jsonEntity, error) {
+			return []jsonEntity{{Key: "1", Name: "car1"}}, nil
+		}),
+	}
+	b, err := json.Marshal(dto)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": null, "cars": null}`, string(b))
+
+	dto.Name.MustGet()
+	dto.Cars.MustGetAll()
+	b, err = json.Marshal(dto)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "Paulo", "cars": [{"key": "1", "name": "car1"}]}`, string(b))
+
+	var decoded personDTO
+	require.NoError(t, json.Unmarshal([]byte(`{"name": "Pedro", "cars": [{"key": "2", "name": "car2"}], "age": 30}`), &decoded))
+	assert.Equal(t, "Pedro", decoded.Name.MustGet())
+	assert.True(t, decoded.Name.IsDirty())
+	assert.True(t, decoded.Cars.IsReset())
+	assert.Equal(t, []jsonEntity{{Key: "2", Name: "car2"}}, slices.Collect(decoded.Cars.MustGetAll()))
+	assert.Equal(t, 30, decoded.Age.Get())
+	assert.True(t, decoded.Age.IsDirty())
+}
+
+func TestWithMarshalMode(t *testing.T) {
+	load := func() (string, error) { return "Paulo", nil }
+
+	b, err := json.Marshal(delta.NewLazy(load, delta.WithMarshalMode(delta.LoadOnMarshal)))
+	require.NoError(t, err)
+	assert.JSONEq(t, `"Paulo"`, string(b))
+
+	_, err = json.Marshal(delta.NewLazy(load, delta.WithMarshalMode(delta.ErrorIfUnloaded)))
+	require.ErrorIs(t, err, delta.ErrNotLoaded)
+
+	cars := delta.NewLazySlice(func(id string) ([]jsonEntity, error) {
+		return []jsonEntity{{Key: "1", Name: "car1"}}, nil
+	}, delta.WithMarshalMode(delta.LoadOnMarshal))
+	b, err = json.Marshal(cars)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"key": "1", "name": "car1"}]`, string(b))
+	assert.True(t, cars.IsLoaded())
+}
+
+func TestMarshalJSON_UnloadedWithChanges(t *testing.T) {
+	cars := delta.NewLazySlice(func(id string) ([]jsonEntity, error) {
+		return []jsonEntity{{Key: "1", Name: "car1"}}, nil
+	})
+	b, err := json.Marshal(cars)
+	require.NoError(t, err)
+	assert.JSONEq(t, `null`, string(b))
+
+	// the pending changes are not skipped
+	cars.Set(jsonEntity{Key: "2", Name: "car2"})
+	b, err = json.Marshal(cars)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"key": "2", "name": "car2"}]`, string(b))
+	assert.False(t, cars.IsLoaded())
+
+	name := delta.NewLazy(func() (string, error) { return "Paulo", nil })
+	name.Set("Pedro")
+	b, err = json.Marshal(name)
+	require.NoError(t, err)
+	assert.JSONEq(t, `"Pedro"`, string(b))
+}
@@ -0,0 +1,142 @@
+// Package jsonpatch converts deltas into RFC 6902 JSON Patch documents.
+//
+// Since changes are tracked by ID, collections are represented as JSON objects keyed by the item ID,
+// eg: a removed car is {"op": "remove", "path": "/cars/42"}.
+// IDs are converted to keys with fmt.Sprint.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/quintans/delta"
+)
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// Operation is a JSON Patch operation.
+type Operation struct {
+	Op    string
+	Path  string
+	Value any // ignored for remove
+}
+
+func (o Operation) MarshalJSON() ([]byte, error) {
+	if o.Op == OpRemove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// Patch is a JSON Patch document.
+type Patch []Operation
+
+// Pointer builds a JSON Pointer from the reference tokens, escaping them.
+func Pointer(tokens ...string) string {
+	var sb strings.Builder
+	for _, t := range tokens {
+		t = strings.ReplaceAll(t, "~", "~0")
+		t = strings.ReplaceAll(t, "/", "~1")
+		sb.WriteString("/")
+		sb.WriteString(t)
+	}
+	return sb.String()
+}
+
+// Scalar returns the operation of a scalar field, if it changed.
+// It is a replace if the value was fetched, otherwise an add, that creates or replaces the member.
+func Scalar[T any](path string, change *delta.Change[T]) Patch {
+	if change == nil {
+		return nil
+	}
+	op := OpAdd
+	if change.HasOld {
+		op = OpReplace
+	}
+	return Patch{{Op: op, Path: path, Value: change.Value}}
+}
+
+// Slice returns the operations of a collection, one per changed item.
+// A reset replaces the whole collection with the added items.
+func Slice[T delta.Identifiable[I], I comparable](path string, changes delta.Changes[T, I]) Patch {
+	var patch Patch
+	if changes.Reset {
+		items := map[string]any{}
+		for c := range changes.Items {
+			if c.Status != delta.Removed {
+				items[key(c.ID)] = c.Value
+			}
+		}
+		return Patch{{Op: OpAdd, Path: path, Value: items}}
+	}
+	for c := range changes.Items {
+		p := path + Pointer(key(c.ID))
+		switch c.Status {
+		case delta.Added:
+			patch = append(patch, Operation{Op: OpAdd, Path: p, Value: c.Value})
+		case delta.Modified:
+			patch = append(patch, Operation{Op: OpReplace, Path: p, Value: c.Value})
+		case delta.Removed:
+			patch = append(patch, Operation{Op: OpRemove, Path: p})
+		}
+	}
+	return patch
+}
+
+// Tracker returns the operations of the pending changes of all the fields registered in the tracker,
+// each field being a member of the aggregate document.
+func Tracker(t *delta.Tracker) Patch {
+	var patch Patch
+	for _, op := range t.Pending() {
+		p := Pointer(op.Path)
+		if op.ID != nil {
+			p += Pointer(key(op.ID))
+		}
+		switch op.Op {
+		case delta.OpSet:
+			patch = append(patch, Operation{Op: OpAdd, Path: p, Value: op.Value})
+		case delta.OpRemove:
+			patch = append(patch, Operation{Op: OpRemove, Path: p})
+		case delta.OpClear:
+			patch = append(patch, Operation{Op: OpAdd, Path: p, Value: map[string]any{}})
+		case delta.OpSetAll:
+			patch = append(patch, Operation{Op: OpAdd, Path: p, Value: keyed(op.Value)})
+		}
+	}
+	return patch
+}
+
+func key(id any) string {
+	return fmt.Sprint(id)
+}
+
+// keyed converts a slice of identifiable items into an object keyed by ID.
+func keyed(values any) any {
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice {
+		return values
+	}
+	items := make(map[string]any, rv.Len())
+	for i := range rv.Len() {
+		v := rv.Index(i)
+		id := v.MethodByName("ID")
+		if !id.IsValid() {
+			return values
+		}
+		items[key(id.Call(nil)[0].Interface())] = v.Interface()
+	}
+	return items
+}
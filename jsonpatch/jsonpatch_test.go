@@ -0,0 +1,73 @@
+package jsonpatch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/quintans/delta/jsonpatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type car struct {
+	Key  string `json:"id"`
+	Make string `json:"make"`
+}
+
+func (c *car) ID() string {
+	return c.Key
+}
+
+func TestScalar(t *testing.T) {
+	name := delta.New("John")
+	name.Set("Jane")
+	age := delta.NewLazy(func() (int, error) { return 30, nil })
+	age.Set(0)
+
+	patch := append(jsonpatch.Scalar("/name", name.Change()), jsonpatch.Scalar("/age", age.Change())...)
+	b, err := json.Marshal(patch)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "replace", "path": "/name", "value": "Jane"},
+		{"op": "add", "path": "/age", "value": 0}
+	]`, string(b))
+}
+
+func TestSlice(t *testing.T) {
+	cars := delta.NewSlice([]*car{{Key: "1", Make: "Ford"}, {Key: "a/b", Make: "Fiat"}})
+	cars.Set(&car{Key: "1", Make: "Ferrari"})
+	cars.Set(&car{Key: "2", Make: "Opel"})
+	cars.Remove("a/b")
+
+	b, err := json.Marshal(jsonpatch.Slice("/cars", cars.Changes()))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "replace", "path": "/cars/1", "value": {"id": "1", "make": "Ferrari"}},
+		{"op": "remove", "path": "/cars/a~1b"},
+		{"op": "add", "path": "/cars/2", "value": {"id": "2", "make": "Opel"}}
+	]`, string(b))
+
+	cars.SetAll([]*car{{Key: "3", Make: "Seat"}})
+	b, err = json.Marshal(jsonpatch.Slice("/cars", cars.Changes()))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"op": "add", "path": "/cars", "value": {"3": {"id": "3", "make": "Seat"}}}]`, string(b))
+}
+
+func TestTracker(t *testing.T) {
+	name := delta.New("John")
+	cars := delta.NewSlice([]*car{{Key: "1", Make: "Ford"}})
+	tracker := delta.NewTracker()
+	tracker.Register("name", name)
+	tracker.Register("cars", cars)
+
+	name.Set("Jane")
+	cars.Remove("1")
+
+	b, err := json.Marshal(jsonpatch.Tracker(tracker))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "add", "path": "/name", "value": "Jane"},
+		{"op": "remove", "path": "/cars/1"}
+	]`, string(b))
+}
@@ -0,0 +1,95 @@
+package delta
+
+import "iter"
+
+// Keyed is a value that does not implement Identifiable (eg: a third-party struct or a protobuf message),
+// along with its key, so that it can be tracked in a collection.
+type Keyed[T any, I comparable] struct {
+	Value T
+	Key   I
+}
+
+func (k Keyed[T, I]) ID() I {
+	return k.Key
+}
+
+// LazyKeyedSlice is a collection of values identified by a key extractor instead of implementing Identifiable.
+// Items are held as Keyed, so that options over the items (eg: WithEqual) are over Keyed[T, I].
+type LazyKeyedSlice[T any, I comparable] struct {
+	LazySlice[Keyed[T, I], I]
+	key func(T) I
+}
+
+// NewLazySliceKeyed creates a collection whose items are loaded by fn, as NewLazySlice, and identified by key.
+func NewLazySliceKeyed[T any, I comparable](key func(T) I, fn func(I) ([]T, error), options ...Option) *LazyKeyedSlice[T, I] {
+	s := &LazyKeyedSlice[T, I]{key: key}
+	var load func(I) ([]Keyed[T, I], error)
+	if fn != nil {
+		load = func(id I) ([]Keyed[T, I], error) {
+			values, err := fn(id)
+			if err != nil {
+				return nil, err
+			}
+			return s.keyedAll(values), nil
+		}
+	}
+	s.init(load, newOptions(options))
+	return s
+}
+
+// NewSliceKeyed creates a loaded collection with values, identified by key.
+func NewSliceKeyed[T any, I comparable](key func(T) I, values []T, options ...Option) *LazyKeyedSlice[T, I] {
+	opts := newOptions(options)
+	opts.capacity = max(opts.capacity, len(values))
+	s := &LazyKeyedSlice[T, I]{key: key}
+	s.init(nil, opts)
+	s.isSet = true
+	for _, v := range s.keyedAll(values) {
+		s.fetched.Put(v.Key, Item[Keyed[T, I], I]{value: v, status: Unchanged})
+	}
+	s.reindex()
+	return s
+}
+
+func (s *LazyKeyedSlice[T, I]) keyed(value T) Keyed[T, I] {
+	return Keyed[T, I]{Value: value, Key: s.key(value)}
+}
+
+func (s *LazyKeyedSlice[T, I]) keyedAll(values []T) []Keyed[T, I] {
+	keyed := make([]Keyed[T, I], len(values))
+	for i, v := range values {
+		keyed[i] = s.keyed(v)
+	}
+	return keyed
+}
+
+// GetValues returns the values, loading them if needed, as GetAll.
+func (s *LazyKeyedSlice[T, I]) GetValues() (iter.Seq[T], error) {
+	items, err := s.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(T) bool) {
+		for item := range items {
+			if !yield(item.Value) {
+				return
+			}
+		}
+	}, nil
+}
+
+// GetValue returns the value identified by id, loading it if needed, as Get.
+func (s *LazyKeyedSlice[T, I]) GetValue(id I) (T, error) {
+	item, err := s.Get(id)
+	return item.Value, err
+}
+
+// SetValue adds or updates the value, as Set.
+func (s *LazyKeyedSlice[T, I]) SetValue(value T) {
+	s.Set(s.keyed(value))
+}
+
+// SetValues replaces all the values, as SetAll.
+func (s *LazyKeyedSlice[T, I]) SetValues(values []T) {
+	s.SetAll(s.keyedAll(values))
+}
@@ -0,0 +1,60 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// thirdParty does not implement Identifiable.
+type thirdParty struct {
+	Code string
+	Name string
+}
+
+func thirdPartyCode(v thirdParty) string {
+	return v.Code
+}
+
+func TestNewLazySliceKeyed(t *testing.T) {
+	items := delta.NewLazySliceKeyed(thirdPartyCode, func(code string) ([]thirdParty, error) {
+		return []thirdParty{{Code: "a", Name: "A"}, {Code: "b", Name: "B"}}, nil
+	})
+	tracker := delta.NewTracker()
+	tracker.Register("items", items)
+
+	values, err := items.GetValues()
+	require.NoError(t, err)
+	assert.Equal(t, []thirdParty{{Code: "a", Name: "A"}, {Code: "b", Name: "B"}}, slices.Collect(values))
+
+	v, err := items.GetValue("b")
+	require.NoError(t, err)
+	assert.Equal(t, thirdParty{Code: "b", Name: "B"}, v)
+
+	items.SetValue(thirdParty{Code: "a", Name: "changed"})
+	items.Remove("b")
+	assert.True(t, tracker.IsDirty())
+
+	var changes []delta.SliceChange[string, delta.Keyed[thirdParty, string]]
+	for c := range items.Changes().Items {
+		changes = append(changes, c)
+	}
+	require.Len(t, changes, 2)
+	assert.Equal(t, delta.Modified, changes[0].Status)
+	assert.Equal(t, thirdParty{Code: "a", Name: "changed"}, changes[0].Value.Value)
+	assert.Equal(t, delta.Removed, changes[1].Status)
+	assert.Equal(t, "b", changes[1].ID)
+}
+
+func TestNewSliceKeyed(t *testing.T) {
+	items := delta.NewSliceKeyed(thirdPartyCode, []thirdParty{{Code: "a", Name: "A"}})
+	items.SetValues([]thirdParty{{Code: "c", Name: "C"}})
+	assert.True(t, items.IsReset())
+
+	values, err := items.GetValues()
+	require.NoError(t, err)
+	assert.Equal(t, []thirdParty{{Code: "c", Name: "C"}}, slices.Collect(values))
+}
@@ -3,6 +3,8 @@ package delta
 import (
 	"errors"
 	"iter"
+	"sync"
+	"time"
 
 	"github.com/quintans/ds/collections/linkedmap"
 )
@@ -10,43 +12,173 @@ import (
 // ============ Scalar ======================
 
 type LazyScalar[T any] struct {
-	isSet   bool
-	value   T
-	fn      func() (T, error)
-	isDirty bool
+	isSet     bool
+	value     T
+	fn        func() (T, error)
+	isDirty   bool
+	original  T    // last fetched or persisted value, to be able to discard the changes
+	hasOrig   bool // false if the value was set without being fetched
+	meta      ChangeMeta
+	fetchedAt time.Time
+	ttl       time.Duration
+	equal     func(a, b T) bool // nil if values cannot be compared
+	clock     Clock
+	counters  *fieldCounters
+	gauge     *fieldGauge
+	budget    *memoryBudget
+	mu        sync.Mutex // serializes the loads, so that concurrent reads share a single load
+	errs      *errorCache[struct{}]
+	marshal   MarshalMode
+	mut       mutations
+	onChange  listeners[Change[T]]
+
+	fieldDiff   bool
+	keepHistory bool
+	history     []HistoryEntry[T]
 }
 
-func NewLazy[T any](fn func() (T, error)) *LazyScalar[T] {
-	return &LazyScalar[T]{isSet: false, fn: fn}
+func NewLazy[T any](fn func() (T, error), options ...Option) *LazyScalar[T] {
+	v := &LazyScalar[T]{}
+	v.init(fn, newOptions(options))
+	return v
+}
+
+func (v *LazyScalar[T]) init(fn func() (T, error), opts options) {
+	opts.only(scalarOptions, v)
+	v.fn = loader0(opts, fn)
+	v.clock = opts.clock
+	v.ttl = opts.ttl
+	v.equal = equalFor[T](opts)
+	v.errs = newErrorCache[struct{}](opts.errorTTL)
+	v.keepHistory = opts.history
+	v.fieldDiff = fieldDiffFor[T](opts)
+	v.marshal = opts.marshal
 }
 
 func (v *LazyScalar[T]) Get() (T, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.expire()
 	if v.isSet {
+		v.counters.hit()
+		v.budget.touch(v)
 		return v.value, nil
 	}
+	v.counters.miss()
+	return v.fetch()
+}
+
+// fetch loads the value, keeping the pending change, if any.
+func (v *LazyScalar[T]) fetch() (T, error) {
+	var zero T
+	if err := v.errs.get(struct{}{}, v.now()); err != nil {
+		return zero, err
+	}
+	start := v.now()
 	value, err := v.fn()
 	if err != nil {
-		var zero T
+		v.counters.failed(v.now().Sub(start))
+		v.errs.put(struct{}{}, err, v.now())
 		return zero, err
 	}
-	v.value = value
+	v.fetchedAt = v.now()
+	v.counters.loaded(sizeOf(value), v.fetchedAt.Sub(start))
+	if !v.isDirty {
+		v.value = value
+	}
+	v.original, v.hasOrig = value, true
 	v.isSet = true
+	v.updated()
+	v.budget.loaded(v)
 	return v.value, nil
 }
 
+// MustGet is like Get but panics if the value cannot be loaded.
+// Meant for infallible loaders (eg: in-memory hydration).
+func (v *LazyScalar[T]) MustGet() T {
+	value, err := v.Get()
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Set sets the value, marking it as changed, unless it is equal to the current or fetched value.
+// See WithEqual.
 func (v *LazyScalar[T]) Set(value T) {
+	v.mut.begin()
+	defer v.mut.end()
+
+	if v.equal != nil {
+		if v.hasOrig && v.equal(value, v.original) {
+			// back to the fetched value
+			if v.isDirty {
+				v.value = value
+				v.isDirty = false
+				v.updated()
+				v.mut.log(OpSet, "dirty", false)
+				v.onChange.notify(Change[T]{Value: value, Old: v.original, HasOld: true, Null: isNull(value)})
+			}
+			return
+		}
+		if v.isSet && v.equal(value, v.value) {
+			return
+		}
+	}
 	v.value = value
 	v.isSet = true
 	v.isDirty = true
+	v.meta = v.mut.meta(v.now())
+	v.updated()
+	v.mut.log(OpSet, "dirty", true)
+	v.onChange.notify(*v.Change())
+}
+
+// Refresh replaces the cached value with the one returned by the store after a save
+// (eg: server timestamps, incremented versions), without touching the change state.
+func (v *LazyScalar[T]) Refresh(value T) {
+	v.value = value
+	if !v.isDirty {
+		v.original, v.hasOrig = value, true
+	}
+	v.isSet = true
+	v.updated()
+}
+
+// FetchedAt returns when the value was loaded, or the zero time if it was not loaded.
+func (v *LazyScalar[T]) FetchedAt() time.Time {
+	return v.fetchedAt
+}
+
+func (v *LazyScalar[T]) now() time.Time {
+	if v.clock == nil {
+		return SystemClock.Now()
+	}
+	return v.clock.Now()
+}
+
+func (v *LazyScalar[T]) setClock(clock Clock) {
+	v.clock = clock
 }
 
 type Change[T any] struct {
-	Value T
+	Value  T
+	Old    T             // fetched value
+	HasOld bool          // false if the value was set without being fetched
+	Null   bool          // the value was set to nil or to an absent Optional, to be persisted as NULL
+	Fields []FieldChange // changed fields of a struct value, see WithFieldDiff
+	Meta   ChangeMeta
+}
+
+// AnyValue returns the new value, for the consumers of changes of any type (eg: the values of Tracker.Changes).
+func (c *Change[T]) AnyValue() any {
+	return c.Value
 }
 
 func (v *LazyScalar[T]) Change() *Change[T] {
 	if v.isDirty {
-		return &Change[T]{Value: v.value}
+		return &Change[T]{Value: v.value, Old: v.original, HasOld: v.hasOrig, Null: isNull(v.value), Fields: v.fieldChanges(), Meta: v.meta}
 	}
 	return nil
 }
@@ -55,13 +187,13 @@ type Scalar[T any] struct {
 	LazyScalar[T]
 }
 
-func New[T any](value T) *Scalar[T] {
-	return &Scalar[T]{
-		LazyScalar: LazyScalar[T]{
-			isSet: true,
-			value: value,
-		},
-	}
+func New[T any](value T, options ...Option) *Scalar[T] {
+	e := &Scalar[T]{}
+	e.init(nil, newOptions(options))
+	e.isSet = true
+	e.value = value
+	e.original, e.hasOrig = value, true
+	return e
 }
 
 func (e *Scalar[T]) Get() T {
@@ -84,54 +216,246 @@ type Identifiable[T comparable] interface {
 	ID() T
 }
 
+// Dirtyable can be implemented by the items of a collection that track their own changes,
+// so that an item mutated in place is reported as modified without being set again.
+type Dirtyable interface {
+	HasChanges() bool
+}
+
 type Item[T Identifiable[I], I comparable] struct {
 	value  T
 	status Status
+	old    T    // fetched value of a modified or removed item
+	hasOld bool // false if the item was changed without being fetched
+	meta   ChangeMeta
+}
+
+// set returns the item with a new value.
+func (item Item[T, I]) set(value T) Item[T, I] {
+	switch item.status {
+	case Absent, Added:
+		item.status = Added
+	case Unchanged:
+		item.status = Modified
+		item.old, item.hasOld = item.value, true
+	case Removed:
+		item.status = Modified
+	}
+	item.value = value
+	return item
+}
+
+// effectiveStatus returns the status of the item, reporting a fetched item with its own changes as modified.
+func (item Item[T, I]) effectiveStatus() Status {
+	if item.status == Unchanged {
+		if d, ok := any(item.value).(Dirtyable); ok && d.HasChanges() {
+			return Modified
+		}
+	}
+	return item.status
+}
+
+// remove returns the removed item.
+func (item Item[T, I]) remove() Item[T, I] {
+	if item.status == Unchanged {
+		item.old, item.hasOld = item.value, true
+	}
+	var zero T
+	item.value = zero
+	item.status = Removed
+	return item
+}
+
+// fetched returns the item reconciled with the fetched value.
+func (item Item[T, I]) fetched(value T) Item[T, I] {
+	switch {
+	case item.status == Absent:
+		item.value, item.status = value, Unchanged
+	case item.status == Added:
+		item.status = Modified
+		item.old, item.hasOld = value, true
+	case !item.hasOld && item.status != Unchanged && item.status != Absent:
+		item.old, item.hasOld = value, true
+	}
+	return item
 }
 
 type LazySlice[T Identifiable[I], I comparable] struct {
-	isSet   bool
-	isReset bool
-	fetched *linkedmap.Map[I, Item[T, I]]
-	fn      func(I) ([]T, error) // function to load items by ID. If ID is zero value, load all items.
+	isSet     bool
+	isReset   bool
+	fetched   *linkedmap.Map[I, Item[T, I]]
+	fn        func(I) ([]T, error) // function to load items by ID. If ID is zero value, load all items.
+	pager     func(Page[I]) ([]T, error)
+	querier   func(Query[I]) ([]T, error)
+	queries   map[string][]I // IDs of the items loaded per query
+	exister   func(I) (bool, error)
+	many      func([]I) ([]T, error)
+	stream    func() iter.Seq2[T, error]
+	errs      *errorCache[I] // the zero ID for the errors of loading all the items
+	noAbsent  bool
+	equal     func(a, b T) bool // nil if items cannot be compared
+	compare   func(a, b T) int  // nil to keep the insertion order
+	delta     func(T) any       // nil if the delta of modified items is not extracted
+	marshal   MarshalMode
+	order     ChangeOrder
+	existing  map[I]struct{} // items known to exist, without being loaded
+	indexes   map[string]*sliceIndex[T, I]
+	fetchedAt time.Time
+	loadedAt  time.Time // when the oldest cached item was loaded
+	ttl       time.Duration
+	clock     Clock
+	counters  *fieldCounters
+	gauge     *fieldGauge
+	budget    *memoryBudget
+	mu        sync.Mutex // serializes the loads, so that concurrent reads share a single load
+	stride    int
+	mut       mutations
+	onChange  listeners[SliceChange[I, T]]
+
+	// state before the collection was reset, to be able to discard the changes
+	beforeReset *linkedmap.Map[I, Item[T, I]]
+	wasSet      bool
+
+	keepHistory bool
+	history     []sliceEvent[T, I]
+}
+
+func NewLazySlice[T Identifiable[I], I comparable](fn func(I) ([]T, error), options ...Option) *LazySlice[T, I] {
+	s := &LazySlice[T, I]{}
+	s.init(fn, newOptions(options))
+	return s
+}
+
+func (s *LazySlice[T, I]) init(fn func(I) ([]T, error), opts options) {
+	opts.only(sliceOptions, s)
+	s.fn = loader(opts, fn)
+	s.pager = loader(opts, pagerFor[T, I](opts))
+	s.querier = loader(opts, querierFor[T, I](opts))
+	s.exister = loader(opts, existerFor[I](opts))
+	s.many = loader(opts, manyFor[T, I](opts))
+	s.stream = streamFor[T](opts)
+	s.errs = newErrorCache[I](opts.errorTTL)
+	s.noAbsent = opts.noAbsent
+	s.equal = equalFor[T](opts)
+	s.compare = compareFor[T](opts)
+	s.delta = deltaFor[T](opts)
+	s.indexes = indexesFor[T, I](opts)
+	s.fetched = linkedmap.New(linkedmap.WithCapacity[I, Item[T, I]](opts.capacity))
+	s.clock = opts.clock
+	s.ttl = opts.ttl
+	s.stride = opts.stride
+	s.keepHistory = opts.history
+	s.marshal = opts.marshal
+	s.order = opts.order
 }
 
-func NewLazySlice[T Identifiable[I], I comparable](fn func(I) ([]T, error)) *LazySlice[T, I] {
-	return &LazySlice[T, I]{
-		isSet:   false,
-		fn:      fn,
-		fetched: linkedmap.New[I, Item[T, I]](),
+// GetAll loads all the items, if not loaded yet, and iterates over them.
+// With query options, only a page of the items is returned, loaded with the page loader (see WithPageLoader)
+// if not all the items are loaded yet.
+func (s *LazySlice[T, I]) GetAll(opts ...QueryOption) (iter.Seq[T], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	if len(opts) > 0 {
+		return s.find(Filter[T]{}, opts)
 	}
+	return s.getAll()
 }
 
-func (s *LazySlice[T, I]) GetAll() (iter.Seq[T], error) {
+// getAll is GetAll without query options, for callers holding the lock.
+func (s *LazySlice[T, I]) getAll() (iter.Seq[T], error) {
 	if s.isSet {
-		return filterRemoved(s.fetched.Values()), nil
+		s.counters.hit()
+		s.budget.touch(s)
+		return s.ordered(filterRemoved(strided(s.fetched.Values(), s.stride))), nil
 	}
-	// load all items when zero value is passed
 	var zero I
-	values, err := s.fn(zero)
+	if err := s.errs.get(zero, s.now()); err != nil {
+		return nil, err
+	}
+	s.counters.miss()
+	start := s.now()
+	size, err := s.loadAll()
 	if err != nil {
+		s.counters.failed(s.now().Sub(start))
+		s.errs.put(zero, err, s.now())
 		return nil, err
 	}
+	s.fetchedAt = s.now()
+	s.counters.loaded(size, s.fetchedAt.Sub(start))
+	if s.loadedAt.IsZero() {
+		s.loadedAt = s.fetchedAt
+	}
 
+	s.isSet = true
+	s.budget.loaded(s)
+	return s.ordered(filterRemoved(strided(s.fetched.Values(), s.stride))), nil
+}
+
+// loadAll loads all the items into the cache, returning their size.
+func (s *LazySlice[T, I]) loadAll() (int, error) {
+	if s.stream != nil {
+		return s.loadStream()
+	}
+	// load all items when zero value is passed
+	var zero I
+	values, err := s.fn(zero)
+	if err != nil {
+		return 0, err
+	}
 	for _, v := range values {
-		item, ok := s.fetched.Get(v.ID())
-		if !ok {
-			s.fetched.Put(v.ID(), Item[T, I]{value: v, status: Unchanged})
-		} else if item.status == Added {
-			s.fetched.Put(v.ID(), Item[T, I]{value: item.value, status: Modified})
-		}
+		s.merge(v)
 	}
+	return sizeOfAll(values), nil
+}
 
-	s.isSet = true
-	return filterRemoved(s.fetched.Values()), nil
+// absent records an item found missing, unless WithoutAbsentCache is used.
+func (s *LazySlice[T, I]) absent(id I) {
+	if !s.noAbsent {
+		s.put(id, Item[T, I]{status: Absent})
+	}
+}
+
+// merge puts a loaded item in the cache, reconciled with its pending changes.
+func (s *LazySlice[T, I]) merge(v T) {
+	if item, ok := s.fetched.Get(v.ID()); ok {
+		s.put(v.ID(), item.fetched(v))
+		return
+	}
+	s.put(v.ID(), Item[T, I]{value: v, status: Unchanged})
+}
+
+// FetchedAt returns when all the items were loaded, or the zero time if they were not loaded.
+func (s *LazySlice[T, I]) FetchedAt() time.Time {
+	return s.fetchedAt
+}
+
+func (s *LazySlice[T, I]) now() time.Time {
+	if s.clock == nil {
+		return SystemClock.Now()
+	}
+	return s.clock.Now()
+}
+
+func (s *LazySlice[T, I]) setClock(clock Clock) {
+	s.clock = clock
+}
+
+// MustGetAll is like GetAll but panics if the items cannot be loaded.
+// Meant for infallible loaders (eg: in-memory hydration).
+func (s *LazySlice[T, I]) MustGetAll() iter.Seq[T] {
+	values, err := s.GetAll()
+	if err != nil {
+		panic(err)
+	}
+	return values
 }
 
 func filterRemoved[T Identifiable[I], I comparable](it iter.Seq[Item[T, I]]) iter.Seq[T] {
 	return func(yield func(T) bool) {
 		for v := range it {
-			if v.status == Removed {
+			if v.status == Removed || v.status == Absent {
 				continue
 			}
 			if !yield(v.value) {
@@ -144,8 +468,19 @@ func filterRemoved[T Identifiable[I], I comparable](it iter.Seq[Item[T, I]]) ite
 var ErrNotFound = errors.New("item not found")
 
 func (s *LazySlice[T, I]) Get(id I) (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.get(id)
+}
+
+// get returns the item, loading it if needed, with the load lock held.
+func (s *LazySlice[T, I]) get(id I) (T, error) {
+	s.expire()
 	item, exists := s.fetched.Get(id)
 	if exists {
+		s.counters.hit()
+		s.budget.touch(s)
 		if item.status == Absent || item.status == Removed {
 			var zero T
 			return zero, ErrNotFound
@@ -153,65 +488,204 @@ func (s *LazySlice[T, I]) Get(id I) (T, error) {
 		return item.value, nil
 	}
 	if s.isSet {
+		s.counters.hit()
 		var zero T
 		return zero, ErrNotFound
 	}
 
+	if err := s.errs.get(id, s.now()); err != nil {
+		var zero T
+		return zero, err
+	}
+	s.counters.miss()
+	start := s.now()
 	values, err := s.fn(id)
 	if err != nil {
+		s.counters.failed(s.now().Sub(start))
+		s.errs.put(id, err, s.now())
 		var zero T
 		return zero, err
 	}
+	loadedAt := s.now()
+	s.counters.loaded(sizeOfAll(values), loadedAt.Sub(start))
+	if s.loadedAt.IsZero() {
+		s.loadedAt = loadedAt
+	}
 	if len(values) == 0 {
-		s.fetched.Put(id, Item[T, I]{status: Absent})
+		s.absent(id)
 		var zero T
 		return zero, ErrNotFound
 	}
-	s.fetched.Put(values[0].ID(), Item[T, I]{value: values[0], status: Unchanged})
+	s.put(values[0].ID(), Item[T, I]{value: values[0], status: Unchanged})
+	s.budget.loaded(s)
 	return values[0], nil
 }
 
+// MustGet is like Get but panics if the item cannot be loaded or does not exist.
+// Meant for infallible loaders (eg: in-memory hydration).
+func (s *LazySlice[T, I]) MustGet(id I) T {
+	value, err := s.Get(id)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
 func (s *LazySlice[T, I]) SetAll(value []T) {
-	s.isReset = true
-	s.isSet = true
-	s.fetched = linkedmap.New(linkedmap.WithCapacity[I, Item[T, I]](len(value)))
+	s.mut.begin()
+	defer s.mut.end()
+
+	s.notifyReset()
+	s.reset()
+	s.replaceFetched(linkedmap.New(linkedmap.WithCapacity[I, Item[T, I]](len(value))))
+	meta := s.mut.meta(s.now())
 	for _, v := range value {
-		s.fetched.Put(v.ID(), Item[T, I]{value: v, status: Added})
+		item := Item[T, I]{value: v, status: Added, meta: meta}
+		s.put(v.ID(), item)
+		s.notifyItem(v.ID(), item)
 	}
+	s.mut.log(OpSetAll, "items", len(value))
 }
 
 func (s *LazySlice[T, I]) Set(value T) {
+	s.mut.begin()
+	defer s.mut.end()
+
+	item := s.set(value, s.mut.meta(s.now()))
+	s.mut.log(OpSet, "id", value.ID(), "status", item.status)
+}
+
+func (s *LazySlice[T, I]) set(value T, meta ChangeMeta) Item[T, I] {
 	item, exists := s.fetched.Get(value.ID())
 	if exists {
-		status := item.status
-		switch status {
-		case Absent, Added:
-			status = Added
-		case Removed, Unchanged:
-			status = Modified
-		}
-		s.fetched.Put(value.ID(), Item[T, I]{value: value, status: status})
-		return
+		item = item.set(value)
+	} else {
+		item = Item[T, I]{value: value, status: Added}
 	}
-	s.fetched.Put(value.ID(), Item[T, I]{value: value, status: Added})
+	item.meta = meta
+	s.put(value.ID(), item)
+	s.notifyItem(value.ID(), item)
+	return item
 }
 
 func (s *LazySlice[T, I]) Clear() {
-	s.isSet = true
-	s.isReset = true
-	s.fetched.Clear()
+	s.mut.begin()
+	defer s.mut.end()
+
+	s.notifyReset()
+	s.reset()
+	s.replaceFetched(linkedmap.New[I, Item[T, I]]())
+	s.mut.log(OpClear)
 }
 
 func (s *LazySlice[T, I]) Remove(id I) bool {
+	s.mut.begin()
+	defer s.mut.end()
+	s.mut.log(OpRemove, "id", id)
+
+	return s.remove(id, s.mut.meta(s.now()))
+}
+
+func (s *LazySlice[T, I]) remove(id I, meta ChangeMeta) bool {
 	item, exists := s.fetched.Get(id)
-	if exists {
-		if item.status == Added {
-			s.fetched.Delete(id)
-			return true
+	if !exists {
+		item = Item[T, I]{status: Removed, meta: meta}
+		s.put(id, item)
+		s.notifyItem(id, item)
+		return false
+	}
+	if item.status == Added {
+		s.delete(id)
+		s.notifyItem(id, Item[T, I]{status: Removed, meta: meta})
+		return true
+	}
+	item = item.remove()
+	item.meta = meta
+	s.put(id, item)
+	s.notifyItem(id, item)
+	return true
+}
+
+// Refresh replaces the cached item identified by id with the value returned by the store after a save
+// (eg: generated IDs, server timestamps, incremented versions), keeping its status and position.
+// If the value carries a different ID, the item is re-keyed. An added item is simply re-keyed, while an item
+// fetched from the store is removed under its old ID and added under the new one, so that the store is told of both.
+// It returns false if there is no cached item for id, or if the new ID is already cached.
+func (s *LazySlice[T, I]) Refresh(id I, value T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, exists := s.fetched.Get(id)
+	if !exists || item.status == Absent || item.status == Removed {
+		return false
+	}
+	newID := value.ID()
+	if newID == id {
+		item.value = value
+		s.put(id, item)
+		return true
+	}
+	if _, taken := s.fetched.Get(newID); taken {
+		return false
+	}
+
+	// rebuild to keep the insertion order
+	fetched := linkedmap.New(linkedmap.WithCapacity[I, Item[T, I]](s.fetched.Size() + 1))
+	for k, v := range s.fetched.Entries() {
+		if k != id {
+			fetched.Put(k, v)
+			continue
+		}
+		if item.status != Added {
+			fetched.Put(id, item.remove())
+			item = Item[T, I]{status: Added, meta: item.meta}
 		}
+		item.value = value
+		fetched.Put(newID, item)
+	}
+	s.replaceFetched(fetched)
+	return true
+}
+
+// reset marks the collection as reset, keeping the state before the first reset.
+func (s *LazySlice[T, I]) reset() {
+	if !s.isReset {
+		s.beforeReset = s.fetched
+		s.wasSet = s.isSet
+	}
+	s.isReset = true
+	s.isSet = true
+	s.queries = nil
+	s.existing = nil
+}
+
+// put puts an item in the cache, keeping the gauges, indexes and history up to date.
+func (s *LazySlice[T, I]) put(id I, item Item[T, I]) {
+	old, existed := s.fetched.Put(id, item)
+	if existed {
+		s.gauge.addStatus(old.status, -1)
 	}
-	s.fetched.Put(id, Item[T, I]{status: Removed})
-	return exists
+	s.gauge.addStatus(item.status, 1)
+	s.indexPut(id, item, old, existed)
+	s.recordPut(id, item)
+}
+
+// delete deletes an item from the cache, keeping the gauges, indexes and history up to date.
+func (s *LazySlice[T, I]) delete(id I) {
+	old, existed := s.fetched.Delete(id)
+	if existed {
+		s.gauge.addStatus(old.status, -1)
+		s.indexPut(id, Item[T, I]{status: Removed}, old, existed)
+	}
+	s.recordDelete(id)
+}
+
+// replaceFetched replaces the whole cache, keeping the gauges, indexes and history up to date.
+func (s *LazySlice[T, I]) replaceFetched(fetched *linkedmap.Map[I, Item[T, I]]) {
+	s.fetched = fetched
+	s.recount()
+	s.reindex()
+	s.recordReset()
 }
 
 func (s *LazySlice[T, I]) IsReset() bool {
@@ -223,30 +697,74 @@ type Changes[T Identifiable[I], I comparable] struct {
 	Items iter.Seq[SliceChange[I, T]]
 }
 
+// HasChanges returns true if the collection was reset or there is any changed item,
+// stopping at the first one (eg: to decide whether to open a transaction).
+func (c Changes[T, I]) HasChanges() bool {
+	if c.Reset {
+		return true
+	}
+	if c.Items == nil {
+		return false
+	}
+	for range c.Items {
+		return true
+	}
+	return false
+}
+
+// Len returns the number of changed items.
+func (c Changes[T, I]) Len() int {
+	if c.Items == nil {
+		return 0
+	}
+	n := 0
+	for range c.Items {
+		n++
+	}
+	return n
+}
+
 type SliceChange[I comparable, T any] struct {
 	ID     I
-	Value  T
+	Value  T // zero value for removed items
 	Status Status
+	Old    T    // fetched value of modified and removed items
+	HasOld bool // false if the item was changed without being fetched
+	Meta   ChangeMeta
+	// Delta is the own delta of a modified item, extracted with WithChildDelta. It is not encoded.
+	Delta any
 }
 
+// Changes returns the pending changes, iterated in the order set with WithChangeOrder.
 func (s *LazySlice[T, I]) Changes() Changes[T, I] {
 	return Changes[T, I]{
 		Reset: s.isReset,
-		Items: s.changesIterator(),
+		Items: sortedChanges(s.changesIterator(), s.order),
 	}
 }
 
 func (s *LazySlice[T, I]) changesIterator() iter.Seq[SliceChange[I, T]] {
-	it := s.fetched.Entries()
+	it := strided2(s.fetched.Entries(), s.stride)
 	return func(yield func(SliceChange[I, T]) bool) {
 		for k, v := range it {
-			if v.status == Unchanged {
+			status := v.effectiveStatus()
+			if status == Unchanged || status == Absent {
 				continue
 			}
 			change := SliceChange[I, T]{
 				ID:     k,
 				Value:  v.value,
-				Status: v.status,
+				Status: status,
+				Old:    v.old,
+				HasOld: v.hasOld,
+				Meta:   v.meta,
+			}
+			if status != v.status {
+				// mutated in place: the fetched value is the same instance
+				change.Old, change.HasOld = v.value, true
+			}
+			if status == Modified && s.delta != nil {
+				change.Delta = s.delta(v.value)
 			}
 			if !yield(change) {
 				return
@@ -259,23 +777,24 @@ type Slice[T Identifiable[I], I comparable] struct {
 	LazySlice[T, I]
 }
 
-func NewSlice[T Identifiable[I], I comparable](value []T) *Slice[T, I] {
-	fetched := linkedmap.New(linkedmap.WithCapacity[I, Item[T, I]](len(value)))
+func NewSlice[T Identifiable[I], I comparable](value []T, options ...Option) *Slice[T, I] {
+	opts := newOptions(options)
+	opts.capacity = max(opts.capacity, len(value))
+	e := &Slice[T, I]{}
+	e.init(nil, opts)
+	e.isSet = true
 	for _, v := range value {
-		fetched.Put(v.ID(), Item[T, I]{value: v, status: Unchanged})
-	}
-	return &Slice[T, I]{
-		LazySlice: LazySlice[T, I]{
-			isSet:   true,
-			fetched: fetched,
-		},
+		e.fetched.Put(v.ID(), Item[T, I]{value: v, status: Unchanged})
 	}
+	e.reindex()
+	return e
 }
 
 func (e *Slice[T, I]) GetAll() iter.Seq[T] {
-	return filterRemoved(e.fetched.Values())
+	return e.ordered(filterRemoved(e.fetched.Values()))
 }
 
+// Get returns the item identified by id, or the zero value if there is none. See Lookup.
 func (e *Slice[T, I]) Get(id I) T {
 	item, exists := e.fetched.Get(id)
 	if exists {
@@ -284,3 +803,14 @@ func (e *Slice[T, I]) Get(id I) T {
 	var zero T
 	return zero
 }
+
+// Lookup returns the item identified by id, or false if there is none or it was removed,
+// to tell an absent item from a zero value.
+func (e *Slice[T, I]) Lookup(id I) (T, bool) {
+	item, exists := e.fetched.Get(id)
+	if !exists || item.status == Removed || item.status == Absent {
+		var zero T
+		return zero, false
+	}
+	return item.value, true
+}
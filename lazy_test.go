@@ -540,6 +540,75 @@ func TestDeltaSlice_SetAll(t *testing.T) {
 	assert.Equal(t, delta.Added, changes[1].Status)
 }
 
+func TestDelta_Refresh(t *testing.T) {
+	scalar := delta.NewLazy(func() (int, error) {
+		return 1, nil
+	})
+	scalar.Set(2)
+	scalar.Refresh(3)
+
+	result, err := scalar.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 3, result)
+	change := scalar.Change()
+	require.NotNil(t, change)
+	assert.Equal(t, 3, change.Value)
+
+	eager := delta.New(1)
+	eager.Refresh(2)
+	assert.Equal(t, 2, eager.Get())
+	assert.Nil(t, eager.Change())
+}
+
+func TestDeltaSlice_Refresh(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+	}))
+	_, err := lazySlice.GetAll()
+	require.NoError(t, err)
+
+	lazySlice.Set(&testEntity{id: "tmp", name: "entity2"})
+	lazySlice.Set(&testEntity{id: "3", name: "entity3"})
+
+	// database generated ID
+	ok := lazySlice.Refresh("tmp", &testEntity{id: "2", name: "entity2"})
+	require.True(t, ok)
+	assert.False(t, lazySlice.Refresh("unknown", &testEntity{id: "4"}))
+
+	_, err = lazySlice.Get("tmp")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	result, err := lazySlice.Get("2")
+	require.NoError(t, err)
+	assert.Equal(t, "entity2", result.name)
+
+	changes := slices.Collect(lazySlice.Changes().Items)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "2", changes[0].ID)
+	assert.Equal(t, delta.Added, changes[0].Status)
+	assert.Equal(t, "3", changes[1].ID)
+	assert.Equal(t, delta.Added, changes[1].Status)
+
+	// re-keying onto a cached ID keeps both items
+	assert.False(t, lazySlice.Refresh("3", &testEntity{id: "1", name: "entity3"}))
+	result, err = lazySlice.Get("1")
+	require.NoError(t, err)
+	assert.Equal(t, "entity1", result.name)
+	result, err = lazySlice.Get("3")
+	require.NoError(t, err)
+	assert.Equal(t, "entity3", result.name)
+	assert.Len(t, slices.Collect(lazySlice.Changes().Items), 2)
+
+	// the store knows a fetched item by its old ID
+	require.True(t, lazySlice.Refresh("1", &testEntity{id: "10", name: "entity1"}))
+	changes = slices.Collect(lazySlice.Changes().Items)
+	require.Len(t, changes, 4)
+	assert.Equal(t, "1", changes[0].ID)
+	assert.Equal(t, delta.Removed, changes[0].Status)
+	assert.Equal(t, "10", changes[1].ID)
+	assert.Equal(t, delta.Added, changes[1].Status)
+	assert.Equal(t, "entity1", changes[1].Value.name)
+}
+
 func fetcher(ents []*testEntity) func(id string) ([]*testEntity, error) {
 	return func(id string) ([]*testEntity, error) {
 		if id == "" {
@@ -553,3 +622,115 @@ func fetcher(ents []*testEntity) func(id string) ([]*testEntity, error) {
 		return nil, delta.ErrNotFound
 	}
 }
+
+func TestLazyScalar_MustGet(t *testing.T) {
+	scalar := delta.NewLazy(func() (string, error) {
+		return "loaded", nil
+	})
+	assert.Equal(t, "loaded", scalar.MustGet())
+
+	failing := delta.NewLazy(func() (string, error) {
+		return "", errors.New("boom")
+	})
+	assert.PanicsWithError(t, "boom", func() {
+		failing.MustGet()
+	})
+}
+
+func TestLazySlice_MustGet(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+	}))
+	assert.Equal(t, "entity1", lazySlice.MustGet("1").name)
+	assert.Equal(t, []string{"1"}, ids(slices.Collect(lazySlice.MustGetAll())))
+	assert.PanicsWithError(t, delta.ErrNotFound.Error(), func() {
+		lazySlice.MustGet("2")
+	})
+}
+
+func TestChange_Old(t *testing.T) {
+	scalar := delta.NewLazy(func() (string, error) {
+		return "loaded", nil
+	})
+	scalar.MustGet()
+	scalar.Set("new value")
+	assert.Equal(t, &delta.Change[string]{Value: "new value", Old: "loaded", HasOld: true}, scalar.Change())
+
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	_, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	lazySlice.Set(&testEntity{id: "1", name: "entity1_new"})
+	lazySlice.Remove("2")
+	lazySlice.Remove("3")
+	assert.Equal(t, []delta.SliceChange[string, *testEntity]{
+		{ID: "1", Value: &testEntity{id: "1", name: "entity1_new"}, Status: delta.Modified, Old: &testEntity{id: "1", name: "entity1"}, HasOld: true},
+		{ID: "2", Status: delta.Removed, Old: &testEntity{id: "2", name: "entity2"}, HasOld: true},
+		{ID: "3", Status: delta.Removed},
+	}, slices.Collect(lazySlice.Changes().Items))
+}
+
+type dirtyEntity struct {
+	id   string
+	name *delta.Scalar[string]
+}
+
+func (e *dirtyEntity) ID() string {
+	return e.id
+}
+
+func (e *dirtyEntity) HasChanges() bool {
+	return e.name.IsDirty()
+}
+
+func TestDeltaSlice_Changes_Dirtyable(t *testing.T) {
+	entity1 := &dirtyEntity{id: "1", name: delta.New("entity1")}
+	entity2 := &dirtyEntity{id: "2", name: delta.New("entity2")}
+	lazySlice := delta.NewLazySlice(func(id string) ([]*dirtyEntity, error) {
+		return []*dirtyEntity{entity1, entity2}, nil
+	})
+	_, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.False(t, lazySlice.IsDirty())
+
+	entity2.name.Set("entity2_new")
+	assert.True(t, lazySlice.IsDirty())
+	assert.Equal(t, []delta.SliceChange[string, *dirtyEntity]{
+		{ID: "2", Value: entity2, Status: delta.Modified, Old: entity2, HasOld: true},
+	}, slices.Collect(lazySlice.Changes().Items))
+}
+
+func TestChangesHasChangesLen(t *testing.T) {
+	items := delta.NewSlice([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	})
+	assert.False(t, items.Changes().HasChanges())
+	assert.Equal(t, 0, items.Changes().Len())
+
+	items.Set(&testEntity{id: "1", name: "changed"})
+	items.Remove("2")
+	assert.True(t, items.Changes().HasChanges())
+	assert.Equal(t, 2, items.Changes().Len())
+
+	items.Clear()
+	assert.True(t, items.Changes().HasChanges())
+
+	assert.False(t, delta.Changes[*testEntity, string]{}.HasChanges())
+}
+
+func TestSliceLookup(t *testing.T) {
+	items := delta.NewSlice([]jsonEntity{{Key: "1"}, {Key: "2"}})
+	items.Remove("2")
+
+	v, ok := items.Lookup("1")
+	assert.True(t, ok)
+	assert.Equal(t, jsonEntity{Key: "1"}, v)
+
+	_, ok = items.Lookup("2")
+	assert.False(t, ok, "removed")
+	_, ok = items.Lookup("3")
+	assert.False(t, ok, "missing")
+}
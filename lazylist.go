@@ -0,0 +1,485 @@
+package delta
+
+import (
+	"fmt"
+	"iter"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+// LazyList is a lazily loaded ordered collection of child entities (eg: the tracks of a playlist),
+// that tracks the position of its items in addition to their addition, removal and modification.
+type LazyList[T Identifiable[I], I comparable] struct {
+	isSet     bool
+	fetched   []T        // items as loaded or last accepted, in order
+	items     []T        // current items, in order
+	set       map[I]bool // ids of the fetched items that were set
+	fn        func() ([]T, error)
+	equal     func(a, b T) bool
+	fetchedAt time.Time
+	ttl       time.Duration
+	clock     Clock
+	counters  *fieldCounters
+	gauge     *fieldGauge
+	budget    *memoryBudget
+	mu        sync.Mutex // serializes the loads, so that concurrent reads share a single load
+	mut       mutations
+}
+
+func NewLazyList[T Identifiable[I], I comparable](fn func() ([]T, error), options ...Option) *LazyList[T, I] {
+	opts := newOptions(options)
+	opts.only(listOptions, (*LazyList[T, I])(nil))
+	return &LazyList[T, I]{
+		fn:    loader0(opts, fn),
+		equal: equalFor[T](opts),
+		clock: opts.clock,
+		ttl:   opts.ttl,
+		set:   map[I]bool{},
+	}
+}
+
+// NewList creates a loaded list with values, in order.
+func NewList[T Identifiable[I], I comparable](values []T, options ...Option) *LazyList[T, I] {
+	l := NewLazyList[T, I](nil, options...)
+	l.isSet = true
+	l.fetched = slices.Clone(values)
+	l.items = slices.Clone(values)
+	return l
+}
+
+// GetAll returns the items, in order.
+func (l *LazyList[T, I]) GetAll() (iter.Seq[T], error) {
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+	return slices.Values(l.items), nil
+}
+
+// Get returns the item with the given id, or ErrNotFound.
+func (l *LazyList[T, I]) Get(id I) (T, error) {
+	if err := l.load(); err != nil {
+		var zero T
+		return zero, err
+	}
+	i := l.indexOf(id)
+	if i < 0 {
+		var zero T
+		return zero, ErrNotFound
+	}
+	return l.items[i], nil
+}
+
+// Index returns the position of the item with the given id, or ErrNotFound.
+func (l *LazyList[T, I]) Index(id I) (int, error) {
+	if err := l.load(); err != nil {
+		return 0, err
+	}
+	i := l.indexOf(id)
+	if i < 0 {
+		return 0, ErrNotFound
+	}
+	return i, nil
+}
+
+// Len returns the number of items.
+func (l *LazyList[T, I]) Len() (int, error) {
+	if err := l.load(); err != nil {
+		return 0, err
+	}
+	return len(l.items), nil
+}
+
+// Insert inserts value at position pos, shifting the following items.
+// It returns ErrAlreadyExists if there is an item with the same ID and ErrInvalidValue if pos is out of range.
+func (l *LazyList[T, I]) Insert(pos int, value T) error {
+	if err := l.mut.writable(); err != nil {
+		return err
+	}
+	l.mut.begin()
+	defer l.mut.end()
+
+	if err := l.load(); err != nil {
+		return err
+	}
+	if l.indexOf(value.ID()) >= 0 {
+		return fmt.Errorf("%w: %v", ErrAlreadyExists, value.ID())
+	}
+	if pos < 0 || pos > len(l.items) {
+		return fmt.Errorf("%w: position %d out of range [0, %d]", ErrInvalidValue, pos, len(l.items))
+	}
+	l.items = slices.Insert(l.items, pos, value)
+	l.markSet(value)
+	l.syncGauge()
+	l.mut.log(OpSet, "id", value.ID(), "pos", pos)
+	return nil
+}
+
+// Append adds value at the end of the list.
+// It returns ErrAlreadyExists if there is an item with the same ID.
+func (l *LazyList[T, I]) Append(value T) error {
+	if err := l.mut.writable(); err != nil {
+		return err
+	}
+	l.mut.begin()
+	defer l.mut.end()
+
+	if err := l.load(); err != nil {
+		return err
+	}
+	return l.Insert(len(l.items), value)
+}
+
+// Set replaces, in place, the item with the same ID as value, or appends value if there is none.
+func (l *LazyList[T, I]) Set(value T) error {
+	if err := l.mut.writable(); err != nil {
+		return err
+	}
+	l.mut.begin()
+	defer l.mut.end()
+
+	if err := l.load(); err != nil {
+		return err
+	}
+	i := l.indexOf(value.ID())
+	if i < 0 {
+		return l.Insert(len(l.items), value)
+	}
+	l.items[i] = value
+	l.markSet(value)
+	l.syncGauge()
+	l.mut.log(OpSet, "id", value.ID(), "pos", i)
+	return nil
+}
+
+// SetAll replaces all the items, in order.
+// Items are matched by ID with the fetched ones, to report the additions, removals, modifications and moves.
+func (l *LazyList[T, I]) SetAll(values []T) error {
+	if err := l.mut.writable(); err != nil {
+		return err
+	}
+	l.mut.begin()
+	defer l.mut.end()
+
+	if err := l.load(); err != nil {
+		return err
+	}
+	l.items = slices.Clone(values)
+	clear(l.set)
+	for _, v := range values {
+		l.markSet(v)
+	}
+	l.syncGauge()
+	l.mut.log(OpSetAll, "len", len(values))
+	return nil
+}
+
+// Remove removes the item with the given id, shifting the following items.
+// It returns false if there was no such item.
+func (l *LazyList[T, I]) Remove(id I) (bool, error) {
+	if err := l.mut.writable(); err != nil {
+		return false, err
+	}
+	l.mut.begin()
+	defer l.mut.end()
+
+	if err := l.load(); err != nil {
+		return false, err
+	}
+	i := l.indexOf(id)
+	if i < 0 {
+		return false, nil
+	}
+	l.items = slices.Delete(l.items, i, i+1)
+	delete(l.set, id)
+	l.syncGauge()
+	l.mut.log(OpRemove, "id", id, "pos", i)
+	return true, nil
+}
+
+// Move moves the item with the given id to position to, shifting the items in between.
+// It returns ErrNotFound if there is no such item and ErrInvalidValue if to is out of range.
+func (l *LazyList[T, I]) Move(id I, to int) error {
+	if err := l.mut.writable(); err != nil {
+		return err
+	}
+	l.mut.begin()
+	defer l.mut.end()
+
+	if err := l.load(); err != nil {
+		return err
+	}
+	from := l.indexOf(id)
+	if from < 0 {
+		return fmt.Errorf("%w: %v", ErrNotFound, id)
+	}
+	if to < 0 || to >= len(l.items) {
+		return fmt.Errorf("%w: position %d out of range [0, %d)", ErrInvalidValue, to, len(l.items))
+	}
+	value := l.items[from]
+	l.items = slices.Insert(slices.Delete(l.items, from, from+1), to, value)
+	l.syncGauge()
+	l.mut.log(OpSet, "id", id, "from", from, "to", to)
+	return nil
+}
+
+// markSet records that a fetched item was set, unless it is equal to the fetched value.
+func (l *LazyList[T, I]) markSet(value T) {
+	id := value.ID()
+	if i := indexOfID(l.fetched, id); i >= 0 && l.equal != nil && l.equal(l.fetched[i], value) {
+		delete(l.set, id)
+		return
+	}
+	l.set[id] = true
+}
+
+func (l *LazyList[T, I]) indexOf(id I) int {
+	return indexOfID(l.items, id)
+}
+
+func indexOfID[T Identifiable[I], I comparable](items []T, id I) int {
+	return slices.IndexFunc(items, func(v T) bool {
+		return v.ID() == id
+	})
+}
+
+// ListChange is the change of an item of a LazyList.
+type ListChange[I comparable, T any] struct {
+	ID I
+	// Status is Added, Removed or Modified, or Unchanged for an item that was only moved.
+	Status Status
+	Value  T    // zero value for removed items
+	Old    T    // fetched value of modified and removed items
+	HasOld bool // false for added items
+	// MovedFrom is the position of the item in the fetched list, or -1 if it was added.
+	MovedFrom int
+	// MovedTo is the position of the item in the current list, or -1 if it was removed.
+	MovedTo int
+	// Moved is true if the item changed its position relative to the other retained items,
+	// and not only because of the additions and removals before it.
+	Moved bool
+}
+
+// Changes returns the pending changes: the removed items, in their fetched order,
+// followed by the added, modified and moved items, in their current order.
+// As few items as possible are reported as moved: those outside the longest run of items that kept their fetched order.
+func (l *LazyList[T, I]) Changes() iter.Seq[ListChange[I, T]] {
+	return slices.Values(l.listChanges())
+}
+
+func (l *LazyList[T, I]) listChanges() []ListChange[I, T] {
+	fetchedPos := make(map[I]int, len(l.fetched))
+	for i, v := range l.fetched {
+		fetchedPos[v.ID()] = i
+	}
+	current := make(map[I]bool, len(l.items))
+	var changes []ListChange[I, T]
+	for _, v := range l.items {
+		current[v.ID()] = true
+	}
+	for i, v := range l.fetched {
+		if !current[v.ID()] {
+			changes = append(changes, ListChange[I, T]{
+				ID: v.ID(), Status: Removed, Old: v, HasOld: true, MovedFrom: i, MovedTo: -1,
+			})
+		}
+	}
+
+	var retained []int // fetched positions, in current order
+	for _, v := range l.items {
+		if p, ok := fetchedPos[v.ID()]; ok {
+			retained = append(retained, p)
+		}
+	}
+	kept := longestIncreasing(retained)
+
+	for i, v := range l.items {
+		p, ok := fetchedPos[v.ID()]
+		if !ok {
+			changes = append(changes, ListChange[I, T]{
+				ID: v.ID(), Status: Added, Value: v, MovedFrom: -1, MovedTo: i,
+			})
+			continue
+		}
+		c := ListChange[I, T]{
+			ID: v.ID(), Status: Unchanged, Value: v, Old: l.fetched[p], HasOld: true, MovedFrom: p, MovedTo: i,
+			Moved: !kept[p],
+		}
+		if l.set[v.ID()] {
+			c.Status = Modified
+		} else if d, ok := any(v).(Dirtyable); ok && d.HasChanges() {
+			c.Status = Modified
+		}
+		if c.Status == Unchanged && !c.Moved {
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// longestIncreasing returns the values of a longest strictly increasing subsequence of values.
+func longestIncreasing(values []int) map[int]bool {
+	// tails[k] is the index, in values, of the smallest tail of the increasing subsequences of length k+1
+	var tails []int
+	prev := make([]int, len(values))
+	for i, v := range values {
+		k, _ := slices.BinarySearchFunc(tails, v, func(j, v int) int {
+			return values[j] - v
+		})
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	result := make(map[int]bool, len(tails))
+	if len(tails) == 0 {
+		return result
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+		result[values[i]] = true
+	}
+	return result
+}
+
+// FetchedAt returns when the list was loaded, or the zero time if it was not loaded.
+func (l *LazyList[T, I]) FetchedAt() time.Time {
+	return l.fetchedAt
+}
+
+func (l *LazyList[T, I]) now() time.Time {
+	if l.clock == nil {
+		return SystemClock.Now()
+	}
+	return l.clock.Now()
+}
+
+// syncGauge updates the gauge with the current state of the list.
+func (l *LazyList[T, I]) syncGauge() {
+	if l.gauge == nil {
+		return
+	}
+	l.gauge.set(int64(len(l.items)), int64(len(l.listChanges())))
+}
+
+// Evict drops the loaded items, unless the list was changed or there is no loader to reload it.
+func (l *LazyList[T, I]) Evict() bool {
+	if !l.isSet || l.fn == nil || l.IsDirty() {
+		return false
+	}
+	l.isSet = false
+	l.fetched, l.items = nil, nil
+	clear(l.set)
+	l.fetchedAt = time.Time{}
+	l.syncGauge()
+	return true
+}
+
+// ============ Field ======================
+
+func (l *LazyList[T, I]) patch(op PatchOp) error {
+	switch op.Op {
+	case OpSetAll:
+		values, err := convert[[]T](op.Value)
+		if err != nil {
+			return err
+		}
+		return l.SetAll(values)
+	case OpSet:
+		value, err := convert[T](op.Value)
+		if err != nil {
+			return err
+		}
+		return l.Set(value)
+	case OpRemove:
+		id, err := convert[I](op.ID)
+		if err != nil {
+			return err
+		}
+		_, err = l.Remove(id)
+		return err
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedOperation, op.Op)
+	}
+}
+
+// operations replaces all the items, since positions cannot be expressed by the other operations.
+func (l *LazyList[T, I]) operations() []PatchOp {
+	if !l.IsDirty() {
+		return nil
+	}
+	return []PatchOp{{Op: OpSetAll, Value: slices.Clone(l.items)}}
+}
+
+func (l *LazyList[T, I]) base() Field {
+	return l
+}
+
+func (l *LazyList[T, I]) instrument(c *fieldCounters) {
+	l.counters = c
+	if l.gauge != nil {
+		// withdraw the contribution of a previous registration
+		l.gauge.release()
+	}
+	l.gauge = &fieldGauge{counters: c}
+	l.syncGauge()
+	runtime.AddCleanup(l, (*fieldGauge).release, l.gauge)
+}
+
+func (l *LazyList[T, I]) setClock(clock Clock) {
+	l.clock = clock
+}
+
+func (l *LazyList[T, I]) footprint() int {
+	var n int
+	for _, v := range l.items {
+		n += sizeOf(v)
+	}
+	return n
+}
+
+func (l *LazyList[T, I]) setBudget(b *memoryBudget) {
+	l.budget = b
+}
+
+func (l *LazyList[T, I]) getBudget() *memoryBudget {
+	return l.budget
+}
+
+func (l *LazyList[T, I]) locker() *sync.Mutex {
+	return &l.mu
+}
+
+func (l *LazyList[T, I]) load() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expire()
+	if l.isSet {
+		l.counters.hit()
+		l.budget.touch(l)
+		return nil
+	}
+	l.counters.miss()
+	start := l.now()
+	values, err := l.fn()
+	if err != nil {
+		l.counters.failed(l.now().Sub(start))
+		return err
+	}
+	l.fetchedAt = l.now()
+	l.isSet = true
+	l.fetched = values
+	l.items = slices.Clone(values)
+	l.counters.loaded(l.footprint(), l.fetchedAt.Sub(start))
+	l.syncGauge()
+	l.budget.loaded(l)
+	return nil
+}
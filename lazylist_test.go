@@ -0,0 +1,126 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTracks() *delta.LazyList[*testEntity, string] {
+	return delta.NewLazyList(func() ([]*testEntity, error) {
+		return []*testEntity{
+			{id: "a", name: "track a"},
+			{id: "b", name: "track b"},
+			{id: "c", name: "track c"},
+			{id: "d", name: "track d"},
+		}, nil
+	})
+}
+
+func listIDs(t *testing.T, l *delta.LazyList[*testEntity, string]) []string {
+	t.Helper()
+	items, err := l.GetAll()
+	require.NoError(t, err)
+	var ids []string
+	for v := range items {
+		ids = append(ids, v.id)
+	}
+	return ids
+}
+
+func TestLazyList_Move(t *testing.T) {
+	tracks := newTracks()
+	require.NoError(t, tracks.Move("d", 0))
+	assert.Equal(t, []string{"d", "a", "b", "c"}, listIDs(t, tracks))
+
+	changes := slices.Collect(tracks.Changes())
+	require.Len(t, changes, 1)
+	assert.Equal(t, "d", changes[0].ID)
+	assert.Equal(t, delta.Unchanged, changes[0].Status)
+	assert.True(t, changes[0].Moved)
+	assert.Equal(t, 3, changes[0].MovedFrom)
+	assert.Equal(t, 0, changes[0].MovedTo)
+
+	// moving back is no change
+	require.NoError(t, tracks.Move("d", 3))
+	assert.False(t, tracks.IsDirty())
+
+	assert.ErrorIs(t, tracks.Move("x", 0), delta.ErrNotFound)
+	assert.ErrorIs(t, tracks.Move("a", 4), delta.ErrInvalidValue)
+}
+
+func TestLazyList_Changes(t *testing.T) {
+	tracks := newTracks()
+	require.NoError(t, tracks.Insert(1, &testEntity{id: "e", name: "track e"}))
+	removed, err := tracks.Remove("c")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	require.NoError(t, tracks.Set(&testEntity{id: "b", name: "changed"}))
+	assert.Equal(t, []string{"a", "e", "b", "d"}, listIDs(t, tracks))
+
+	changes := slices.Collect(tracks.Changes())
+	require.Len(t, changes, 3)
+	assert.Equal(t, delta.ListChange[string, *testEntity]{
+		ID: "c", Status: delta.Removed, Old: &testEntity{id: "c", name: "track c"}, HasOld: true, MovedFrom: 2, MovedTo: -1,
+	}, changes[0])
+	assert.Equal(t, delta.ListChange[string, *testEntity]{
+		ID: "e", Status: delta.Added, Value: &testEntity{id: "e", name: "track e"}, MovedFrom: -1, MovedTo: 1,
+	}, changes[1])
+	// shifted by the insertion, but not moved
+	assert.Equal(t, delta.ListChange[string, *testEntity]{
+		ID: "b", Status: delta.Modified, Value: &testEntity{id: "b", name: "changed"},
+		Old: &testEntity{id: "b", name: "track b"}, HasOld: true, MovedFrom: 1, MovedTo: 2,
+	}, changes[2])
+
+	assert.ErrorIs(t, tracks.Insert(0, &testEntity{id: "a"}), delta.ErrAlreadyExists)
+	assert.ErrorIs(t, tracks.Insert(5, &testEntity{id: "f"}), delta.ErrInvalidValue)
+
+	tracks.AcceptChanges()
+	assert.False(t, tracks.IsDirty())
+	assert.Equal(t, []string{"a", "e", "b", "d"}, listIDs(t, tracks))
+}
+
+func TestLazyList_SetAll(t *testing.T) {
+	tracks := newTracks()
+	require.NoError(t, tracks.SetAll([]*testEntity{
+		{id: "b", name: "track b"},
+		{id: "c", name: "track c"},
+		{id: "a", name: "track a"},
+	}))
+
+	var moved []string
+	for c := range tracks.Changes() {
+		if c.Moved {
+			moved = append(moved, c.ID)
+		}
+	}
+	// b and c kept their relative order
+	assert.Equal(t, []string{"a"}, moved)
+
+	tracks.DiscardChanges()
+	assert.False(t, tracks.IsDirty())
+	assert.Equal(t, []string{"a", "b", "c", "d"}, listIDs(t, tracks))
+}
+
+func TestLazyList_Tracker(t *testing.T) {
+	tracks := newTracks()
+	tracker := delta.NewTracker(delta.WithJournal())
+	tracker.Register("tracks", tracks)
+
+	require.NoError(t, tracks.Move("a", 3))
+	ops := tracker.Pending()
+	require.Len(t, ops, 1)
+	assert.Equal(t, delta.OpSetAll, ops[0].Op)
+
+	require.NoError(t, tracker.Undo())
+	assert.Equal(t, []string{"a", "b", "c", "d"}, listIDs(t, tracks))
+
+	other := newTracks()
+	replica := delta.NewTracker()
+	replica.Register("tracks", other)
+	require.NoError(t, delta.ApplyPatch(replica, ops))
+	assert.Equal(t, []string{"b", "c", "d", "a"}, listIDs(t, other))
+}
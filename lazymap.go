@@ -0,0 +1,293 @@
+package delta
+
+import (
+	"fmt"
+	"iter"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// LazyMap is a lazily loaded map of keyed child data (eg: settings) with change tracking per key.
+type LazyMap[K comparable, V any] struct {
+	mapItems[K, V]
+	isSet     bool
+	isReset   bool
+	fn        func(key K) (map[K]V, error) // function to load a key. If key is zero value, load all keys.
+	fetchedAt time.Time
+	loadedAt  time.Time // when the oldest cached key was loaded
+	ttl       time.Duration
+	clock     Clock
+	counters  *fieldCounters
+	budget    *memoryBudget
+	mu        sync.Mutex // serializes the loads, so that concurrent reads share a single load
+	stride    int
+	mut       mutations
+
+	// state before the map was reset, to be able to discard the changes
+	beforeReset *linkedmap.Map[K, mapItem[V]]
+	wasSet      bool
+}
+
+func NewLazyMap[K comparable, V any](fn func(key K) (map[K]V, error), options ...Option) *LazyMap[K, V] {
+	opts := newOptions(options)
+	opts.only(mapOptions, (*LazyMap[K, V])(nil))
+	return &LazyMap[K, V]{
+		mapItems: newMapItems[K, V](opts.capacity),
+		fn:       loader(opts, fn),
+		clock:    opts.clock,
+		ttl:      opts.ttl,
+		stride:   opts.stride,
+	}
+}
+
+// GetAll loads all the keys, if not loaded yet, and iterates over the entries.
+// Loaded keys come in order: by value for ordered kinds (eg: integers, strings) and by their fmt.Sprint representation otherwise.
+func (m *LazyMap[K, V]) GetAll() (iter.Seq2[K, V], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expire()
+	if m.isSet {
+		m.counters.hit()
+		m.budget.touch(m)
+		return m.present(m.stride), nil
+	}
+	m.counters.miss()
+	var zero K
+	start := m.now()
+	values, err := m.fn(zero)
+	if err != nil {
+		m.counters.failed(m.now().Sub(start))
+		return nil, err
+	}
+	m.fetchedAt = m.now()
+	m.counters.loaded(sizeOfMap(values), m.fetchedAt.Sub(start))
+	m.markLoaded(m.fetchedAt)
+
+	m.mergeAll(values)
+
+	m.isSet = true
+	m.budget.loaded(m)
+	return m.present(m.stride), nil
+}
+
+func (m *LazyMap[K, V]) Get(key K) (V, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expire()
+	var zero V
+	item, exists := m.fetched.Get(key)
+	if exists {
+		m.counters.hit()
+		m.budget.touch(m)
+		if item.status == Absent || item.status == Removed {
+			return zero, ErrNotFound
+		}
+		return item.value, nil
+	}
+	if m.isSet {
+		m.counters.hit()
+		return zero, ErrNotFound
+	}
+
+	m.counters.miss()
+	start := m.now()
+	values, err := m.fn(key)
+	if err != nil {
+		m.counters.failed(m.now().Sub(start))
+		return zero, err
+	}
+	loadedAt := m.now()
+	m.counters.loaded(sizeOfMap(values), loadedAt.Sub(start))
+	m.markLoaded(loadedAt)
+	value, ok := values[key]
+	if !ok {
+		m.put(key, mapItem[V]{status: Absent})
+		return zero, ErrNotFound
+	}
+	m.put(key, mapItem[V]{value: value, status: Unchanged})
+	m.budget.loaded(m)
+	return value, nil
+}
+
+// Put adds or replaces the value of a key.
+func (m *LazyMap[K, V]) Put(key K, value V) {
+	m.mut.begin()
+	defer m.mut.end()
+
+	item := m.setKey(key, value)
+	m.mut.log(OpSet, "id", key, "status", item.status)
+}
+
+// Delete removes a key, returning true if it was known to exist.
+func (m *LazyMap[K, V]) Delete(key K) bool {
+	m.mut.begin()
+	defer m.mut.end()
+	m.mut.log(OpRemove, "id", key)
+
+	return m.removeKey(key)
+}
+
+func (m *LazyMap[K, V]) Clear() {
+	m.mut.begin()
+	defer m.mut.end()
+
+	if !m.isReset {
+		m.beforeReset = m.fetched
+		m.wasSet = m.isSet
+	}
+	m.isSet = true
+	m.isReset = true
+	m.fetched = linkedmap.New[K, mapItem[V]]()
+	m.recount()
+	m.mut.log(OpClear)
+}
+
+func (m *LazyMap[K, V]) IsReset() bool {
+	return m.isReset
+}
+
+type MapChanges[K comparable, V any] struct {
+	Reset bool
+	Items iter.Seq[MapChange[K, V]]
+}
+
+type MapChange[K comparable, V any] struct {
+	Key    K
+	Value  V // zero value for removed keys
+	Status Status
+	Old    V    // fetched value of modified and removed keys
+	HasOld bool // false if the key was changed without being fetched
+}
+
+func (m *LazyMap[K, V]) Changes() MapChanges[K, V] {
+	return MapChanges[K, V]{
+		Reset: m.isReset,
+		Items: m.changesIterator(),
+	}
+}
+
+func (m *LazyMap[K, V]) changesIterator() iter.Seq[MapChange[K, V]] {
+	it := m.changed(m.stride)
+	return func(yield func(MapChange[K, V]) bool) {
+		for k, v := range it {
+			change := MapChange[K, V]{
+				Key:    k,
+				Value:  v.value,
+				Status: v.status,
+				Old:    v.old,
+				HasOld: v.hasOld,
+			}
+			if !yield(change) {
+				return
+			}
+		}
+	}
+}
+
+// FetchedAt returns when all the keys were loaded, or the zero time if they were not loaded.
+func (m *LazyMap[K, V]) FetchedAt() time.Time {
+	return m.fetchedAt
+}
+
+func (m *LazyMap[K, V]) now() time.Time {
+	if m.clock == nil {
+		return SystemClock.Now()
+	}
+	return m.clock.Now()
+}
+
+// Evict drops the loaded keys that have no pending changes, unless the map was cleared
+// or there is no loader to reload them.
+func (m *LazyMap[K, V]) Evict() bool {
+	if m.isReset || m.fn == nil {
+		return false
+	}
+	if m.dropUnchanged() == 0 && !m.isSet {
+		return false
+	}
+	m.isSet = false
+	m.fetchedAt = time.Time{}
+	m.loadedAt = time.Time{}
+	return true
+}
+
+// ============ Field ======================
+
+func (m *LazyMap[K, V]) patch(op PatchOp) error {
+	switch op.Op {
+	case OpSet:
+		key, err := convert[K](op.ID)
+		if err != nil {
+			return err
+		}
+		value, err := convert[V](op.Value)
+		if err != nil {
+			return err
+		}
+		m.Put(key, value)
+	case OpRemove:
+		key, err := convert[K](op.ID)
+		if err != nil {
+			return err
+		}
+		m.Delete(key)
+	case OpClear:
+		m.Clear()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedOperation, op.Op)
+	}
+	return nil
+}
+
+func (m *LazyMap[K, V]) operations() []PatchOp {
+	return m.keyOperations(m.isReset)
+}
+
+func (m *LazyMap[K, V]) base() Field {
+	return m
+}
+
+func (m *LazyMap[K, V]) instrument(c *fieldCounters) {
+	m.counters = c
+	if m.gauge != nil {
+		// withdraw the contribution of a previous registration
+		m.gauge.release()
+	}
+	m.gauge = &fieldGauge{counters: c}
+	m.recount()
+	runtime.AddCleanup(m, (*fieldGauge).release, m.gauge)
+}
+
+func (m *LazyMap[K, V]) setClock(clock Clock) {
+	m.clock = clock
+}
+
+func (m *LazyMap[K, V]) setBudget(b *memoryBudget) {
+	m.budget = b
+}
+
+func (m *LazyMap[K, V]) getBudget() *memoryBudget {
+	return m.budget
+}
+
+func (m *LazyMap[K, V]) locker() *sync.Mutex {
+	return &m.mu
+}
+
+func (m *LazyMap[K, V]) load() error {
+	_, err := m.GetAll()
+	return err
+}
+
+func sizeOfMap[K comparable, V any](values map[K]V) int {
+	size := 0
+	for _, v := range values {
+		size += sizeOf(v)
+	}
+	return size
+}
@@ -0,0 +1,152 @@
+package delta_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mapFetcher(values map[string]int, calls *[]string) func(key string) (map[string]int, error) {
+	return func(key string) (map[string]int, error) {
+		*calls = append(*calls, key)
+		if key == "" {
+			return values, nil
+		}
+		v, ok := values[key]
+		if !ok {
+			return nil, nil
+		}
+		return map[string]int{key: v}, nil
+	}
+}
+
+func TestLazyMap_Get(t *testing.T) {
+	var calls []string
+	settings := delta.NewLazyMap(mapFetcher(map[string]int{"volume": 5, "brightness": 80}, &calls))
+
+	v, err := settings.Get("volume")
+	require.NoError(t, err)
+	assert.Equal(t, 5, v)
+	_, err = settings.Get("volume")
+	require.NoError(t, err)
+
+	_, err = settings.Get("contrast")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	_, err = settings.Get("contrast")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	assert.Equal(t, []string{"volume", "contrast"}, calls)
+
+	all, err := settings.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"volume": 5, "brightness": 80}, maps.Collect(all))
+	assert.Equal(t, []string{"volume", "contrast", ""}, calls)
+}
+
+func TestLazyMap_GetAllAfterAbsent(t *testing.T) {
+	values := map[string]int{}
+	settings := delta.NewLazyMap(mapFetcher(values, new([]string)))
+
+	_, err := settings.Get("volume")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+
+	values["volume"] = 5 // eg: created meanwhile
+	all, err := settings.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"volume": 5}, maps.Collect(all))
+	assert.False(t, settings.IsDirty())
+}
+
+func TestLazyMap_GetAllOrder(t *testing.T) {
+	values := map[int]string{}
+	for i := range 20 {
+		values[i] = "v"
+	}
+	settings := delta.NewLazyMap(func(key int) (map[int]string, error) {
+		return values, nil
+	})
+	settings.Put(30, "added")
+	all, err := settings.GetAll()
+	require.NoError(t, err)
+
+	// the pending keys come first and the loaded ones in key order
+	var keys []int
+	for k := range all {
+		keys = append(keys, k)
+	}
+	assert.Equal(t, append([]int{30}, slices.Sorted(maps.Keys(values))...), keys)
+}
+
+func TestLazyMap_Changes(t *testing.T) {
+	var calls []string
+	settings := delta.NewLazyMap(mapFetcher(map[string]int{"volume": 5, "brightness": 80}, &calls))
+
+	settings.Put("contrast", 50)
+	settings.Put("volume", 7)
+	assert.False(t, settings.Delete("brightness")) // not known to exist
+	settings.Put("temp", 1)
+	assert.True(t, settings.Delete("temp"))
+
+	// changes are known without loading
+	assert.Empty(t, calls)
+	changes := settings.Changes()
+	assert.False(t, changes.Reset)
+	byKey := map[string]delta.MapChange[string, int]{}
+	for c := range changes.Items {
+		byKey[c.Key] = c
+	}
+	assert.Equal(t, map[string]delta.MapChange[string, int]{
+		"contrast":   {Key: "contrast", Value: 50, Status: delta.Added},
+		"volume":     {Key: "volume", Value: 7, Status: delta.Added},
+		"brightness": {Key: "brightness", Status: delta.Removed},
+	}, byKey)
+
+	// loading reconciles the added keys that already existed
+	all, err := settings.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"volume": 7, "contrast": 50}, maps.Collect(all))
+	for c := range settings.Changes().Items {
+		if c.Key == "volume" {
+			assert.Equal(t, delta.Modified, c.Status)
+		}
+	}
+
+	settings.Clear()
+	settings.Put("volume", 1)
+	changes = settings.Changes()
+	assert.True(t, changes.Reset)
+	var keys []string
+	for c := range changes.Items {
+		keys = append(keys, c.Key)
+	}
+	assert.Equal(t, []string{"volume"}, keys)
+}
+
+func TestLazyMap_Tracker(t *testing.T) {
+	var calls []string
+	settings := delta.NewLazyMap(mapFetcher(map[string]int{"volume": 5}, &calls))
+	tracker := delta.NewTracker()
+	tracker.Register("settings", settings)
+
+	err := delta.ApplyPatch(tracker, delta.Patch{
+		{Op: delta.OpSet, Path: "settings", ID: "volume", Value: 9.0},
+		{Op: delta.OpRemove, Path: "settings", ID: "brightness"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, delta.Patch{
+		{Op: delta.OpSet, Path: "settings", ID: "volume", Value: 9},
+		{Op: delta.OpRemove, Path: "settings", ID: "brightness"},
+	}, tracker.Compact())
+
+	v, err := settings.Get("volume")
+	require.NoError(t, err)
+	assert.Equal(t, 9, v)
+
+	require.NoError(t, tracker.Load())
+	all, err := settings.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"volume": 9}, maps.Collect(all))
+}
@@ -0,0 +1,278 @@
+package delta
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// LazyRef is a lazily loaded reference to a single child entity (eg: Person -> Address), that can be nil.
+// The loader returns ErrNotFound when there is no referenced entity.
+type LazyRef[T Identifiable[I], I comparable] struct {
+	isSet      bool // the original reference was loaded
+	origExists bool
+	origID     I
+	orig       T
+	value      T
+	exists     bool
+	isDirty    bool
+	fn         func() (T, error)
+	fetchedAt  time.Time
+	ttl        time.Duration
+	clock      Clock
+	counters   *fieldCounters
+	gauge      *fieldGauge
+	budget     *memoryBudget
+	mu         sync.Mutex // serializes the loads, so that concurrent reads share a single load
+	mut        mutations
+}
+
+func NewLazyRef[T Identifiable[I], I comparable](fn func() (T, error), options ...Option) *LazyRef[T, I] {
+	opts := newOptions(options)
+	opts.only(refOptions, (*LazyRef[T, I])(nil))
+	return &LazyRef[T, I]{fn: loader0(opts, fn), clock: opts.clock, ttl: opts.ttl}
+}
+
+// NewRef creates a loaded reference to value.
+func NewRef[T Identifiable[I], I comparable](value T, options ...Option) *LazyRef[T, I] {
+	r := NewLazyRef[T](nil, options...)
+	r.isSet = true
+	r.origExists, r.origID, r.orig = true, value.ID(), value
+	r.value, r.exists = value, true
+	return r
+}
+
+// NewNilRef creates a loaded reference to nothing.
+func NewNilRef[T Identifiable[I], I comparable](options ...Option) *LazyRef[T, I] {
+	r := NewLazyRef[T, I](nil, options...)
+	r.isSet = true
+	return r
+}
+
+// Get returns the referenced entity, or ErrNotFound if the reference is nil.
+func (r *LazyRef[T, I]) Get() (T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire()
+	var zero T
+	if r.isSet || r.isDirty {
+		r.counters.hit()
+		r.budget.touch(r)
+		if !r.exists {
+			return zero, ErrNotFound
+		}
+		return r.value, nil
+	}
+	r.counters.miss()
+	start := r.now()
+	value, err := r.fn()
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		r.counters.failed(r.now().Sub(start))
+		return zero, err
+	}
+	r.fetchedAt = r.now()
+	r.isSet = true
+	if err != nil {
+		r.counters.loaded(0, r.fetchedAt.Sub(start))
+		r.syncGauge()
+		return zero, ErrNotFound
+	}
+	r.counters.loaded(sizeOf(value), r.fetchedAt.Sub(start))
+	r.origExists = true
+	r.origID = value.ID()
+	r.orig = value
+	r.value = value
+	r.exists = true
+	r.syncGauge()
+	r.budget.loaded(r)
+	return value, nil
+}
+
+// Set points the reference to value, replacing the referenced entity if it has a different ID.
+func (r *LazyRef[T, I]) Set(value T) {
+	r.mut.begin()
+	defer r.mut.end()
+
+	r.value = value
+	r.exists = true
+	r.isDirty = true
+	r.syncGauge()
+	r.mut.log(OpSet, "id", value.ID())
+}
+
+// Remove makes the reference nil.
+func (r *LazyRef[T, I]) Remove() {
+	r.mut.begin()
+	defer r.mut.end()
+
+	var zero T
+	r.value = zero
+	r.exists = false
+	r.isDirty = true
+	r.syncGauge()
+	r.mut.log(OpRemove)
+}
+
+// RefChange is the change of a reference.
+type RefChange[T Identifiable[I], I comparable] struct {
+	// Status is Added when a nil reference was set, Removed when the reference was made nil
+	// and Modified otherwise, including when the original reference was not loaded.
+	Status Status
+	Value  T // zero value when removed
+	// OldID is the ID of the original entity, if it was loaded and not nil.
+	OldID  I
+	HasOld bool
+}
+
+// Replaced returns true if the reference now points to a different entity.
+func (c *RefChange[T, I]) Replaced() bool {
+	return c.Status == Modified && c.HasOld && c.Value.ID() != c.OldID
+}
+
+// Change returns the change of the reference, or nil if there is none.
+// To know which entity was replaced or removed, the reference must be loaded before being changed.
+func (r *LazyRef[T, I]) Change() *RefChange[T, I] {
+	if !r.isDirty {
+		return nil
+	}
+	c := &RefChange[T, I]{Value: r.value}
+	if r.isSet && r.origExists {
+		c.OldID = r.origID
+		c.HasOld = true
+	}
+	switch {
+	case r.exists && r.isSet && !r.origExists:
+		c.Status = Added
+	case r.exists:
+		c.Status = Modified
+	case r.isSet && !r.origExists:
+		// was and is nil
+		return nil
+	default:
+		c.Status = Removed
+	}
+	return c
+}
+
+// FetchedAt returns when the reference was loaded, or the zero time if it was not loaded.
+func (r *LazyRef[T, I]) FetchedAt() time.Time {
+	return r.fetchedAt
+}
+
+func (r *LazyRef[T, I]) now() time.Time {
+	if r.clock == nil {
+		return SystemClock.Now()
+	}
+	return r.clock.Now()
+}
+
+// syncGauge updates the gauge with the current state of the reference.
+func (r *LazyRef[T, I]) syncGauge() {
+	if r.gauge == nil {
+		return
+	}
+	var cached, pending int64
+	if r.exists && (r.isSet || r.isDirty) {
+		cached = 1
+	}
+	if r.isDirty {
+		pending = 1
+	}
+	r.gauge.set(cached, pending)
+}
+
+// Evict drops the loaded entity, unless the reference was changed or there is no loader to reload it.
+func (r *LazyRef[T, I]) Evict() bool {
+	if !r.isSet || r.isDirty || r.fn == nil {
+		return false
+	}
+	var zero T
+	var zeroID I
+	r.value = zero
+	r.exists = false
+	r.origExists = false
+	r.origID = zeroID
+	r.orig = zero
+	r.isSet = false
+	r.fetchedAt = time.Time{}
+	r.syncGauge()
+	return true
+}
+
+// ============ Field ======================
+
+func (r *LazyRef[T, I]) patch(op PatchOp) error {
+	switch op.Op {
+	case OpSet:
+		value, err := convert[T](op.Value)
+		if err != nil {
+			return err
+		}
+		r.Set(value)
+	case OpRemove:
+		r.Remove()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedOperation, op.Op)
+	}
+	return nil
+}
+
+func (r *LazyRef[T, I]) operations() []PatchOp {
+	c := r.Change()
+	if c == nil {
+		return nil
+	}
+	if c.Status == Removed {
+		return []PatchOp{{Op: OpRemove}}
+	}
+	return []PatchOp{{Op: OpSet, Value: c.Value}}
+}
+
+func (r *LazyRef[T, I]) base() Field {
+	return r
+}
+
+func (r *LazyRef[T, I]) instrument(c *fieldCounters) {
+	r.counters = c
+	if r.gauge != nil {
+		// withdraw the contribution of a previous registration
+		r.gauge.release()
+	}
+	r.gauge = &fieldGauge{counters: c}
+	r.syncGauge()
+	runtime.AddCleanup(r, (*fieldGauge).release, r.gauge)
+}
+
+func (r *LazyRef[T, I]) setClock(clock Clock) {
+	r.clock = clock
+}
+
+func (r *LazyRef[T, I]) footprint() int {
+	if !r.exists {
+		return 0
+	}
+	return sizeOf(r.value)
+}
+
+func (r *LazyRef[T, I]) setBudget(b *memoryBudget) {
+	r.budget = b
+}
+
+func (r *LazyRef[T, I]) getBudget() *memoryBudget {
+	return r.budget
+}
+
+func (r *LazyRef[T, I]) locker() *sync.Mutex {
+	return &r.mu
+}
+
+func (r *LazyRef[T, I]) load() error {
+	_, err := r.Get()
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
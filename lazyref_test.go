@@ -0,0 +1,111 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func refTo(entity *testEntity, calls *int) *delta.LazyRef[*testEntity, string] {
+	return delta.NewLazyRef(func() (*testEntity, error) {
+		*calls++
+		if entity == nil {
+			return nil, delta.ErrNotFound
+		}
+		return entity, nil
+	})
+}
+
+func TestLazyRef_Get(t *testing.T) {
+	calls := 0
+	address := refTo(&testEntity{id: "1", name: "Main St"}, &calls)
+	v, err := address.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "Main St", v.name)
+	_, err = address.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Nil(t, address.Change())
+
+	calls = 0
+	none := refTo(nil, &calls)
+	_, err = none.Get()
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	_, err = none.Get()
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	assert.Equal(t, 1, calls)
+}
+
+func TestLazyRef_Change(t *testing.T) {
+	var calls int
+	t.Run("added", func(t *testing.T) {
+		address := delta.NewNilRef[*testEntity]()
+		address.Set(&testEntity{id: "1"})
+		c := address.Change()
+		require.NotNil(t, c)
+		assert.Equal(t, delta.Added, c.Status)
+		assert.False(t, c.HasOld)
+
+		// setting back to nil is no change
+		address.Remove()
+		assert.Nil(t, address.Change())
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		address := delta.NewRef(&testEntity{id: "1", name: "Main St"})
+		address.Set(&testEntity{id: "1", name: "High St"})
+		c := address.Change()
+		require.NotNil(t, c)
+		assert.Equal(t, delta.Modified, c.Status)
+		assert.Equal(t, "High St", c.Value.name)
+		assert.False(t, c.Replaced())
+	})
+
+	t.Run("replaced", func(t *testing.T) {
+		address := refTo(&testEntity{id: "1"}, &calls)
+		_, err := address.Get()
+		require.NoError(t, err)
+		address.Set(&testEntity{id: "2"})
+		c := address.Change()
+		require.NotNil(t, c)
+		assert.Equal(t, delta.Modified, c.Status)
+		assert.True(t, c.Replaced())
+		assert.Equal(t, "1", c.OldID)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		address := delta.NewRef(&testEntity{id: "1"})
+		address.Remove()
+		c := address.Change()
+		require.NotNil(t, c)
+		assert.Equal(t, delta.Removed, c.Status)
+		assert.Equal(t, "1", c.OldID)
+		_, err := address.Get()
+		require.ErrorIs(t, err, delta.ErrNotFound)
+	})
+
+	t.Run("not loaded", func(t *testing.T) {
+		calls = 0
+		address := refTo(&testEntity{id: "1"}, &calls)
+		address.Set(&testEntity{id: "2"})
+		v, err := address.Get()
+		require.NoError(t, err)
+		assert.Equal(t, "2", v.id)
+		assert.Equal(t, 0, calls)
+		c := address.Change()
+		require.NotNil(t, c)
+		assert.Equal(t, delta.Modified, c.Status)
+		assert.False(t, c.HasOld)
+	})
+}
+
+func TestLazyRef_Patch(t *testing.T) {
+	address := delta.NewRef(&testEntity{id: "1"})
+	tracker := delta.NewTracker()
+	tracker.Register("address", address)
+
+	require.NoError(t, delta.ApplyPatch(tracker, delta.Patch{{Op: delta.OpRemove, Path: "address"}}))
+	assert.Equal(t, delta.Patch{{Op: delta.OpRemove, Path: "address"}}, tracker.Compact())
+}
@@ -0,0 +1,380 @@
+package delta
+
+import (
+	"fmt"
+	"iter"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+type setItem struct {
+	status  Status
+	fetched bool // the member is known to be stored
+}
+
+// LazySet is a lazily loaded set of values without identity (eg: tags), tracking the added and removed members.
+type LazySet[T comparable] struct {
+	isSet     bool
+	isReset   bool
+	fetched   *linkedmap.Map[T, setItem]
+	fn        func(member T) ([]T, error) // function to load a member. If member is zero value, load all members.
+	fetchedAt time.Time
+	loadedAt  time.Time // when the oldest cached member was loaded
+	ttl       time.Duration
+	clock     Clock
+	counters  *fieldCounters
+	gauge     *fieldGauge
+	budget    *memoryBudget
+	mu        sync.Mutex // serializes the loads, so that concurrent reads share a single load
+	stride    int
+	mut       mutations
+
+	// state before the set was reset, to be able to discard the changes
+	beforeReset *linkedmap.Map[T, setItem]
+	wasSet      bool
+}
+
+func NewLazySet[T comparable](fn func(member T) ([]T, error), options ...Option) *LazySet[T] {
+	opts := newOptions(options)
+	opts.only(setOptions, (*LazySet[T])(nil))
+	return &LazySet[T]{
+		fn:      loader(opts, fn),
+		fetched: linkedmap.New(linkedmap.WithCapacity[T, setItem](opts.capacity)),
+		clock:   opts.clock,
+		ttl:     opts.ttl,
+		stride:  opts.stride,
+	}
+}
+
+func (s *LazySet[T]) GetAll() (iter.Seq[T], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	if s.isSet {
+		s.counters.hit()
+		s.budget.touch(s)
+		return s.members(), nil
+	}
+	s.counters.miss()
+	var zero T
+	start := s.now()
+	values, err := s.fn(zero)
+	if err != nil {
+		s.counters.failed(s.now().Sub(start))
+		return nil, err
+	}
+	s.fetchedAt = s.now()
+	s.counters.loaded(sizeOfAll(values), s.fetchedAt.Sub(start))
+	s.markLoaded(s.fetchedAt)
+
+	for _, v := range values {
+		item, ok := s.fetched.Get(v)
+		switch {
+		case !ok, item.status == Added, item.status == Absent:
+			// adding an existing member is not a change
+			s.put(v, setItem{status: Unchanged, fetched: true})
+		case item.status == Removed:
+			s.put(v, setItem{status: Removed, fetched: true})
+		}
+	}
+
+	s.isSet = true
+	s.budget.loaded(s)
+	return s.members(), nil
+}
+
+func (s *LazySet[T]) members() iter.Seq[T] {
+	it := strided2(s.fetched.Entries(), s.stride)
+	return func(yield func(T) bool) {
+		for v, item := range it {
+			if item.status == Removed || item.status == Absent {
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns true if the value is a member, loading only that member if the set is not loaded.
+func (s *LazySet[T]) Contains(member T) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	item, exists := s.fetched.Get(member)
+	if exists {
+		s.counters.hit()
+		s.budget.touch(s)
+		return item.status == Unchanged || item.status == Added, nil
+	}
+	if s.isSet {
+		s.counters.hit()
+		return false, nil
+	}
+
+	s.counters.miss()
+	start := s.now()
+	values, err := s.fn(member)
+	if err != nil {
+		s.counters.failed(s.now().Sub(start))
+		return false, err
+	}
+	loadedAt := s.now()
+	s.counters.loaded(sizeOfAll(values), loadedAt.Sub(start))
+	s.markLoaded(loadedAt)
+	if len(values) == 0 {
+		s.put(member, setItem{status: Absent})
+		return false, nil
+	}
+	s.put(member, setItem{status: Unchanged, fetched: true})
+	s.budget.loaded(s)
+	return true, nil
+}
+
+// Add adds a member.
+func (s *LazySet[T]) Add(member T) {
+	s.mut.begin()
+	defer s.mut.end()
+
+	item, exists := s.fetched.Get(member)
+	switch {
+	case !exists, item.status == Absent:
+		s.put(member, setItem{status: Added})
+	case item.status == Removed && item.fetched:
+		// back to what is stored
+		s.put(member, setItem{status: Unchanged, fetched: true})
+	case item.status == Removed:
+		s.put(member, setItem{status: Added})
+	}
+	item, _ = s.fetched.Get(member)
+	s.mut.log(OpSet, "id", member, "status", item.status)
+}
+
+// Remove removes a member, returning true if it was known to be a member.
+func (s *LazySet[T]) Remove(member T) bool {
+	s.mut.begin()
+	defer s.mut.end()
+	s.mut.log(OpRemove, "id", member)
+
+	item, exists := s.fetched.Get(member)
+	if !exists {
+		if !s.isSet {
+			s.put(member, setItem{status: Removed})
+		}
+		return false
+	}
+	switch item.status {
+	case Added:
+		s.delete(member)
+		return true
+	case Unchanged:
+		s.put(member, setItem{status: Removed, fetched: true})
+		return true
+	}
+	return false
+}
+
+func (s *LazySet[T]) Clear() {
+	s.mut.begin()
+	defer s.mut.end()
+
+	if !s.isReset {
+		s.beforeReset = s.fetched
+		s.wasSet = s.isSet
+	}
+	s.isSet = true
+	s.isReset = true
+	s.fetched = linkedmap.New[T, setItem]()
+	s.recount()
+	s.mut.log(OpClear)
+}
+
+func (s *LazySet[T]) IsReset() bool {
+	return s.isReset
+}
+
+type SetChanges[T comparable] struct {
+	Reset bool
+	Items iter.Seq[SetChange[T]]
+}
+
+type SetChange[T comparable] struct {
+	Value  T
+	Status Status // Added or Removed
+}
+
+func (s *LazySet[T]) Changes() SetChanges[T] {
+	return SetChanges[T]{
+		Reset: s.isReset,
+		Items: s.changesIterator(),
+	}
+}
+
+func (s *LazySet[T]) changesIterator() iter.Seq[SetChange[T]] {
+	it := strided2(s.fetched.Entries(), s.stride)
+	return func(yield func(SetChange[T]) bool) {
+		for v, item := range it {
+			if item.status != Added && item.status != Removed {
+				continue
+			}
+			if !yield(SetChange[T]{Value: v, Status: item.status}) {
+				return
+			}
+		}
+	}
+}
+
+// FetchedAt returns when all the members were loaded, or the zero time if they were not loaded.
+func (s *LazySet[T]) FetchedAt() time.Time {
+	return s.fetchedAt
+}
+
+func (s *LazySet[T]) now() time.Time {
+	if s.clock == nil {
+		return SystemClock.Now()
+	}
+	return s.clock.Now()
+}
+
+// put puts a member in the cache, keeping the gauges up to date.
+func (s *LazySet[T]) put(member T, item setItem) {
+	old, existed := s.fetched.Put(member, item)
+	if existed {
+		s.gauge.addStatus(old.status, -1)
+	}
+	s.gauge.addStatus(item.status, 1)
+}
+
+// delete deletes a member from the cache, keeping the gauges up to date.
+func (s *LazySet[T]) delete(member T) {
+	old, existed := s.fetched.Delete(member)
+	if existed {
+		s.gauge.addStatus(old.status, -1)
+	}
+}
+
+func (s *LazySet[T]) recount() {
+	if s.gauge == nil {
+		return
+	}
+	s.gauge.release()
+	for item := range s.fetched.Values() {
+		s.gauge.addStatus(item.status, 1)
+	}
+}
+
+// Evict drops the loaded members that have no pending changes, unless the set was cleared
+// or there is no loader to reload them.
+func (s *LazySet[T]) Evict() bool {
+	if s.isReset || s.fn == nil {
+		return false
+	}
+	fetched := linkedmap.New[T, setItem]()
+	for v, item := range s.fetched.Entries() {
+		if item.status == Unchanged || item.status == Absent {
+			continue
+		}
+		fetched.Put(v, item)
+	}
+	if fetched.Size() == s.fetched.Size() && !s.isSet {
+		return false
+	}
+	s.isSet = false
+	s.fetchedAt = time.Time{}
+	s.loadedAt = time.Time{}
+	s.fetched = fetched
+	s.recount()
+	return true
+}
+
+// ============ Field ======================
+
+func (s *LazySet[T]) patch(op PatchOp) error {
+	switch op.Op {
+	case OpSet:
+		member, err := convert[T](op.ID)
+		if err != nil {
+			return err
+		}
+		s.Add(member)
+	case OpRemove:
+		member, err := convert[T](op.ID)
+		if err != nil {
+			return err
+		}
+		s.Remove(member)
+	case OpClear:
+		s.Clear()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedOperation, op.Op)
+	}
+	return nil
+}
+
+func (s *LazySet[T]) operations() []PatchOp {
+	var ops []PatchOp
+	if s.isReset {
+		ops = append(ops, PatchOp{Op: OpClear})
+	}
+	for c := range s.changesIterator() {
+		switch c.Status {
+		case Added:
+			ops = append(ops, PatchOp{Op: OpSet, ID: c.Value})
+		case Removed:
+			ops = append(ops, PatchOp{Op: OpRemove, ID: c.Value})
+		}
+	}
+	return ops
+}
+
+func (s *LazySet[T]) base() Field {
+	return s
+}
+
+func (s *LazySet[T]) instrument(c *fieldCounters) {
+	s.counters = c
+	if s.gauge != nil {
+		// withdraw the contribution of a previous registration
+		s.gauge.release()
+	}
+	s.gauge = &fieldGauge{counters: c}
+	s.recount()
+	runtime.AddCleanup(s, (*fieldGauge).release, s.gauge)
+}
+
+func (s *LazySet[T]) setClock(clock Clock) {
+	s.clock = clock
+}
+
+func (s *LazySet[T]) footprint() int {
+	size := 0
+	for v, item := range s.fetched.Entries() {
+		if item.status != Removed && item.status != Absent {
+			size += sizeOf(v)
+		}
+	}
+	return size
+}
+
+func (s *LazySet[T]) setBudget(b *memoryBudget) {
+	s.budget = b
+}
+
+func (s *LazySet[T]) getBudget() *memoryBudget {
+	return s.budget
+}
+
+func (s *LazySet[T]) locker() *sync.Mutex {
+	return &s.mu
+}
+
+func (s *LazySet[T]) load() error {
+	_, err := s.GetAll()
+	return err
+}
@@ -0,0 +1,112 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setFetcher(members []string, calls *[]string) func(member string) ([]string, error) {
+	return func(member string) ([]string, error) {
+		*calls = append(*calls, member)
+		if member == "" {
+			return members, nil
+		}
+		if slices.Contains(members, member) {
+			return []string{member}, nil
+		}
+		return nil, nil
+	}
+}
+
+func setChanges(s *delta.LazySet[string]) []delta.SetChange[string] {
+	var changes []delta.SetChange[string]
+	for c := range s.Changes().Items {
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+func TestLazySet_Contains(t *testing.T) {
+	var calls []string
+	tags := delta.NewLazySet(setFetcher([]string{"go", "ddd"}, &calls))
+
+	ok, err := tags.Contains("go")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = tags.Contains("rust")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, err = tags.Contains("rust")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "rust"}, calls)
+
+	all, err := tags.GetAll()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"go", "ddd"}, slices.Collect(all))
+	assert.Equal(t, []string{"go", "rust", ""}, calls)
+
+	ok, err = tags.Contains("java")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Len(t, calls, 3)
+}
+
+func TestLazySet_Changes(t *testing.T) {
+	var calls []string
+	tags := delta.NewLazySet(setFetcher([]string{"go", "ddd"}, &calls))
+
+	tags.Add("rust")
+	tags.Add("go") // already a member, but not known yet
+	assert.False(t, tags.Remove("ddd"))
+	tags.Add("temp")
+	assert.True(t, tags.Remove("temp"))
+	assert.Empty(t, calls)
+	assert.Equal(t, []delta.SetChange[string]{
+		{Value: "rust", Status: delta.Added},
+		{Value: "go", Status: delta.Added},
+		{Value: "ddd", Status: delta.Removed},
+	}, setChanges(tags))
+
+	// loading drops the additions of existing members
+	all, err := tags.GetAll()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"go", "rust"}, slices.Collect(all))
+	assert.Equal(t, []delta.SetChange[string]{
+		{Value: "rust", Status: delta.Added},
+		{Value: "ddd", Status: delta.Removed},
+	}, setChanges(tags))
+
+	// adding back a removed member cancels the removal
+	tags.Add("ddd")
+	assert.Equal(t, []delta.SetChange[string]{
+		{Value: "rust", Status: delta.Added},
+	}, setChanges(tags))
+
+	tags.Clear()
+	tags.Add("java")
+	assert.True(t, tags.Changes().Reset)
+	assert.Equal(t, []delta.SetChange[string]{
+		{Value: "java", Status: delta.Added},
+	}, setChanges(tags))
+}
+
+func TestLazySet_Patch(t *testing.T) {
+	var calls []string
+	tags := delta.NewLazySet(setFetcher([]string{"go"}, &calls))
+	tracker := delta.NewTracker()
+	tracker.Register("tags", tags)
+
+	err := delta.ApplyPatch(tracker, delta.Patch{
+		{Op: delta.OpSet, Path: "tags", ID: "rust"},
+		{Op: delta.OpRemove, Path: "tags", ID: "go"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, delta.Patch{
+		{Op: delta.OpSet, Path: "tags", ID: "rust"},
+		{Op: delta.OpRemove, Path: "tags", ID: "go"},
+	}, tracker.Compact())
+}
@@ -0,0 +1,57 @@
+package delta
+
+// LazyLinks is a lazily loaded many-to-many association between the aggregate and other aggregates,
+// identified by their IDs (eg: the courses a student is enrolled in), tracking only the added and removed links,
+// to be persisted as inserts and deletes in a join table.
+type LazyLinks[I comparable] struct {
+	LazySet[I]
+}
+
+// NewLazyLinks creates an association whose linked IDs are loaded by fn.
+// If id is the zero value, fn returns all the linked IDs, otherwise it returns id if it is linked.
+func NewLazyLinks[I comparable](fn func(id I) ([]I, error), options ...Option) *LazyLinks[I] {
+	return &LazyLinks[I]{LazySet: *NewLazySet(fn, options...)}
+}
+
+// Link links the aggregate with id.
+func (l *LazyLinks[I]) Link(id I) {
+	l.Add(id)
+}
+
+// Unlink removes the link with id, returning true if it was known to be linked.
+func (l *LazyLinks[I]) Unlink(id I) bool {
+	return l.Remove(id)
+}
+
+// IsLinked returns true if the aggregate is linked with id, loading only that link if the links are not loaded.
+func (l *LazyLinks[I]) IsLinked(id I) (bool, error) {
+	return l.Contains(id)
+}
+
+// LinkChanges are the pending changes of the links.
+type LinkChanges[I comparable] struct {
+	// Reset is true if all the stored links must be deleted before inserting the added ones.
+	Reset    bool
+	Linked   []I // links to insert
+	Unlinked []I // links to delete
+}
+
+// IsEmpty returns true if there is nothing to persist.
+func (c LinkChanges[I]) IsEmpty() bool {
+	return !c.Reset && len(c.Linked) == 0 && len(c.Unlinked) == 0
+}
+
+// LinkChanges returns the pending changes of the links, in the order they were made.
+func (l *LazyLinks[I]) LinkChanges() LinkChanges[I] {
+	changes := l.Changes()
+	c := LinkChanges[I]{Reset: changes.Reset}
+	for item := range changes.Items {
+		switch item.Status {
+		case Added:
+			c.Linked = append(c.Linked, item.Value)
+		case Removed:
+			c.Unlinked = append(c.Unlinked, item.Value)
+		}
+	}
+	return c
+}
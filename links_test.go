@@ -0,0 +1,62 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCourses(calls *int) *delta.LazyLinks[int] {
+	return delta.NewLazyLinks(func(id int) ([]int, error) {
+		*calls++
+		linked := []int{1, 2, 3}
+		if id == 0 {
+			return linked, nil
+		}
+		for _, v := range linked {
+			if v == id {
+				return []int{id}, nil
+			}
+		}
+		return nil, nil
+	})
+}
+
+func TestLazyLinks_LinkChanges(t *testing.T) {
+	var calls int
+	courses := newCourses(&calls)
+	ok, err := courses.IsLinked(2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	courses.Link(4)
+	courses.Link(2)                    // already linked
+	assert.False(t, courses.Unlink(3)) // not loaded, but still unlinked
+	courses.Link(5)
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, delta.LinkChanges[int]{
+		Linked:   []int{4, 5},
+		Unlinked: []int{3},
+	}, courses.LinkChanges())
+
+	courses.AcceptChanges()
+	assert.True(t, courses.LinkChanges().IsEmpty())
+
+	courses.Clear()
+	courses.Link(7)
+	assert.Equal(t, delta.LinkChanges[int]{Reset: true, Linked: []int{7}}, courses.LinkChanges())
+}
+
+func TestLazyLinks_Tracker(t *testing.T) {
+	var calls int
+	courses := newCourses(&calls)
+	tracker := delta.NewTracker()
+	tracker.Register("courses", courses)
+
+	courses.Link(4)
+	assert.True(t, tracker.IsDirty())
+	assert.Equal(t, delta.Patch{{Op: delta.OpSet, Path: "courses", ID: 4}}, tracker.Pending())
+}
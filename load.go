@@ -0,0 +1,218 @@
+package delta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// WithAggregateID sets the identity of the aggregate, used to identify it in load errors.
+func WithAggregateID(id any) TrackerOption {
+	return trackerOptionFunc(func(t *Tracker) {
+		t.aggID = id
+	})
+}
+
+// LoadError is the failure to load a field of an aggregate, or of a field named with Named. It matches ErrLoad.
+type LoadError struct {
+	AggregateType string // empty if the tracker has no aggregate type
+	AggregateID   any    // nil if the tracker has no aggregate id
+	Field         string
+	Err           error
+}
+
+func (e *LoadError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("load")
+	if e.AggregateType != "" {
+		sb.WriteString(" ")
+		sb.WriteString(e.AggregateType)
+	}
+	if e.AggregateID != nil {
+		fmt.Fprintf(&sb, " %v", e.AggregateID)
+	}
+	fmt.Fprintf(&sb, " field %q: %v", e.Field, e.Err)
+	return sb.String()
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+func (e *LoadError) Is(target error) bool {
+	return target == ErrLoad
+}
+
+// Load loads the named fields, or all the registered fields if no name is given.
+// Every field is loaded, even if some fail, and the failures are joined as *LoadError.
+func (t *Tracker) Load(names ...string) error {
+	var errs []error
+	load := func(name string, field Field) {
+		if err := field.load(); err != nil {
+			errs = append(errs, t.loadError(name, err))
+		}
+	}
+	if len(names) == 0 {
+		for name, field := range t.fields.Entries() {
+			load(name, field)
+		}
+		return errors.Join(errs...)
+	}
+	for _, name := range names {
+		field, ok := t.fields.Get(name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrUnknownField, name))
+			continue
+		}
+		load(name, field)
+	}
+	return errors.Join(errs...)
+}
+
+func (t *Tracker) loadError(name string, err error) *LoadError {
+	if le, ok := err.(*LoadError); ok {
+		// a named field keeps its own name
+		named := *le
+		named.AggregateType, named.AggregateID = t.aggType, t.aggID
+		return &named
+	}
+	return &LoadError{
+		AggregateType: t.aggType,
+		AggregateID:   t.aggID,
+		Field:         name,
+		Err:           err,
+	}
+}
+
+func (v *LazyScalar[T]) load() error {
+	_, err := v.Get()
+	return err
+}
+
+func (s *LazySlice[T, I]) load() error {
+	_, err := s.GetAll()
+	return err
+}
+
+func (d *DynamicFields) load() error {
+	_, err := d.GetAll()
+	return err
+}
+
+// FieldLoad is the outcome of loading a field.
+type FieldLoad struct {
+	Field string
+	Err   error // *LoadError, or nil on success
+}
+
+// LoadReport is the outcome of loading the registered fields, in registration order.
+type LoadReport []FieldLoad
+
+// OK returns true if all the fields were loaded.
+func (r LoadReport) OK() bool {
+	for _, l := range r {
+		if l.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the names of the fields that failed to load.
+func (r LoadReport) Failed() []string {
+	var names []string
+	for _, l := range r {
+		if l.Err != nil {
+			names = append(names, l.Field)
+		}
+	}
+	return names
+}
+
+// Err returns the failures joined, or nil if all the fields were loaded.
+func (r LoadReport) Err() error {
+	var errs []error
+	for _, l := range r {
+		if l.Err != nil {
+			errs = append(errs, l.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LoadAll loads all the registered fields concurrently and reports the outcome per field.
+// Fields not yet loaded when the context is done fail with the context error.
+// The memory budget, if any, is not enforced while loading, so that the aggregate is fully materialized.
+func (t *Tracker) LoadAll(ctx context.Context) LoadReport {
+	t.budget.pause(true)
+	defer t.budget.pause(false)
+
+	// a field registered under several names is only loaded once
+	var report LoadReport
+	loads := map[Field]*error{}
+	var wg sync.WaitGroup
+	for name, field := range t.fields.Entries() {
+		report = append(report, FieldLoad{Field: name})
+		if _, ok := loads[field.base()]; ok {
+			continue
+		}
+		var err error
+		loads[field.base()] = &err
+		wg.Go(func() {
+			if err = ctx.Err(); err != nil {
+				return
+			}
+			err = field.load()
+		})
+	}
+	wg.Wait()
+
+	for i, l := range report {
+		field, _ := t.fields.Get(l.Field)
+		if err := *loads[field.base()]; err != nil {
+			report[i].Err = t.loadError(l.Field, err)
+		}
+	}
+	return report
+}
+
+// Preloadable is a lazy field that can be loaded ahead of its use, with Preload.
+// It is implemented by the tracked types of this package.
+type Preloadable interface {
+	load() error
+	getBudget() *memoryBudget
+}
+
+// Preload loads the fields concurrently, returning the first failure.
+// After a failure, or when the context is done, the fields not yet being loaded are skipped.
+// It returns once all the started loads are done.
+// As in LoadAll, the memory budget of the fields, if any, is not enforced while loading.
+func Preload(ctx context.Context, fields ...Preloadable) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	budgets := map[*memoryBudget]bool{}
+	for _, field := range fields {
+		if b := field.getBudget(); b != nil && !budgets[b] {
+			budgets[b] = true
+			b.pause(true)
+			defer b.pause(false)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, field := range fields {
+		wg.Go(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := field.load(); err != nil {
+				cancel(err)
+			}
+		})
+	}
+	wg.Wait()
+	return context.Cause(ctx)
+}
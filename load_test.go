@@ -0,0 +1,142 @@
+package delta_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_Load(t *testing.T) {
+	errBoom := errors.New("connection refused")
+	name := delta.NewLazy(func() (string, error) {
+		return "John", nil
+	})
+	cars := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		return nil, errBoom
+	})
+
+	tracker := delta.NewTracker(delta.WithAggregateType("person"), delta.WithAggregateID(123))
+	tracker.Register("name", name)
+	tracker.Register("cars", cars)
+
+	err := tracker.Load()
+	require.ErrorIs(t, err, errBoom)
+	assert.EqualError(t, err, `load person 123 field "cars": connection refused`)
+
+	var loadErr *delta.LoadError
+	require.ErrorAs(t, err, &loadErr)
+	assert.Equal(t, "person", loadErr.AggregateType)
+	assert.Equal(t, 123, loadErr.AggregateID)
+	assert.Equal(t, "cars", loadErr.Field)
+
+	// the other fields are loaded anyway
+	assert.False(t, name.FetchedAt().IsZero())
+
+	require.NoError(t, tracker.Load("name"))
+	require.ErrorIs(t, tracker.Load("address"), delta.ErrUnknownField)
+}
+
+func TestLoadError_Error(t *testing.T) {
+	err := &delta.LoadError{Field: "cars", Err: errors.New("timeout")}
+	assert.EqualError(t, err, `load field "cars": timeout`)
+}
+
+func TestTracker_LoadAll(t *testing.T) {
+	errBoom := errors.New("connection refused")
+	name := delta.NewLazy(func() (string, error) {
+		return "John", nil
+	})
+	cars := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		return nil, errBoom
+	})
+	attributes := delta.NewDynamicFields(func(key string) (map[string]any, error) {
+		return map[string]any{"height": 180}, nil
+	})
+
+	tracker := delta.NewTracker(delta.WithAggregateType("person"), delta.WithAggregateID(123), delta.WithMemoryBudget(1))
+	tracker.Register("name", name)
+	tracker.Register("cars", cars)
+	tracker.Register("attributes", attributes)
+
+	report := tracker.LoadAll(context.Background())
+	assert.False(t, report.OK())
+	assert.Equal(t, []string{"cars"}, report.Failed())
+	require.Len(t, report, 3)
+	assert.Equal(t, "name", report[0].Field)
+	require.NoError(t, report[0].Err)
+	assert.EqualError(t, report[1].Err, `load person 123 field "cars": connection refused`)
+	require.ErrorIs(t, report.Err(), errBoom)
+
+	// nothing was evicted
+	assert.False(t, name.FetchedAt().IsZero())
+	assert.False(t, attributes.FetchedAt().IsZero())
+}
+
+func TestTracker_LoadAll_Canceled(t *testing.T) {
+	calls := 0
+	name := delta.NewLazy(func() (string, error) {
+		calls++
+		return "John", nil
+	})
+	tracker := delta.NewTracker()
+	tracker.Register("name", name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := tracker.LoadAll(ctx)
+	assert.Equal(t, []string{"name"}, report.Failed())
+	require.ErrorIs(t, report.Err(), context.Canceled)
+	assert.Equal(t, 0, calls)
+}
+
+func TestPreload(t *testing.T) {
+	name := delta.NewLazy(func() (string, error) {
+		return "John", nil
+	})
+	cars := delta.NewLazySlice(fetcher([]*testEntity{{id: "1", name: "entity1"}}))
+
+	require.NoError(t, delta.Preload(context.Background(), name, cars))
+	assert.False(t, name.FetchedAt().IsZero())
+	assert.False(t, cars.FetchedAt().IsZero())
+}
+
+func TestPreload_MemoryBudget(t *testing.T) {
+	name := delta.NewLazy(func() (string, error) {
+		return "John", nil
+	})
+	nickname := delta.NewLazy(func() (string, error) {
+		return "Johnny", nil
+	})
+	tracker := delta.NewTracker(delta.WithMemoryBudget(1))
+	tracker.Register("name", name)
+	tracker.Register("nickname", nickname)
+
+	require.NoError(t, delta.Preload(context.Background(), name, nickname))
+
+	// nothing was evicted
+	assert.False(t, name.FetchedAt().IsZero())
+	assert.False(t, nickname.FetchedAt().IsZero())
+}
+
+func TestPreload_Fails(t *testing.T) {
+	errBoom := errors.New("connection refused")
+	name := delta.NewLazy(func() (string, error) {
+		return "", errBoom
+	})
+	calls := 0
+	photo := delta.NewLazy(func() ([]byte, error) {
+		calls++
+		return nil, nil
+	})
+
+	require.ErrorIs(t, delta.Preload(context.Background(), name), errBoom)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, delta.Preload(ctx, photo), context.Canceled)
+	assert.Equal(t, 0, calls)
+}
@@ -0,0 +1,74 @@
+package delta
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger logs, at debug level, the loads and cache hits of the registered fields, their mutations made with their methods
+// (eg: Set, Remove) and, on AcceptAll, the summary of the changes being accepted.
+// Records carry the registered field name and, for collections, the id and status of the item.
+func WithLogger(logger *slog.Logger) TrackerOption {
+	return trackerOptionFunc(func(t *Tracker) {
+		t.logger = logger
+	})
+}
+
+// log logs a mutation, if there is a logger.
+func (m *mutations) log(op Operation, attrs ...any) {
+	if m.logger == nil {
+		return
+	}
+	m.logger.Debug("delta mutation", append([]any{"field", m.field, "op", op}, attrs...)...)
+}
+
+func (v *LazyScalar[T]) setLogger(logger *slog.Logger, field string) {
+	v.mut.logger, v.mut.field = logger, field
+}
+
+func (s *LazySlice[T, I]) setLogger(logger *slog.Logger, field string) {
+	s.mut.logger, s.mut.field = logger, field
+}
+
+func (d *DynamicFields) setLogger(logger *slog.Logger, field string) {
+	d.mut.logger, d.mut.field = logger, field
+}
+
+func (m *LazyMap[K, V]) setLogger(logger *slog.Logger, field string) {
+	m.mut.logger, m.mut.field = logger, field
+}
+
+func (s *LazySet[T]) setLogger(logger *slog.Logger, field string) {
+	s.mut.logger, s.mut.field = logger, field
+}
+
+func (r *LazyRef[T, I]) setLogger(logger *slog.Logger, field string) {
+	r.mut.logger, r.mut.field = logger, field
+}
+
+func (l *LazyList[T, I]) setLogger(logger *slog.Logger, field string) {
+	l.mut.logger, l.mut.field = logger, field
+}
+
+// logChanges logs the number of pending operations, per operation, of each dirty field.
+func (t *Tracker) logChanges() {
+	if t.logger == nil || !t.logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	for name, field := range t.fields.Entries() {
+		if !field.IsDirty() {
+			continue
+		}
+		counts := map[Operation]int{}
+		for _, op := range field.operations() {
+			counts[op.Op]++
+		}
+		attrs := []any{"field", name}
+		for _, op := range []Operation{OpClear, OpSetAll, OpSet, OpRemove} {
+			if n := counts[op]; n > 0 {
+				attrs = append(attrs, string(op), n)
+			}
+		}
+		t.logger.Debug("delta changes", attrs...)
+	}
+}
@@ -0,0 +1,60 @@
+package delta_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "elapsed" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	name := delta.NewLazy(func() (string, error) { return "Paulo", nil })
+	items := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	tracker := delta.NewTracker(delta.WithLogger(newTestLogger(&buf)))
+	tracker.Register("name", name)
+	tracker.Register("items", items)
+
+	_, err := name.Get()
+	require.NoError(t, err)
+	_, err = name.Get()
+	require.NoError(t, err)
+	name.Set("Quintans")
+	_, err = items.GetAll()
+	require.NoError(t, err)
+	items.Set(&testEntity{id: "1", name: "changed"})
+	items.Set(&testEntity{id: "3", name: "entity3"})
+	items.Remove("2")
+	tracker.AcceptAll()
+
+	assert.Equal(t, []string{
+		`level=DEBUG msg="delta load" field=name`,
+		`level=DEBUG msg="delta cache hit" field=name`,
+		`level=DEBUG msg="delta mutation" field=name op=set dirty=true`,
+		`level=DEBUG msg="delta load" field=items`,
+		`level=DEBUG msg="delta mutation" field=items op=set id=1 status=modified`,
+		`level=DEBUG msg="delta mutation" field=items op=set id=3 status=added`,
+		`level=DEBUG msg="delta mutation" field=items op=remove id=2`,
+		`level=DEBUG msg="delta changes" field=name set=1`,
+		`level=DEBUG msg="delta changes" field=items set=2 remove=1`,
+	}, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
@@ -0,0 +1,100 @@
+package delta
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// WithManyLoader sets the loader used by LazySlice.GetMany to load several items in a single call.
+// It panics when used with a collection of a different type.
+func WithManyLoader[T Identifiable[I], I comparable](fn func([]I) ([]T, error)) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optManyLoader
+		o.many = fn
+	})
+}
+
+func manyFor[T Identifiable[I], I comparable](opts options) func([]I) ([]T, error) {
+	if opts.many == nil {
+		return nil
+	}
+	many, ok := opts.many.(func([]I) ([]T, error))
+	if !ok {
+		panic(fmt.Sprintf("delta: WithManyLoader loader %T used with a collection of %s", opts.many, reflect.TypeFor[T]()))
+	}
+	return many
+}
+
+// GetMany returns the items with the given IDs, in the same order, skipping the ones that do not exist.
+// The items that are not cached are loaded in a single call of the many loader (see WithManyLoader),
+// or one by one if there is none.
+func (s *LazySlice[T, I]) GetMany(ids ...I) ([]T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	var absent []I
+	if !s.isSet {
+		var missing []I
+		for _, id := range ids {
+			if _, ok := s.fetched.Get(id); !ok && !slices.Contains(missing, id) {
+				missing = append(missing, id)
+			}
+		}
+		var err error
+		absent, err = s.loadMany(missing)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	values := make([]T, 0, len(ids))
+	for _, id := range ids {
+		if slices.Contains(absent, id) {
+			continue
+		}
+		value, err := s.get(id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// loadMany loads the items with the given IDs, returning the IDs of the ones that do not exist.
+func (s *LazySlice[T, I]) loadMany(ids []I) ([]I, error) {
+	if len(ids) == 0 || s.many == nil {
+		// left to Get
+		return nil, nil
+	}
+	s.counters.miss()
+	start := s.now()
+	values, err := s.many(ids)
+	if err != nil {
+		s.counters.failed(s.now().Sub(start))
+		return nil, err
+	}
+	loadedAt := s.now()
+	s.counters.loaded(sizeOfAll(values), loadedAt.Sub(start))
+	if s.loadedAt.IsZero() {
+		s.loadedAt = loadedAt
+	}
+	for _, v := range values {
+		s.put(v.ID(), Item[T, I]{value: v, status: Unchanged})
+	}
+	var absent []I
+	for _, id := range ids {
+		if _, ok := s.fetched.Get(id); !ok {
+			s.absent(id)
+			absent = append(absent, id)
+		}
+	}
+	s.budget.loaded(s)
+	return absent, nil
+}
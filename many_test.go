@@ -0,0 +1,47 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazySlice_GetMany(t *testing.T) {
+	ents := []*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+		{id: "3", name: "entity3"},
+	}
+	var calls [][]string
+	lazySlice := delta.NewLazySlice(
+		func(id string) ([]*testEntity, error) {
+			t.Fatal("items should be loaded in batch")
+			return nil, nil
+		},
+		delta.WithManyLoader(func(ids []string) ([]*testEntity, error) {
+			calls = append(calls, ids)
+			var result []*testEntity
+			for _, e := range ents {
+				for _, id := range ids {
+					if e.id == id {
+						result = append(result, e)
+					}
+				}
+			}
+			return result, nil
+		}),
+	)
+	lazySlice.Set(&testEntity{id: "1", name: "entity1_new"})
+
+	values, err := lazySlice.GetMany("3", "1", "2", "4", "3")
+	require.NoError(t, err)
+	assert.Equal(t, []*testEntity{ents[2], {id: "1", name: "entity1_new"}, ents[1], ents[2]}, values)
+	assert.Equal(t, [][]string{{"3", "2", "4"}}, calls)
+
+	// all cached, including the absent one
+	_, err = lazySlice.GetMany("2", "4")
+	require.NoError(t, err)
+	assert.Len(t, calls, 1)
+}
@@ -0,0 +1,207 @@
+package delta
+
+import (
+	"iter"
+	"maps"
+	"slices"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// mapItem is a key of a LazyMap or DynamicFields, with its change state.
+type mapItem[V any] struct {
+	value  V
+	status Status
+	old    V    // fetched value of a modified or removed key
+	hasOld bool // false if the key was changed without being fetched
+}
+
+// set returns the item with a new value.
+func (item mapItem[V]) set(value V) mapItem[V] {
+	switch item.status {
+	case Absent, Added:
+		item.status = Added
+	case Unchanged:
+		item.status = Modified
+		item.old, item.hasOld = item.value, true
+	case Removed:
+		item.status = Modified
+	}
+	item.value = value
+	return item
+}
+
+// remove returns the removed item.
+func (item mapItem[V]) remove() mapItem[V] {
+	if item.status == Unchanged {
+		item.old, item.hasOld = item.value, true
+	}
+	var zero V
+	item.value = zero
+	item.status = Removed
+	return item
+}
+
+// fetched returns the item reconciled with the fetched value.
+func (item mapItem[V]) fetched(value V) mapItem[V] {
+	switch {
+	case item.status == Absent:
+		item.value, item.status = value, Unchanged
+	case item.status == Added:
+		item.status = Modified
+		item.old, item.hasOld = value, true
+	case !item.hasOld && item.status != Unchanged && item.status != Absent:
+		item.old, item.hasOld = value, true
+	}
+	return item
+}
+
+// mapItems is the cache of the keys of a LazyMap or DynamicFields, keeping the gauge up to date.
+type mapItems[K comparable, V any] struct {
+	fetched *linkedmap.Map[K, mapItem[V]]
+	gauge   *fieldGauge
+}
+
+func newMapItems[K comparable, V any](capacity int) mapItems[K, V] {
+	return mapItems[K, V]{fetched: linkedmap.New(linkedmap.WithCapacity[K, mapItem[V]](capacity))}
+}
+
+// put puts an item in the cache, keeping the gauges up to date.
+func (c *mapItems[K, V]) put(key K, item mapItem[V]) {
+	old, existed := c.fetched.Put(key, item)
+	if existed {
+		c.gauge.addStatus(old.status, -1)
+	}
+	c.gauge.addStatus(item.status, 1)
+}
+
+// delete deletes an item from the cache, keeping the gauges up to date.
+func (c *mapItems[K, V]) delete(key K) {
+	old, existed := c.fetched.Delete(key)
+	if existed {
+		c.gauge.addStatus(old.status, -1)
+	}
+}
+
+func (c *mapItems[K, V]) recount() {
+	if c.gauge == nil {
+		return
+	}
+	c.gauge.release()
+	for v := range c.fetched.Values() {
+		c.gauge.addStatus(v.status, 1)
+	}
+}
+
+// mergeAll puts all the loaded values, reconciled with their pending changes.
+// Keys are put in order (see compareIDs), so that the iteration order does not depend on the order of the Go map.
+func (c *mapItems[K, V]) mergeAll(values map[K]V) {
+	for _, k := range slices.SortedFunc(maps.Keys(values), compareIDs[K]) {
+		if item, ok := c.fetched.Get(k); ok {
+			c.put(k, item.fetched(values[k]))
+		} else {
+			c.put(k, mapItem[V]{value: values[k], status: Unchanged})
+		}
+	}
+}
+
+// setKey sets the value of a key, returning its item.
+func (c *mapItems[K, V]) setKey(key K, value V) mapItem[V] {
+	item, exists := c.fetched.Get(key)
+	if exists {
+		item = item.set(value)
+	} else {
+		item = mapItem[V]{value: value, status: Added}
+	}
+	c.put(key, item)
+	return item
+}
+
+// removeKey removes a key, returning true if it was known to exist.
+func (c *mapItems[K, V]) removeKey(key K) bool {
+	item, exists := c.fetched.Get(key)
+	if !exists {
+		c.put(key, mapItem[V]{status: Removed})
+		return false
+	}
+	if item.status == Added {
+		c.delete(key)
+		return true
+	}
+	c.put(key, item.remove())
+	return true
+}
+
+// dropUnchanged drops the keys without pending changes, returning how many were dropped.
+func (c *mapItems[K, V]) dropUnchanged() int {
+	fetched := linkedmap.New[K, mapItem[V]]()
+	for k, item := range c.fetched.Entries() {
+		if item.status == Unchanged || item.status == Absent {
+			continue
+		}
+		fetched.Put(k, item)
+	}
+	dropped := c.fetched.Size() - fetched.Size()
+	if dropped > 0 {
+		c.fetched = fetched
+		c.recount()
+	}
+	return dropped
+}
+
+// present iterates over the values of the keys that exist.
+func (c *mapItems[K, V]) present(stride int) iter.Seq2[K, V] {
+	it := strided2(c.fetched.Entries(), stride)
+	return func(yield func(K, V) bool) {
+		for k, v := range it {
+			if v.status == Removed || v.status == Absent {
+				continue
+			}
+			if !yield(k, v.value) {
+				return
+			}
+		}
+	}
+}
+
+// changed iterates over the keys with pending changes.
+func (c *mapItems[K, V]) changed(stride int) iter.Seq2[K, mapItem[V]] {
+	it := strided2(c.fetched.Entries(), stride)
+	return func(yield func(K, mapItem[V]) bool) {
+		for k, v := range it {
+			if v.status == Unchanged || v.status == Absent {
+				continue
+			}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// keyOperations returns the operations that reproduce the pending changes of the keys.
+func (c *mapItems[K, V]) keyOperations(reset bool) []PatchOp {
+	var ops []PatchOp
+	if reset {
+		ops = append(ops, PatchOp{Op: OpClear})
+	}
+	for k, v := range c.changed(0) {
+		switch v.status {
+		case Added, Modified:
+			ops = append(ops, PatchOp{Op: OpSet, ID: k, Value: v.value})
+		case Removed:
+			ops = append(ops, PatchOp{Op: OpRemove, ID: k})
+		}
+	}
+	return ops
+}
+
+func (c *mapItems[K, V]) footprint() int {
+	size := 0
+	for item := range c.fetched.Values() {
+		if item.status != Removed && item.status != Absent {
+			size += sizeOf(item.value)
+		}
+	}
+	return size
+}
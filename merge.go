@@ -0,0 +1,125 @@
+package delta
+
+import (
+	"time"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// MergeStrategy resolves two changes for the same item, ours being the earlier and theirs the later one.
+// It returns false if the item ends up without changes.
+type MergeStrategy[T Identifiable[I], I comparable] func(ours, theirs SliceChange[I, T]) (SliceChange[I, T], bool)
+
+// Sequential combines the changes as if theirs was applied after ours:
+//
+//	ours \ theirs | Added    | Modified | Removed
+//	Added         | Added    | Added    | (none)
+//	Modified      | Modified | Modified | Removed
+//	Removed       | Modified | Modified | Removed
+//
+// The value is the one of theirs and the old value is the one before ours, if any.
+// Added items have no old value.
+func Sequential[T Identifiable[I], I comparable](ours, theirs SliceChange[I, T]) (SliceChange[I, T], bool) {
+	if ours.HasOld {
+		theirs.Old, theirs.HasOld = ours.Old, true
+	}
+	switch ours.Status {
+	case Added:
+		switch theirs.Status {
+		case Removed:
+			return SliceChange[I, T]{}, false
+		case Added, Modified:
+			var zero T
+			theirs.Status = Added
+			theirs.Old, theirs.HasOld = zero, false
+		}
+	case Modified:
+		if theirs.Status == Added {
+			theirs.Status = Modified
+		}
+	case Removed:
+		if theirs.Status == Added {
+			theirs.Status = Modified
+		}
+	}
+	return theirs, true
+}
+
+// Ours keeps our change, discarding theirs.
+func Ours[T Identifiable[I], I comparable](ours, _ SliceChange[I, T]) (SliceChange[I, T], bool) {
+	return ours, true
+}
+
+// Theirs keeps their change, discarding ours.
+func Theirs[T Identifiable[I], I comparable](_, theirs SliceChange[I, T]) (SliceChange[I, T], bool) {
+	return theirs, true
+}
+
+// Newest keeps the change whose value has the most recent timestamp, preferring theirs on ties.
+// Since removals do not carry a value, they are combined with Sequential.
+func Newest[T Identifiable[I], I comparable](timestamp func(T) time.Time) MergeStrategy[T, I] {
+	return func(ours, theirs SliceChange[I, T]) (SliceChange[I, T], bool) {
+		if ours.Status == Removed || theirs.Status == Removed {
+			return Sequential(ours, theirs)
+		}
+		if timestamp(ours.Value).After(timestamp(theirs.Value)) {
+			return ours, true
+		}
+		return theirs, true
+	}
+}
+
+// Merger merges collection changes using a policy that can be defined once per aggregate type.
+type Merger[T Identifiable[I], I comparable] struct {
+	// Strategy is used when both sides changed the same item. Defaults to Sequential.
+	Strategy MergeStrategy[T, I]
+	// ByStatus overrides Strategy according to the status of their change.
+	ByStatus map[Status]MergeStrategy[T, I]
+}
+
+// MergeChanges merges b into a, as if b happened after a, combining the changes of the same item with Sequential.
+// If b is a reset, the changes of a are discarded.
+func MergeChanges[T Identifiable[I], I comparable](a, b Changes[T, I]) Changes[T, I] {
+	return Merger[T, I]{}.Merge(a, b)
+}
+
+// Merge merges theirs into ours.
+// If theirs is a reset, our changes are discarded.
+func (m Merger[T, I]) Merge(ours, theirs Changes[T, I]) Changes[T, I] {
+	merged := linkedmap.New[I, SliceChange[I, T]]()
+	if !theirs.Reset && ours.Items != nil {
+		for c := range ours.Items {
+			merged.Put(c.ID, c)
+		}
+	}
+	if theirs.Items != nil {
+		for c := range theirs.Items {
+			current, ok := merged.Get(c.ID)
+			if !ok {
+				merged.Put(c.ID, c)
+				continue
+			}
+			resolved, ok := m.strategy(c.Status)(current, c)
+			if !ok {
+				merged.Delete(c.ID)
+				continue
+			}
+			merged.Put(c.ID, resolved)
+		}
+	}
+
+	return Changes[T, I]{
+		Reset: ours.Reset || theirs.Reset,
+		Items: merged.Values(),
+	}
+}
+
+func (m Merger[T, I]) strategy(status Status) MergeStrategy[T, I] {
+	if s, ok := m.ByStatus[status]; ok && s != nil {
+		return s
+	}
+	if m.Strategy != nil {
+		return m.Strategy
+	}
+	return Sequential[T, I]
+}
@@ -0,0 +1,172 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stampedEntity struct {
+	id        string
+	updatedAt time.Time
+}
+
+func (e *stampedEntity) ID() string {
+	return e.id
+}
+
+func changesOf[T delta.Identifiable[I], I comparable](reset bool, items ...delta.SliceChange[I, T]) delta.Changes[T, I] {
+	return delta.Changes[T, I]{
+		Reset: reset,
+		Items: slices.Values(items),
+	}
+}
+
+func TestMergeChanges(t *testing.T) {
+	e1 := &testEntity{id: "1", name: "entity1"}
+	e2 := &testEntity{id: "2", name: "entity2"}
+	e3 := &testEntity{id: "3", name: "entity3"}
+	e3b := &testEntity{id: "3", name: "entity3b"}
+
+	a := changesOf(false,
+		delta.SliceChange[string, *testEntity]{ID: "1", Value: e1, Status: delta.Added},
+		delta.SliceChange[string, *testEntity]{ID: "2", Value: e2, Status: delta.Added},
+		delta.SliceChange[string, *testEntity]{ID: "3", Value: e3, Status: delta.Modified},
+	)
+	b := changesOf(false,
+		delta.SliceChange[string, *testEntity]{ID: "1", Status: delta.Removed},
+		delta.SliceChange[string, *testEntity]{ID: "2", Value: e2, Status: delta.Modified},
+		delta.SliceChange[string, *testEntity]{ID: "3", Value: e3b, Status: delta.Modified},
+		delta.SliceChange[string, *testEntity]{ID: "4", Status: delta.Removed},
+	)
+
+	merged := delta.MergeChanges(a, b)
+	assert.False(t, merged.Reset)
+	changes := slices.Collect(merged.Items)
+	require.Len(t, changes, 3)
+	assert.Equal(t, "2", changes[0].ID)
+	assert.Equal(t, delta.Added, changes[0].Status)
+	assert.Equal(t, "3", changes[1].ID)
+	assert.Equal(t, delta.Modified, changes[1].Status)
+	assert.Equal(t, "entity3b", changes[1].Value.name)
+	assert.Equal(t, "4", changes[2].ID)
+	assert.Equal(t, delta.Removed, changes[2].Status)
+}
+
+func TestMergeChanges_TheirsReset(t *testing.T) {
+	e1 := &testEntity{id: "1", name: "entity1"}
+	e2 := &testEntity{id: "2", name: "entity2"}
+
+	a := changesOf(false, delta.SliceChange[string, *testEntity]{ID: "1", Value: e1, Status: delta.Added})
+	b := changesOf(true, delta.SliceChange[string, *testEntity]{ID: "2", Value: e2, Status: delta.Added})
+
+	merged := delta.MergeChanges(a, b)
+	assert.True(t, merged.Reset)
+	changes := slices.Collect(merged.Items)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "2", changes[0].ID)
+}
+
+func TestMerger_Strategies(t *testing.T) {
+	older := &stampedEntity{id: "1", updatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	newer := &stampedEntity{id: "1", updatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)}
+
+	ours := changesOf(false, delta.SliceChange[string, *stampedEntity]{ID: "1", Value: newer, Status: delta.Modified})
+	theirs := changesOf(false, delta.SliceChange[string, *stampedEntity]{ID: "1", Value: older, Status: delta.Modified})
+
+	tests := []struct {
+		name     string
+		merger   delta.Merger[*stampedEntity, string]
+		expected *stampedEntity
+	}{
+		{"default", delta.Merger[*stampedEntity, string]{}, older},
+		{"ours", delta.Merger[*stampedEntity, string]{Strategy: delta.Ours[*stampedEntity, string]}, newer},
+		{"theirs", delta.Merger[*stampedEntity, string]{Strategy: delta.Ours[*stampedEntity, string], ByStatus: map[delta.Status]delta.MergeStrategy[*stampedEntity, string]{
+			delta.Modified: delta.Theirs[*stampedEntity, string],
+		}}, older},
+		{"newest", delta.Merger[*stampedEntity, string]{Strategy: delta.Newest[*stampedEntity, string](func(e *stampedEntity) time.Time {
+			return e.updatedAt
+		})}, newer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := slices.Collect(tt.merger.Merge(ours, theirs).Items)
+			require.Len(t, changes, 1)
+			assert.Same(t, tt.expected, changes[0].Value)
+		})
+	}
+}
+
+func TestMerger_CustomStrategy(t *testing.T) {
+	e1 := &testEntity{id: "1", name: "entity1"}
+
+	merger := delta.Merger[*testEntity, string]{
+		Strategy: func(ours, theirs delta.SliceChange[string, *testEntity]) (delta.SliceChange[string, *testEntity], bool) {
+			// removals never win
+			if theirs.Status == delta.Removed {
+				return ours, true
+			}
+			return theirs, true
+		},
+	}
+
+	merged := merger.Merge(
+		changesOf(false, delta.SliceChange[string, *testEntity]{ID: "1", Value: e1, Status: delta.Modified}),
+		changesOf(false, delta.SliceChange[string, *testEntity]{ID: "1", Status: delta.Removed}),
+	)
+	changes := slices.Collect(merged.Items)
+	require.Len(t, changes, 1)
+	assert.Equal(t, delta.Modified, changes[0].Status)
+	assert.Same(t, e1, changes[0].Value)
+}
+
+func TestSequential_Rules(t *testing.T) {
+	old := &testEntity{id: "1", name: "old"}
+	v1 := &testEntity{id: "1", name: "v1"}
+	v2 := &testEntity{id: "1", name: "v2"}
+	changeOf := func(status delta.Status) delta.SliceChange[string, *testEntity] {
+		switch status {
+		case delta.Added:
+			return delta.SliceChange[string, *testEntity]{ID: "1", Value: v1, Status: status}
+		case delta.Modified:
+			return delta.SliceChange[string, *testEntity]{ID: "1", Value: v1, Status: status, Old: old, HasOld: true}
+		default:
+			return delta.SliceChange[string, *testEntity]{ID: "1", Status: status, Old: old, HasOld: true}
+		}
+	}
+
+	tests := []struct {
+		ours, theirs delta.Status
+		want         delta.Status // Unchanged when the changes cancel
+	}{
+		{delta.Added, delta.Added, delta.Added},
+		{delta.Added, delta.Modified, delta.Added},
+		{delta.Added, delta.Removed, delta.Unchanged},
+		{delta.Modified, delta.Added, delta.Modified},
+		{delta.Modified, delta.Modified, delta.Modified},
+		{delta.Modified, delta.Removed, delta.Removed},
+		{delta.Removed, delta.Added, delta.Modified},
+		{delta.Removed, delta.Modified, delta.Modified},
+		{delta.Removed, delta.Removed, delta.Removed},
+	}
+	for _, tt := range tests {
+		theirs := changeOf(tt.theirs)
+		if theirs.Status != delta.Removed {
+			theirs.Value = v2
+		}
+		got, ok := delta.Sequential(changeOf(tt.ours), theirs)
+		if tt.want == delta.Unchanged {
+			assert.False(t, ok, "%v+%v", tt.ours, tt.theirs)
+			continue
+		}
+		require.True(t, ok, "%v+%v", tt.ours, tt.theirs)
+		assert.Equal(t, tt.want, got.Status, "%v+%v", tt.ours, tt.theirs)
+		assert.Equal(t, theirs.Value, got.Value, "%v+%v", tt.ours, tt.theirs)
+		assert.Equal(t, tt.want != delta.Added, got.HasOld, "%v+%v", tt.ours, tt.theirs)
+	}
+}
@@ -0,0 +1,86 @@
+package delta
+
+import (
+	"context"
+	"time"
+)
+
+// ChangeMeta is the metadata of a change: when it was made, by whom and why.
+// It is only recorded for the fields registered in a tracker with WithActor.
+type ChangeMeta struct {
+	At     time.Time `json:"at"`
+	Actor  string    `json:"actor,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// IsZero returns true if there is no metadata.
+func (m ChangeMeta) IsZero() bool {
+	return m == ChangeMeta{}
+}
+
+// WithActor records, with the changes made by the methods of the registered fields (eg: Set, Remove),
+// when they were made and the actor and reason returned by fn (eg: the authenticated user),
+// to be reported in the Meta of Change and SliceChange.
+func WithActor(fn func() (actor, reason string)) TrackerOption {
+	return trackerOptionFunc(func(t *Tracker) {
+		t.actor = fn
+	})
+}
+
+type actorKey struct{}
+
+type actorValue struct {
+	actor  string
+	reason string
+}
+
+// ContextWithActor returns a copy of ctx carrying the actor of the changes, and why they are made.
+func ContextWithActor(ctx context.Context, actor, reason string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorValue{actor: actor, reason: reason})
+}
+
+// ActorFromContext returns, to be used with WithActor, the actor and reason carried by ctx,
+// set with ContextWithActor.
+func ActorFromContext(ctx context.Context) func() (actor, reason string) {
+	return func() (string, string) {
+		v, _ := ctx.Value(actorKey{}).(actorValue)
+		return v.actor, v.reason
+	}
+}
+
+// meta returns the metadata of a mutation made at, if there is an actor.
+func (m *mutations) meta(at time.Time) ChangeMeta {
+	if m.actor == nil {
+		return ChangeMeta{}
+	}
+	actor, reason := m.actor()
+	return ChangeMeta{At: at, Actor: actor, Reason: reason}
+}
+
+func (v *LazyScalar[T]) setActor(fn func() (string, string)) {
+	v.mut.actor = fn
+}
+
+func (s *LazySlice[T, I]) setActor(fn func() (string, string)) {
+	s.mut.actor = fn
+}
+
+func (d *DynamicFields) setActor(fn func() (string, string)) {
+	d.mut.actor = fn
+}
+
+func (m *LazyMap[K, V]) setActor(fn func() (string, string)) {
+	m.mut.actor = fn
+}
+
+func (s *LazySet[T]) setActor(fn func() (string, string)) {
+	s.mut.actor = fn
+}
+
+func (r *LazyRef[T, I]) setActor(fn func() (string, string)) {
+	r.mut.actor = fn
+}
+
+func (l *LazyList[T, I]) setActor(fn func() (string, string)) {
+	l.mut.actor = fn
+}
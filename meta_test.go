@@ -0,0 +1,45 @@
+package delta_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithActor(t *testing.T) {
+	clock := newFakeClock()
+	ctx := delta.ContextWithActor(context.Background(), "user-1", "typo")
+	name := delta.New("Paulo")
+	items := delta.NewSlice([]*testEntity{{id: "1", name: "entity1"}})
+	tracker := delta.NewTracker(delta.WithClock(clock), delta.WithActor(delta.ActorFromContext(ctx)))
+	tracker.Register("name", name)
+	tracker.Register("items", items)
+
+	name.Set("Quintans")
+	items.Set(&testEntity{id: "2", name: "entity2"})
+	items.Remove("1")
+
+	want := delta.ChangeMeta{At: clock.Now(), Actor: "user-1", Reason: "typo"}
+	assert.Equal(t, want, name.Change().Meta)
+	for c := range items.Changes().Items {
+		assert.Equal(t, want, c.Meta, c.ID)
+	}
+
+	b, err := json.Marshal(name.Change())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value":"Quintans","old":"Paulo","meta":{"at":"2025-01-01T00:00:00Z","actor":"user-1","reason":"typo"}}`, string(b))
+	var c delta.Change[string]
+	require.NoError(t, json.Unmarshal(b, &c))
+	assert.Equal(t, want, c.Meta)
+}
+
+func TestWithActor_None(t *testing.T) {
+	name := delta.New("Paulo")
+	delta.NewTracker().Register("name", name)
+	name.Set("Quintans")
+	assert.True(t, name.Change().Meta.IsZero())
+}
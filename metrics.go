@@ -0,0 +1,92 @@
+package delta
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Metrics receives the load events of the fields registered in a tracker, eg: to be exported to Prometheus.
+// The aggregate type is the one set with WithAggregateType, if any, and field is the registered name.
+type Metrics interface {
+	// Hit is called when a read is served from the cache.
+	Hit(aggregateType, field string)
+	// Miss is called when a read requires a load.
+	Miss(aggregateType, field string)
+	// Loaded is called after a successful load.
+	Loaded(aggregateType, field string, elapsed time.Duration)
+	// Failed is called after a failed load.
+	Failed(aggregateType, field string, elapsed time.Duration)
+}
+
+// WithMetrics reports the load events of the registered fields to m.
+func WithMetrics(m Metrics) TrackerOption {
+	return trackerOptionFunc(func(t *Tracker) {
+		t.metrics = m
+	})
+}
+
+// fieldCounters reports the load events of a registered field to the load statistics, the metrics and the logger.
+type fieldCounters struct {
+	stats         *statCounters // nil without aggregate type
+	metrics       Metrics
+	logger        *slog.Logger
+	aggregateType string
+	field         string
+}
+
+func (c *fieldCounters) hit() {
+	if c == nil {
+		return
+	}
+	if c.stats != nil {
+		c.stats.hits.Add(1)
+	}
+	if c.metrics != nil {
+		c.metrics.Hit(c.aggregateType, c.field)
+	}
+	if c.logger != nil {
+		c.logger.Debug("delta cache hit", "field", c.field)
+	}
+}
+
+func (c *fieldCounters) miss() {
+	if c == nil {
+		return
+	}
+	if c.stats != nil {
+		c.stats.misses.Add(1)
+	}
+	if c.metrics != nil {
+		c.metrics.Miss(c.aggregateType, c.field)
+	}
+}
+
+func (c *fieldCounters) loaded(bytes int, elapsed time.Duration) {
+	if c == nil {
+		return
+	}
+	if c.stats != nil {
+		c.stats.loaded(bytes, elapsed)
+	}
+	if c.metrics != nil {
+		c.metrics.Loaded(c.aggregateType, c.field, elapsed)
+	}
+	if c.logger != nil {
+		c.logger.Debug("delta load", "field", c.field, "elapsed", elapsed)
+	}
+}
+
+func (c *fieldCounters) failed(elapsed time.Duration) {
+	if c == nil {
+		return
+	}
+	if c.stats != nil {
+		c.stats.errors.Add(1)
+	}
+	if c.metrics != nil {
+		c.metrics.Failed(c.aggregateType, c.field, elapsed)
+	}
+	if c.logger != nil {
+		c.logger.Debug("delta load failed", "field", c.field, "elapsed", elapsed)
+	}
+}
@@ -0,0 +1,82 @@
+package delta_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingMetrics struct {
+	events []string
+}
+
+func (m *recordingMetrics) Hit(aggregateType, field string) {
+	m.events = append(m.events, "hit "+aggregateType+"."+field)
+}
+
+func (m *recordingMetrics) Miss(aggregateType, field string) {
+	m.events = append(m.events, "miss "+aggregateType+"."+field)
+}
+
+func (m *recordingMetrics) Loaded(aggregateType, field string, _ time.Duration) {
+	m.events = append(m.events, "loaded "+aggregateType+"."+field)
+}
+
+func (m *recordingMetrics) Failed(aggregateType, field string, _ time.Duration) {
+	m.events = append(m.events, "failed "+aggregateType+"."+field)
+}
+
+func TestMetrics(t *testing.T) {
+	delta.ResetStats()
+	errLoad := errors.New("unavailable")
+	fail := true
+
+	photo := delta.NewLazy(func() ([]byte, error) {
+		if fail {
+			return nil, errLoad
+		}
+		return []byte("photo"), nil
+	})
+	metrics := &recordingMetrics{}
+	tracker := delta.NewTracker(delta.WithAggregateType("metrics-person"), delta.WithMetrics(metrics))
+	tracker.Register("photo", photo)
+
+	_, err := photo.Get()
+	require.ErrorIs(t, err, errLoad)
+	fail = false
+	for range 2 {
+		_, err = photo.Get()
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{
+		"miss metrics-person.photo",
+		"failed metrics-person.photo",
+		"miss metrics-person.photo",
+		"loaded metrics-person.photo",
+		"hit metrics-person.photo",
+	}, metrics.events)
+
+	stats := delta.StatsFor("metrics-person")
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(1), stats[0].Errors)
+	assert.Equal(t, int64(1), stats[0].Loads)
+}
+
+func TestMetrics_WithoutAggregateType(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{{id: "1", name: "entity1"}}))
+	metrics := &recordingMetrics{}
+	tracker := delta.NewTracker(delta.WithMetrics(metrics))
+	tracker.Register("items", items)
+
+	_, err := items.Get("1")
+	require.NoError(t, err)
+	_, err = items.Get("1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"miss .items", "loaded .items", "hit .items"}, metrics.events)
+}
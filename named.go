@@ -0,0 +1,51 @@
+package delta
+
+import "errors"
+
+// ErrLoad is matched by the failures to load a field. See LoadError.
+var ErrLoad = errors.New("load failed")
+
+// Named names a lazy field (eg: "person.photo"), so that the failures of its loaders are wrapped in a *LoadError
+// carrying the name, telling which field of a deep aggregate failed to load.
+func Named(name string) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optNamed
+		o.name = name
+	})
+}
+
+// loader returns fn decorated as set in the options: retried (see WithRetry) and with its failures named (see Named).
+func loader[A, R any](opts options, fn func(A) (R, error)) func(A) (R, error) {
+	return named(opts.name, retried(opts.retry, fn))
+}
+
+// loader0 is loader for the loaders without argument.
+func loader0[R any](opts options, fn func() (R, error)) func() (R, error) {
+	return named0(opts.name, retried0(opts.retry, fn))
+}
+
+func named[A, R any](name string, fn func(A) (R, error)) func(A) (R, error) {
+	if name == "" || fn == nil {
+		return fn
+	}
+	return func(a A) (R, error) {
+		r, err := fn(a)
+		// a missing entity is not a failure
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			err = &LoadError{Field: name, Err: err}
+		}
+		return r, err
+	}
+}
+
+func named0[R any](name string, fn func() (R, error)) func() (R, error) {
+	if name == "" || fn == nil {
+		return fn
+	}
+	n := named(name, func(struct{}) (R, error) {
+		return fn()
+	})
+	return func() (R, error) {
+		return n(struct{}{})
+	}
+}
@@ -0,0 +1,38 @@
+package delta_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamed(t *testing.T) {
+	boom := errors.New("boom")
+	photo := delta.NewLazy(func() ([]byte, error) { return nil, boom }, delta.Named("person.photo"))
+
+	_, err := photo.Get()
+	require.ErrorIs(t, err, delta.ErrLoad)
+	require.ErrorIs(t, err, boom)
+	assert.EqualError(t, err, `load field "person.photo": boom`)
+
+	tracker := delta.NewTracker(delta.WithAggregateType("person"), delta.WithAggregateID(1))
+	tracker.Register("photo", photo)
+	err = tracker.Load()
+	var le *delta.LoadError
+	require.ErrorAs(t, err, &le)
+	assert.Equal(t, "person.photo", le.Field)
+	assert.EqualError(t, err, `load person 1 field "person.photo": boom`)
+}
+
+func TestNamed_NotFound(t *testing.T) {
+	address := delta.NewLazyRef(func() (*testEntity, error) {
+		return nil, delta.ErrNotFound
+	}, delta.Named("person.address"))
+
+	_, err := address.Get()
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	assert.NotErrorIs(t, err, delta.ErrLoad)
+}
@@ -0,0 +1,31 @@
+package delta
+
+import "reflect"
+
+// none is implemented by values that can be absent, like Optional.
+type none interface {
+	IsNone() bool
+}
+
+// isNull returns true if value is nil (eg: a nil pointer, slice or map) or an absent Optional,
+// that is, a value to be persisted as NULL.
+func isNull(value any) bool {
+	if value == nil {
+		return true
+	}
+	if n, ok := value.(none); ok {
+		return n.IsNone()
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// SetNull sets the value to its zero value (eg: a nil pointer), so that its change is reported as Null.
+func (v *LazyScalar[T]) SetNull() {
+	var zero T
+	v.Set(zero)
+}
@@ -0,0 +1,47 @@
+package delta_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeNull(t *testing.T) {
+	name := "Paulo"
+	nickname := delta.New(&name)
+	assert.Nil(t, nickname.Change())
+
+	nickname.SetNull()
+	c := nickname.Change()
+	require.NotNil(t, c)
+	assert.True(t, c.Null)
+	assert.Nil(t, c.Value)
+
+	other := "Quintans"
+	nickname.Set(&other)
+	assert.False(t, nickname.Change().Null)
+
+	age := delta.New(40)
+	age.SetNull()
+	assert.False(t, age.Change().Null, "zero values are not null")
+
+	photo := delta.NewOptional(delta.Some("photo.png"))
+	photo.Clear()
+	assert.True(t, photo.Change().Null)
+}
+
+func TestChangeNullJSON(t *testing.T) {
+	name := "Paulo"
+	nickname := delta.New(&name)
+	nickname.SetNull()
+
+	data, err := json.Marshal(nickname.Change())
+	require.NoError(t, err)
+
+	var c delta.Change[*string]
+	require.NoError(t, json.Unmarshal(data, &c))
+	assert.True(t, c.Null)
+}
@@ -0,0 +1,80 @@
+package delta
+
+// listeners are the subscribed callbacks of a field, called in the order they were subscribed.
+type listeners[C any] struct {
+	next int
+	fns  []listener[C]
+}
+
+type listener[C any] struct {
+	id int
+	fn func(C)
+}
+
+// add subscribes fn, returning the function that unsubscribes it.
+func (l *listeners[C]) add(fn func(C)) func() {
+	l.next++
+	id := l.next
+	l.fns = append(l.fns, listener[C]{id: id, fn: fn})
+	return func() {
+		for i, x := range l.fns {
+			if x.id == id {
+				l.fns = append(l.fns[:i:i], l.fns[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (l *listeners[C]) notify(c C) {
+	for _, x := range l.fns {
+		x.fn(c)
+	}
+}
+
+func (l *listeners[C]) active() bool {
+	return len(l.fns) > 0
+}
+
+// OnChange subscribes fn to the changes of the value made by Set, called synchronously after each one
+// with the new value and the fetched one (eg: to update a view or to invalidate a cache).
+// It returns the function that unsubscribes fn.
+func (v *LazyScalar[T]) OnChange(fn func(Change[T])) (unsubscribe func()) {
+	return v.onChange.add(fn)
+}
+
+// OnSliceChange subscribes fn to the changes of the items made by Set, Remove, SetAll and Clear,
+// called synchronously after each one with the resulting status of the item (eg: to update a view or to invalidate a cache).
+// Clear and SetAll first notify the removal of the cached items.
+// It returns the function that unsubscribes fn.
+func (s *LazySlice[T, I]) OnSliceChange(fn func(SliceChange[I, T])) (unsubscribe func()) {
+	return s.onChange.add(fn)
+}
+
+// notifyItem notifies the change of an item.
+func (s *LazySlice[T, I]) notifyItem(id I, item Item[T, I]) {
+	if !s.onChange.active() {
+		return
+	}
+	s.onChange.notify(SliceChange[I, T]{
+		ID:     id,
+		Value:  item.value,
+		Status: item.status,
+		Old:    item.old,
+		HasOld: item.hasOld,
+		Meta:   item.meta,
+	})
+}
+
+// notifyReset notifies the removal of the cached items, before a reset.
+func (s *LazySlice[T, I]) notifyReset() {
+	if !s.onChange.active() {
+		return
+	}
+	for id, item := range s.fetched.Entries() {
+		if item.status == Removed || item.status == Absent {
+			continue
+		}
+		s.notifyItem(id, item.remove())
+	}
+}
@@ -0,0 +1,47 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyScalar_OnChange(t *testing.T) {
+	name := delta.New("Paulo")
+	var changes []delta.Change[string]
+	unsubscribe := name.OnChange(func(c delta.Change[string]) {
+		changes = append(changes, c)
+	})
+
+	name.Set("Quintans")
+	name.Set("Paulo") // back to the fetched value
+	unsubscribe()
+	name.Set("Other")
+
+	assert.Equal(t, []delta.Change[string]{
+		{Value: "Quintans", Old: "Paulo", HasOld: true},
+		{Value: "Paulo", Old: "Paulo", HasOld: true},
+	}, changes)
+}
+
+func TestLazySlice_OnSliceChange(t *testing.T) {
+	items := delta.NewSlice([]*testEntity{{id: "1", name: "entity1"}})
+	var statuses []string
+	items.OnSliceChange(func(c delta.SliceChange[string, *testEntity]) {
+		statuses = append(statuses, c.ID+":"+c.Status.String())
+	})
+
+	items.Set(&testEntity{id: "2"})
+	items.Set(&testEntity{id: "1", name: "changed"})
+	items.Remove("2")
+	items.SetAll([]*testEntity{{id: "3"}})
+
+	assert.Equal(t, []string{
+		"2:added",
+		"1:modified",
+		"2:removed",
+		"1:removed",
+		"3:added",
+	}, statuses)
+}
@@ -0,0 +1,113 @@
+package delta
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Optional is a value that can be absent (eg: a photo that was cleared), encoded as null in JSON.
+// The zero value is absent.
+type Optional[T any] struct {
+	value T
+	ok    bool
+}
+
+// Some returns an optional with value.
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{value: value, ok: true}
+}
+
+// None returns an absent optional.
+func None[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// Get returns the value and true, or the zero value and false if it is absent.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// OrElse returns the value, or def if it is absent.
+func (o Optional[T]) OrElse(def T) T {
+	if !o.ok {
+		return def
+	}
+	return o.value
+}
+
+// IsNone returns true if the value is absent.
+func (o Optional[T]) IsNone() bool {
+	return !o.ok
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.ok {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = None[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+type optionalGob[T any] struct {
+	Value T
+	OK    bool
+}
+
+// MarshalBinary encodes the optional with gob.
+func (o Optional[T]) MarshalBinary() ([]byte, error) {
+	return gobEncode(optionalGob[T]{Value: o.value, OK: o.ok})
+}
+
+func (o *Optional[T]) UnmarshalBinary(data []byte) error {
+	var v optionalGob[T]
+	if err := gobDecode(data, &v); err != nil {
+		return err
+	}
+	*o = Optional[T]{value: v.Value, ok: v.OK}
+	return nil
+}
+
+// LazyOptional is a lazily loaded value that can be cleared, distinguishing, in its Change,
+// a value that was not touched (nil change), cleared (None) or set (Some), eg: for PATCH semantics.
+type LazyOptional[T any] struct {
+	LazyScalar[Optional[T]]
+}
+
+// NewLazyOptional creates an optional whose value is loaded by fn.
+func NewLazyOptional[T any](fn func() (Optional[T], error), options ...Option) *LazyOptional[T] {
+	o := &LazyOptional[T]{}
+	o.init(fn, newOptions(options))
+	return o
+}
+
+// NewOptional creates a loaded optional with value.
+func NewOptional[T any](value Optional[T], options ...Option) *LazyOptional[T] {
+	o := &LazyOptional[T]{}
+	o.init(nil, newOptions(options))
+	o.isSet = true
+	o.value = value
+	o.original, o.hasOrig = value, true
+	return o
+}
+
+// SetValue sets the value.
+func (o *LazyOptional[T]) SetValue(value T) {
+	o.Set(Some(value))
+}
+
+// Clear clears the value, so that it is absent.
+func (o *LazyOptional[T]) Clear() {
+	o.Set(None[T]())
+}
@@ -0,0 +1,53 @@
+package delta_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyOptional(t *testing.T) {
+	photo := delta.NewLazyOptional(func() (delta.Optional[string], error) {
+		return delta.Some("photo.png"), nil
+	})
+	v, err := photo.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "photo.png", v.OrElse(""))
+	assert.Nil(t, photo.Change()) // not touched
+
+	photo.Clear()
+	c := photo.Change()
+	require.NotNil(t, c)
+	assert.True(t, c.Value.IsNone())
+
+	photo.SetValue("photo.png") // back to the fetched value
+	assert.Nil(t, photo.Change())
+
+	photo.SetValue("other.png")
+	value, ok := photo.Change().Value.Get()
+	assert.True(t, ok)
+	assert.Equal(t, "other.png", value)
+}
+
+func TestOptional_JSON(t *testing.T) {
+	photo := delta.NewOptional(delta.Some("photo.png"))
+	photo.Clear()
+	b, err := json.Marshal(photo.Change())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value":null,"old":"photo.png"}`, string(b))
+
+	var c delta.Change[delta.Optional[string]]
+	require.NoError(t, json.Unmarshal(b, &c))
+	assert.True(t, c.Value.IsNone())
+	assert.Equal(t, delta.Some("photo.png"), c.Old)
+
+	b, err = photo.Change().MarshalBinary()
+	require.NoError(t, err)
+	c = delta.Change[delta.Optional[string]]{}
+	require.NoError(t, c.UnmarshalBinary(b))
+	assert.True(t, c.Value.IsNone())
+	assert.Equal(t, delta.Some("photo.png"), c.Old)
+}
@@ -0,0 +1,185 @@
+package delta
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock provides the current time, so that time can be controlled in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the default clock, using time.Now().
+var SystemClock Clock = systemClock{}
+
+type options struct {
+	clock     Clock
+	history   bool
+	schema    Schema
+	stride    int
+	ttl       time.Duration
+	equal     any // func(a, b T) bool
+	compare   any // func(a, b T) int
+	delta     any // func(T) any
+	pager     any // func(Page[I]) ([]T, error)
+	querier   any // func(Query[I]) ([]T, error)
+	exister   any // func(I) (bool, error)
+	many      any // func([]I) ([]T, error)
+	stream    any // func() iter.Seq2[T, error]
+	retry     retryPolicy
+	errorTTL  time.Duration
+	noAbsent  bool
+	fieldDiff bool
+	capacity  int
+	marshal   MarshalMode
+	order     ChangeOrder
+	indexes   []indexDef
+	name      string
+	applied   optionSet // the options used, to reject the ones not supported by the type
+}
+
+// optionSet identifies the options of the tracked types, so that each type can reject the options it ignores.
+type optionSet uint32
+
+const (
+	optCapacity optionSet = 1 << iota
+	optChangeOrder
+	optChildDelta
+	optClock
+	optEqual
+	optErrorCache
+	optExistsLoader
+	optFieldDiff
+	optHistory
+	optIndex
+	optManyLoader
+	optMarshalMode
+	optNamed
+	optNoAbsent
+	optOrder
+	optPageLoader
+	optQueryLoader
+	optRetry
+	optSchema
+	optStreamLoader
+	optStride
+	optTTL
+)
+
+var optionNames = []string{
+	"WithCapacity",
+	"WithChangeOrder",
+	"WithChildDelta",
+	"WithClock",
+	"WithEqual",
+	"WithErrorCache",
+	"WithExistsLoader",
+	"WithFieldDiff",
+	"WithHistory",
+	"WithIndex",
+	"WithManyLoader",
+	"WithMarshalMode",
+	"Named",
+	"WithoutAbsentCache",
+	"WithOrder",
+	"WithPageLoader",
+	"WithQueryLoader",
+	"WithRetry",
+	"WithSchema",
+	"WithStreamLoader",
+	"WithStride",
+	"WithTTL",
+}
+
+// The options supported by each tracked type.
+const (
+	loaderOptions     = optClock | optNamed | optRetry | optTTL
+	scalarOptions     = loaderOptions | optEqual | optErrorCache | optFieldDiff | optHistory | optMarshalMode
+	collectionOptions = loaderOptions | optCapacity | optStride
+	sliceOptions      = collectionOptions | optChangeOrder | optChildDelta | optEqual | optErrorCache | optExistsLoader |
+		optHistory | optIndex | optManyLoader | optMarshalMode | optNoAbsent | optOrder | optPageLoader |
+		optQueryLoader | optStreamLoader
+	mapOptions     = collectionOptions
+	setOptions     = collectionOptions
+	dynamicOptions = collectionOptions | optSchema
+	refOptions     = loaderOptions
+	listOptions    = loaderOptions | optEqual
+)
+
+// only panics if an option not in supported was used with field, instead of silently ignoring it.
+func (o options) only(supported optionSet, field any) {
+	for i, name := range optionNames {
+		if o.applied&^supported&(1<<i) != 0 {
+			panic(fmt.Sprintf("delta: %s is not supported by %T", name, field))
+		}
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		clock: SystemClock,
+	}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	return o
+}
+
+// WithCapacity sets the number of items a collection is expected to cache.
+// Supported by LazySlice, LazyMap, LazySet and DynamicFields.
+func WithCapacity(n int) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optCapacity
+		o.capacity = n
+	})
+}
+
+// Option configures the tracked types of this package.
+// Each option documents the types that support it. Using an option with a type that does not support it panics.
+type Option interface {
+	apply(*options)
+}
+
+type optionFunc func(*options)
+
+func (f optionFunc) apply(o *options) {
+	f(o)
+}
+
+// TrackerOption configures a Tracker.
+type TrackerOption interface {
+	applyTracker(*Tracker)
+}
+
+type trackerOptionFunc func(*Tracker)
+
+func (f trackerOptionFunc) applyTracker(t *Tracker) {
+	f(t)
+}
+
+// ClockOption is an option that can be used both with tracked types and trackers.
+type ClockOption struct {
+	clock Clock
+}
+
+func (o ClockOption) apply(opts *options) {
+	opts.applied |= optClock
+	opts.clock = o.clock
+}
+
+func (o ClockOption) applyTracker(t *Tracker) {
+	t.clock = o.clock
+}
+
+// WithClock sets the clock used by the timestamp-bearing features (eg: fetched-at, load latencies).
+// When used with a tracker, the clock is also set on the registered fields.
+func WithClock(clock Clock) ClockOption {
+	return ClockOption{clock: clock}
+}
@@ -0,0 +1,168 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock that only moves when told to.
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestWithClock(t *testing.T) {
+	clock := newFakeClock()
+
+	scalar := delta.NewLazy(func() (string, error) {
+		clock.Advance(20 * time.Millisecond)
+		return "loaded", nil
+	}, delta.WithClock(clock))
+	assert.True(t, scalar.FetchedAt().IsZero())
+
+	_, err := scalar.Get()
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now(), scalar.FetchedAt())
+
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{{id: "1", name: "entity1"}}), delta.WithClock(clock))
+	_, err = lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now(), lazySlice.FetchedAt())
+}
+
+func TestWithClock_Tracker(t *testing.T) {
+	delta.ResetStats()
+	clock := newFakeClock()
+
+	scalar := delta.NewLazy(func() (string, error) {
+		clock.Advance(20 * time.Millisecond)
+		return "loaded", nil
+	})
+	tracker := delta.NewTracker(delta.WithClock(clock), delta.WithAggregateType("clock-person"))
+	tracker.Register("name", scalar)
+
+	_, err := scalar.Get()
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now(), scalar.FetchedAt())
+
+	stats := delta.StatsFor("clock-person")
+	require.Len(t, stats, 1)
+	assert.Equal(t, 20*time.Millisecond, stats[0].LoadLatency.Sum)
+	// 20ms falls in the 25ms bucket
+	assert.Equal(t, int64(1), stats[0].LoadLatency.Counts[3])
+}
+
+func TestEagerConstructors_Options(t *testing.T) {
+	scalar := delta.New(1.0, delta.WithHistory(), delta.WithEqual(func(a, b float64) bool {
+		return int(a) == int(b)
+	}))
+	scalar.Set(1.5)
+	assert.Nil(t, scalar.Change())
+	scalar.Set(2.0)
+	assert.Len(t, scalar.History(), 1)
+
+	slice := delta.NewSlice([]*testEntity{{id: "1", name: "entity1"}}, delta.WithCapacity(10))
+	slice.Set(&testEntity{id: "2", name: "entity2"})
+	assert.Equal(t, []string{"1", "2"}, ids(slices.Collect(slice.GetAll())))
+}
+
+func TestOptions_Unsupported(t *testing.T) {
+	loadSettings := func(key string) (map[string]string, error) {
+		return nil, nil
+	}
+	assert.PanicsWithValue(t, "delta: WithHistory is not supported by *delta.LazyMap[string,string]", func() {
+		delta.NewLazyMap(loadSettings, delta.WithHistory())
+	})
+
+	tests := []struct {
+		name        string
+		create      func(opts ...delta.Option)
+		supported   []delta.Option
+		unsupported []delta.Option
+	}{
+		{
+			name: "LazyScalar",
+			create: func(opts ...delta.Option) {
+				delta.NewLazy(func() (string, error) { return "", nil }, opts...)
+			},
+			supported:   []delta.Option{delta.WithHistory(), delta.WithErrorCache(time.Minute), delta.WithTTL(time.Minute)},
+			unsupported: []delta.Option{delta.WithCapacity(10), delta.WithStride(10), delta.WithSchema(delta.Schema{})},
+		},
+		{
+			name: "LazySlice",
+			create: func(opts ...delta.Option) {
+				delta.NewLazySlice(fetcher([]*testEntity{}), opts...)
+			},
+			supported:   []delta.Option{delta.WithHistory(), delta.WithCapacity(10), delta.WithStride(10), delta.WithoutAbsentCache()},
+			unsupported: []delta.Option{delta.WithFieldDiff(), delta.WithSchema(delta.Schema{})},
+		},
+		{
+			name: "LazyMap",
+			create: func(opts ...delta.Option) {
+				delta.NewLazyMap(loadSettings, opts...)
+			},
+			supported:   []delta.Option{delta.WithCapacity(10), delta.WithStride(10), delta.WithTTL(time.Minute)},
+			unsupported: []delta.Option{delta.WithHistory(), delta.WithErrorCache(time.Minute), delta.WithMarshalMode(delta.LoadOnMarshal)},
+		},
+		{
+			name: "LazySet",
+			create: func(opts ...delta.Option) {
+				delta.NewLazySet(func(member string) ([]string, error) { return nil, nil }, opts...)
+			},
+			supported:   []delta.Option{delta.WithCapacity(10), delta.WithStride(10), delta.WithRetry(2, nil)},
+			unsupported: []delta.Option{delta.WithHistory(), delta.WithoutAbsentCache()},
+		},
+		{
+			name: "DynamicFields",
+			create: func(opts ...delta.Option) {
+				delta.NewDynamicFields(func(key string) (map[string]any, error) { return nil, nil }, opts...)
+			},
+			supported:   []delta.Option{delta.WithSchema(delta.Schema{}), delta.WithCapacity(10), delta.Named("attributes")},
+			unsupported: []delta.Option{delta.WithHistory(), delta.WithChangeOrder(delta.ByID)},
+		},
+		{
+			name: "LazyRef",
+			create: func(opts ...delta.Option) {
+				delta.NewLazyRef(func() (*testEntity, error) { return nil, nil }, opts...)
+			},
+			supported:   []delta.Option{delta.WithClock(newFakeClock()), delta.WithTTL(time.Minute)},
+			unsupported: []delta.Option{delta.WithCapacity(10), delta.WithHistory()},
+		},
+		{
+			name: "LazyList",
+			create: func(opts ...delta.Option) {
+				delta.NewLazyList(func() ([]*testEntity, error) { return nil, nil }, opts...)
+			},
+			supported:   []delta.Option{delta.WithEqual(func(a, b *testEntity) bool { return a.id == b.id })},
+			unsupported: []delta.Option{delta.WithCapacity(10), delta.WithStride(10)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotPanics(t, func() {
+				tt.create(tt.supported...)
+			})
+			for _, opt := range tt.unsupported {
+				assert.Panics(t, func() {
+					tt.create(opt)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package delta
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+)
+
+// WithOrder sets the order in which GetAll yields the items of a LazySlice of T,
+// regardless of the order in which they were fetched or added. Items that compare equal keep that order.
+// A nil compare keeps the insertion order.
+// It panics when used with a collection of a different type.
+func WithOrder[T any](compare func(a, b T) int) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optOrder
+		if compare != nil {
+			o.compare = compare
+		}
+	})
+}
+
+func compareFor[T any](opts options) func(a, b T) int {
+	if opts.compare == nil {
+		return nil
+	}
+	compare, ok := opts.compare.(func(a, b T) int)
+	if !ok {
+		panic(fmt.Sprintf("delta: WithOrder comparator %T used with a collection of %s", opts.compare, reflect.TypeFor[T]()))
+	}
+	return compare
+}
+
+// ordered returns the items in the order set with WithOrder. They are sorted when iterated,
+// and returned as they are, without being copied, when there is no order.
+func (s *LazySlice[T, I]) ordered(items iter.Seq[T]) iter.Seq[T] {
+	if s.compare == nil {
+		return items
+	}
+	return func(yield func(T) bool) {
+		for _, v := range slices.SortedStableFunc(items, s.compare) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
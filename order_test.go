@@ -0,0 +1,66 @@
+package delta_test
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func byName(a, b *testEntity) int {
+	return strings.Compare(a.name, b.name)
+}
+
+func TestWithOrder(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "c"},
+		{id: "2", name: "a"},
+	}), delta.WithOrder(byName))
+	items.Set(&testEntity{id: "3", name: "b"})
+
+	all, err := items.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "3", "1"}, ids(slices.Collect(all)))
+
+	eager := delta.NewSlice([]*testEntity{{id: "1", name: "b"}, {id: "2", name: "a"}}, delta.WithOrder(byName))
+	assert.Equal(t, []string{"2", "1"}, ids(slices.Collect(eager.GetAll())))
+}
+
+func TestWithOrder_WrongType(t *testing.T) {
+	assert.Panics(t, func() {
+		delta.NewSlice([]*testEntity{}, delta.WithOrder(strings.Compare))
+	})
+}
+
+func TestWithOrder_Nil(t *testing.T) {
+	items := []*testEntity{{id: "1", name: "b"}, {id: "2", name: "a"}}
+	for _, s := range []*delta.Slice[*testEntity, string]{
+		delta.NewSlice(items),
+		delta.NewSlice(items, delta.WithOrder[*testEntity](nil)),
+		// a nil comparator of another type is not used either
+		delta.NewSlice(items, delta.WithOrder[string](nil)),
+	} {
+		assert.Equal(t, []string{"1", "2"}, ids(slices.Collect(s.GetAll())))
+	}
+}
+
+func TestWithOrder_NotSet_DoesNotCopy(t *testing.T) {
+	items := make([]*testEntity, 100)
+	for i := range items {
+		items[i] = &testEntity{id: strconv.Itoa(i)}
+	}
+	iterate := func(s *delta.Slice[*testEntity, string]) func() {
+		return func() {
+			for range s.GetAll() {
+			}
+		}
+	}
+
+	unordered := testing.AllocsPerRun(10, iterate(delta.NewSlice(items)))
+	ordered := testing.AllocsPerRun(10, iterate(delta.NewSlice(items, delta.WithOrder(byName))))
+	assert.Less(t, unordered, ordered)
+}
@@ -0,0 +1,68 @@
+package delta
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// OutboxRecord is a row of an outbox table, written in the same transaction as the changes of the aggregate,
+// to be published later.
+type OutboxRecord struct {
+	AggregateType string
+	AggregateID   any
+	Sequence      uint64
+	Kind          string // name of the changed field or type of the event
+	Payload       []byte
+}
+
+// Outbox returns the outbox records of the pending changes, one per changed field, in registration order,
+// with the aggregate type and ID of the tracker (see WithAggregateType and WithAggregateID).
+// The records are numbered after seq (eg: the last sequence of the aggregate)
+// and their payload is the changes of the field (see Changes), encoded with encode.
+func (t *Tracker) Outbox(seq uint64, encode func(v any) ([]byte, error)) ([]OutboxRecord, error) {
+	var records []OutboxRecord
+	for name, field := range t.fields.Entries() {
+		if !field.IsDirty() {
+			continue
+		}
+		payload, err := encode(field.changes())
+		if err != nil {
+			return nil, fmt.Errorf("encoding the changes of %q: %w", name, err)
+		}
+		seq++
+		records = append(records, OutboxRecord{
+			AggregateType: t.aggType,
+			AggregateID:   t.aggID,
+			Sequence:      seq,
+			Kind:          name,
+			Payload:       payload,
+		})
+	}
+	return records, nil
+}
+
+// OutboxEvents returns the outbox records of the events (eg: drained from Events), numbered after seq.
+// The kind of a record is the name of the type of its event, or of the pointed type.
+func OutboxEvents(aggType string, aggID any, seq uint64, events []any, encode func(v any) ([]byte, error)) ([]OutboxRecord, error) {
+	records := make([]OutboxRecord, 0, len(events))
+	for _, e := range events {
+		rt := reflect.TypeOf(e)
+		if rt.Kind() == reflect.Pointer {
+			rt = rt.Elem()
+		}
+		kind := rt.Name()
+		payload, err := encode(e)
+		if err != nil {
+			return nil, fmt.Errorf("encoding the event %s: %w", kind, err)
+		}
+		seq++
+		records = append(records, OutboxRecord{
+			AggregateType: aggType,
+			AggregateID:   aggID,
+			Sequence:      seq,
+			Kind:          kind,
+			Payload:       payload,
+		})
+	}
+	return records, nil
+}
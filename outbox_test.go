@@ -0,0 +1,41 @@
+package delta_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_Outbox(t *testing.T) {
+	name := delta.New("John")
+	age := delta.New(30)
+	cars := delta.NewSlice([]*jsonEntity{{Key: "1", Name: "car1"}})
+	tracker := delta.NewTracker(delta.WithAggregateType("person"), delta.WithAggregateID("p1"))
+	tracker.Register("name", name)
+	tracker.Register("age", age)
+	tracker.Register("cars", cars)
+	name.Set("Jane")
+	cars.Remove("1")
+
+	records, err := tracker.Outbox(10, json.Marshal)
+	require.NoError(t, err)
+	assert.Equal(t, []delta.OutboxRecord{
+		{AggregateType: "person", AggregateID: "p1", Sequence: 11, Kind: "name", Payload: []byte(`{"value":"Jane","old":"John"}`)},
+		{AggregateType: "person", AggregateID: "p1", Sequence: 12, Kind: "cars", Payload: []byte(`{"reset":false,"items":[{"id":"1","status":"removed","old":{"key":"1","name":"car1"}}]}`)},
+	}, records)
+}
+
+type carSold struct {
+	ID string `json:"id"`
+}
+
+func TestOutboxEvents(t *testing.T) {
+	records, err := delta.OutboxEvents("person", "p1", 0, []any{carSold{ID: "1"}}, json.Marshal)
+	require.NoError(t, err)
+	assert.Equal(t, []delta.OutboxRecord{
+		{AggregateType: "person", AggregateID: "p1", Sequence: 1, Kind: "carSold", Payload: []byte(`{"id":"1"}`)},
+	}, records)
+}
@@ -0,0 +1,88 @@
+package delta
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Page is the page of a collection requested to a page loader.
+type Page[I comparable] struct {
+	Limit    int // maximum number of items, or 0 for no limit
+	Offset   int
+	After    I    // ID of the last item of the previous page, for cursor pagination
+	HasAfter bool // false if After is not set
+}
+
+// QueryOption restricts the items returned by LazySlice.GetAll and LazySlice.GetWhere.
+type QueryOption func(*query)
+
+type query struct {
+	limit  int
+	offset int
+	after  any
+	sort   []Sort
+}
+
+// Limit limits the number of returned items.
+func Limit(n int) QueryOption {
+	return func(q *query) {
+		q.limit = n
+	}
+}
+
+// Offset skips the first n items.
+func Offset(n int) QueryOption {
+	return func(q *query) {
+		q.offset = n
+	}
+}
+
+// After returns the items after the one with the given ID.
+func After(id any) QueryOption {
+	return func(q *query) {
+		q.after = id
+	}
+}
+
+// WithPageLoader sets the loader used by LazySlice.GetAll when called with query options,
+// so that large collections can be loaded one page at a time.
+// The loaded pages are added to the fetched items.
+// It panics when used with a collection of a different type.
+func WithPageLoader[T Identifiable[I], I comparable](fn func(Page[I]) ([]T, error)) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optPageLoader
+		o.pager = fn
+	})
+}
+
+func pagerFor[T Identifiable[I], I comparable](opts options) func(Page[I]) ([]T, error) {
+	if opts.pager == nil {
+		return nil
+	}
+	pager, ok := opts.pager.(func(Page[I]) ([]T, error))
+	if !ok {
+		panic(fmt.Sprintf("delta: WithPageLoader loader %T used with a collection of %s", opts.pager, reflect.TypeFor[T]()))
+	}
+	return pager
+}
+
+func queryOf[I comparable](name string, args []any, opts []QueryOption) (Query[I], error) {
+	var q query
+	for _, opt := range opts {
+		opt(&q)
+	}
+	result := Query[I]{
+		Page:   Page[I]{Limit: q.limit, Offset: q.offset},
+		Filter: name,
+		Args:   args,
+		Sort:   q.sort,
+	}
+	if q.after != nil {
+		after, err := convert[I](q.after)
+		if err != nil {
+			return Query[I]{}, err
+		}
+		result.After, result.HasAfter = after, true
+	}
+	return result, nil
+}
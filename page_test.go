@@ -0,0 +1,66 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pager(ents []*testEntity) func(delta.Page[string]) ([]*testEntity, error) {
+	return func(p delta.Page[string]) ([]*testEntity, error) {
+		result := ents
+		if p.HasAfter {
+			i := slices.IndexFunc(ents, func(e *testEntity) bool { return e.id == p.After })
+			result = ents[i+1:]
+		}
+		result = result[min(p.Offset, len(result)):]
+		if p.Limit > 0 {
+			result = result[:min(p.Limit, len(result))]
+		}
+		return result, nil
+	}
+}
+
+func TestLazySlice_GetAll_Paged(t *testing.T) {
+	ents := []*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+		{id: "3", name: "entity3"},
+		{id: "4", name: "entity4"},
+	}
+	lazySlice := delta.NewLazySlice(fetcher(ents), delta.WithPageLoader(pager(ents)))
+	lazySlice.Set(&testEntity{id: "2", name: "entity2_new"})
+
+	page, err := lazySlice.GetAll(delta.Limit(2))
+	require.NoError(t, err)
+	assert.Equal(t, []*testEntity{ents[0], {id: "2", name: "entity2_new"}}, slices.Collect(page))
+	assert.False(t, lazySlice.IsLoaded())
+
+	page, err = lazySlice.GetAll(delta.After("2"), delta.Limit(2))
+	require.NoError(t, err)
+	assert.Equal(t, []*testEntity{ents[2], ents[3]}, slices.Collect(page))
+
+	// loaded pages are cached
+	e, err := lazySlice.Get("3")
+	require.NoError(t, err)
+	assert.Equal(t, ents[2], e)
+	assert.Equal(t, []delta.SliceChange[string, *testEntity]{
+		{ID: "2", Value: &testEntity{id: "2", name: "entity2_new"}, Status: delta.Modified, Old: ents[1], HasOld: true},
+	}, slices.Collect(lazySlice.Changes().Items))
+
+	// once all are loaded, pages are served from the cache
+	_, err = lazySlice.GetAll()
+	require.NoError(t, err)
+	page, err = lazySlice.GetAll(delta.After("1"), delta.Offset(1), delta.Limit(2))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4"}, ids(slices.Collect(page)))
+}
+
+func TestLazySlice_GetAll_PagedWithoutPageLoader(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher(nil))
+	_, err := lazySlice.GetAll(delta.Limit(2))
+	require.ErrorIs(t, err, delta.ErrUnsupportedOperation)
+}
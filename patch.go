@@ -0,0 +1,229 @@
+package delta
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+var (
+	ErrUnknownField         = errors.New("unknown field")
+	ErrUnsupportedOperation = errors.New("unsupported operation")
+	ErrInvalidValue         = errors.New("invalid value")
+)
+
+type Operation string
+
+const (
+	OpSet    Operation = "set"
+	OpRemove Operation = "remove"
+	OpSetAll Operation = "set_all"
+	OpClear  Operation = "clear"
+)
+
+// PatchOp is an operation over a registered field.
+// Values that are not of the field type (eg: decoded from JSON) are converted.
+type PatchOp struct {
+	Op    Operation `json:"op"`
+	Path  string    `json:"path"`            // name of the registered field
+	ID    any       `json:"id,omitempty"`    // item ID, for collections, or the member, for sets
+	Value any       `json:"value,omitempty"` // new value, or values for set_all
+}
+
+// Patch is an ordered list of operations to apply to the fields of a tracker.
+type Patch []PatchOp
+
+// ApplyPatch applies the patch operations, in order, to the fields registered in the tracker,
+// stopping at the first failure.
+func ApplyPatch(tracker *Tracker, patch Patch) error {
+	if tracker.frozen {
+		return ErrFrozen
+	}
+	for i, op := range patch {
+		field, ok := tracker.Field(op.Path)
+		if !ok {
+			return fmt.Errorf("patch operation %d: %w: %q", i, ErrUnknownField, op.Path)
+		}
+		if err := field.patch(op); err != nil {
+			return fmt.Errorf("patch operation %d on %q: %w", i, op.Path, err)
+		}
+		tracker.logPatch(op)
+	}
+	return nil
+}
+
+func (v *LazyScalar[T]) patch(op PatchOp) error {
+	switch op.Op {
+	case OpSet:
+		value, err := convert[T](op.Value)
+		if err != nil {
+			return err
+		}
+		v.Set(value)
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedOperation, op.Op)
+	}
+}
+
+func (s *LazySlice[T, I]) patch(op PatchOp) error {
+	switch op.Op {
+	case OpSet:
+		value, err := convert[T](op.Value)
+		if err != nil {
+			return err
+		}
+		s.Set(value)
+	case OpRemove:
+		id, err := convert[I](op.ID)
+		if err != nil {
+			return err
+		}
+		s.Remove(id)
+	case OpSetAll:
+		values, err := convert[[]T](op.Value)
+		if err != nil {
+			return err
+		}
+		s.SetAll(values)
+	case OpClear:
+		s.Clear()
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedOperation, op.Op)
+	}
+	return nil
+}
+
+func (v *LazyScalar[T]) base() Field {
+	return v
+}
+
+func (s *LazySlice[T, I]) base() Field {
+	return s
+}
+
+func (v *LazyScalar[T]) operations() []PatchOp {
+	if !v.isDirty {
+		return nil
+	}
+	return []PatchOp{{Op: OpSet, Value: v.value}}
+}
+
+func (s *LazySlice[T, I]) operations() []PatchOp {
+	var ops []PatchOp
+	if s.isReset {
+		ops = append(ops, PatchOp{Op: OpClear})
+	}
+	for c := range s.changesIterator() {
+		switch c.Status {
+		case Added, Modified:
+			ops = append(ops, PatchOp{Op: OpSet, ID: c.ID, Value: c.Value})
+		case Removed:
+			ops = append(ops, PatchOp{Op: OpRemove, ID: c.ID})
+		}
+	}
+	return ops
+}
+
+// convert converts a loosely typed value (eg: decoded from JSON) into T.
+func convert[T any](value any) (T, error) {
+	var zero T
+	if value == nil {
+		return zero, nil
+	}
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+	v, err := convertTo(value, reflect.TypeFor[T]())
+	if err != nil {
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// convertTo converts a loosely typed value (eg: decoded from JSON) into the target type.
+func convertTo(value any, target reflect.Type) (any, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Type() == target {
+		return value, nil
+	}
+	if isNumeric(rv.Kind()) && isNumeric(target.Kind()) {
+		return convertNumber(rv, target)
+	}
+	if s, ok := value.(string); ok {
+		ptr := reflect.New(target)
+		if u, ok := ptr.Interface().(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(s)); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+			}
+			return ptr.Elem().Interface(), nil
+		}
+		if rv.Type().ConvertibleTo(target) {
+			return rv.Convert(target).Interface(), nil
+		}
+	}
+
+	// last resort: round trip through JSON
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+	ptr := reflect.New(target)
+	if err := json.Unmarshal(b, ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("%w: cannot convert %T to %s: %w", ErrInvalidValue, value, target, err)
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+// convertNumber converts a number into the numeric target type, failing if the number does not fit
+// or, for integer types, is not an integer.
+func convertNumber(rv reflect.Value, target reflect.Type) (any, error) {
+	out := reflect.New(target).Elem()
+	fits := true
+	switch {
+	case rv.CanInt():
+		i := rv.Int()
+		switch {
+		case out.CanInt():
+			fits = !out.OverflowInt(i)
+		case out.CanUint():
+			fits = i >= 0 && !out.OverflowUint(uint64(i))
+		}
+	case rv.CanUint():
+		u := rv.Uint()
+		switch {
+		case out.CanInt():
+			fits = u <= math.MaxInt64 && !out.OverflowInt(int64(u))
+		case out.CanUint():
+			fits = !out.OverflowUint(u)
+		}
+	default:
+		f := rv.Float()
+		switch {
+		case out.CanInt():
+			fits = f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !out.OverflowInt(int64(f))
+		case out.CanUint():
+			fits = f == math.Trunc(f) && f >= 0 && f < math.MaxUint64 && !out.OverflowUint(uint64(f))
+		default:
+			fits = !out.OverflowFloat(f)
+		}
+	}
+	if !fits {
+		return nil, fmt.Errorf("%w: %v does not fit in %s", ErrInvalidValue, rv.Interface(), target)
+	}
+	return rv.Convert(target).Interface(), nil
+}
+
+func isNumeric(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,143 @@
+package delta_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type keyedEntity struct {
+	Key  int    `json:"key"`
+	Name string `json:"name"`
+}
+
+func (e keyedEntity) ID() int {
+	return e.Key
+}
+
+func TestApplyPatch(t *testing.T) {
+	name := delta.NewLazy(func() (string, error) {
+		return "john", nil
+	})
+	items := delta.NewLazySlice(func(int) ([]keyedEntity, error) {
+		return []keyedEntity{{Key: 1, Name: "one"}, {Key: 2, Name: "two"}}, nil
+	})
+	tracker := delta.NewTracker()
+	tracker.Register("name", name)
+	tracker.Register("items", items)
+
+	var patch delta.Patch
+	err := json.Unmarshal([]byte(`[
+		{"op": "set", "path": "name", "value": "jane"},
+		{"op": "remove", "path": "items", "id": 1},
+		{"op": "set", "path": "items", "value": {"key": 3, "name": "three"}}
+	]`), &patch)
+	require.NoError(t, err)
+
+	err = delta.ApplyPatch(tracker, patch)
+	require.NoError(t, err)
+
+	change := name.Change()
+	require.NotNil(t, change)
+	assert.Equal(t, "jane", change.Value)
+
+	changes := slices.Collect(items.Changes().Items)
+	require.Len(t, changes, 2)
+	assert.Equal(t, 1, changes[0].ID)
+	assert.Equal(t, delta.Removed, changes[0].Status)
+	assert.Equal(t, 3, changes[1].ID)
+	assert.Equal(t, delta.Added, changes[1].Status)
+	assert.Equal(t, "three", changes[1].Value.Name)
+
+	seq, err := items.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, []keyedEntity{{Key: 3, Name: "three"}, {Key: 2, Name: "two"}}, slices.Collect(seq))
+}
+
+func TestApplyPatch_SetAll(t *testing.T) {
+	items := delta.NewSlice([]keyedEntity{{Key: 1, Name: "one"}})
+	tracker := delta.NewTracker()
+	tracker.Register("items", items)
+
+	err := delta.ApplyPatch(tracker, delta.Patch{
+		{Op: delta.OpSetAll, Path: "items", Value: []any{map[string]any{"key": 2, "name": "two"}}},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, items.IsReset())
+	assert.Equal(t, []keyedEntity{{Key: 2, Name: "two"}}, slices.Collect(items.GetAll()))
+}
+
+func TestApplyPatch_ConvertsTextIDs(t *testing.T) {
+	id := uuid.New()
+	items := delta.NewSlice([]*testUUIDEntity{{id: id}})
+	tracker := delta.NewTracker()
+	tracker.Register("items", items)
+
+	err := delta.ApplyPatch(tracker, delta.Patch{
+		{Op: delta.OpRemove, Path: "items", ID: id.String()},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, slices.Collect(items.GetAll()))
+}
+
+func TestApplyPatch_Errors(t *testing.T) {
+	tracker := delta.NewTracker()
+	tracker.Register("name", delta.New("john"))
+
+	err := delta.ApplyPatch(tracker, delta.Patch{{Op: delta.OpSet, Path: "unknown", Value: "x"}})
+	require.ErrorIs(t, err, delta.ErrUnknownField)
+
+	err = delta.ApplyPatch(tracker, delta.Patch{{Op: delta.OpRemove, Path: "name"}})
+	require.ErrorIs(t, err, delta.ErrUnsupportedOperation)
+
+	err = delta.ApplyPatch(tracker, delta.Patch{{Op: delta.OpSet, Path: "name", Value: []int{1}}})
+	require.ErrorIs(t, err, delta.ErrInvalidValue)
+}
+
+func TestApplyPatch_LossyNumbers(t *testing.T) {
+	small := delta.New(int8(0))
+	count := delta.New(uint(0))
+	ratio := delta.New(float32(0))
+	tracker := delta.NewTracker()
+	tracker.Register("small", small)
+	tracker.Register("count", count)
+	tracker.Register("ratio", ratio)
+
+	// numbers decoded from JSON are float64
+	require.NoError(t, delta.ApplyPatch(tracker, delta.Patch{
+		{Op: delta.OpSet, Path: "small", Value: float64(-128)},
+		{Op: delta.OpSet, Path: "count", Value: float64(7)},
+		{Op: delta.OpSet, Path: "ratio", Value: 0.5},
+	}))
+	assert.Equal(t, int8(-128), small.Get())
+	assert.Equal(t, uint(7), count.Get())
+	assert.Equal(t, float32(0.5), ratio.Get())
+
+	for _, op := range []delta.PatchOp{
+		{Op: delta.OpSet, Path: "small", Value: float64(300)},
+		{Op: delta.OpSet, Path: "small", Value: 1.5},
+		{Op: delta.OpSet, Path: "small", Value: 200},
+		{Op: delta.OpSet, Path: "count", Value: -1},
+		{Op: delta.OpSet, Path: "count", Value: float64(-1)},
+		{Op: delta.OpSet, Path: "ratio", Value: 1e300},
+	} {
+		err := delta.ApplyPatch(tracker, delta.Patch{op})
+		require.ErrorIs(t, err, delta.ErrInvalidValue, "%s = %v", op.Path, op.Value)
+	}
+	assert.Equal(t, int8(-128), small.Get())
+	assert.Equal(t, uint(7), count.Get())
+}
+
+type testUUIDEntity struct {
+	id uuid.UUID
+}
+
+func (e *testUUIDEntity) ID() uuid.UUID {
+	return e.id
+}
@@ -0,0 +1,211 @@
+package delta
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+)
+
+// Query is the query of a collection requested to a query loader.
+type Query[I comparable] struct {
+	Page[I]
+	Filter string // name of the filter, or empty for all the items
+	Args   []any  // arguments of the filter
+	Sort   []Sort
+}
+
+// Sort is a sort criterion of a query.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// OrderBy sorts the returned items by field, in ascending order.
+// Sorting is done by the query loader, so the query is never answered from the cached items.
+func OrderBy(field string) QueryOption {
+	return func(q *query) {
+		q.sort = append(q.sort, Sort{Field: field})
+	}
+}
+
+// OrderByDesc sorts the returned items by field, in descending order.
+func OrderByDesc(field string) QueryOption {
+	return func(q *query) {
+		q.sort = append(q.sort, Sort{Field: field, Desc: true})
+	}
+}
+
+// Filter is a named filter over the items of a collection.
+// The name and arguments are passed to the query loader, and Match, if defined,
+// applies the same filter to the items in memory, so that pending changes are taken into account
+// and the cached items can be used when all are loaded.
+type Filter[T any] struct {
+	Name  string
+	Args  []any
+	Match func(T) bool
+}
+
+// WithQueryLoader sets the loader used by LazySlice.GetWhere and by LazySlice.GetAll when called with query options,
+// if there is no page loader.
+// It panics when used with a collection of a different type.
+func WithQueryLoader[T Identifiable[I], I comparable](fn func(Query[I]) ([]T, error)) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optQueryLoader
+		o.querier = fn
+	})
+}
+
+func querierFor[T Identifiable[I], I comparable](opts options) func(Query[I]) ([]T, error) {
+	if opts.querier == nil {
+		return nil
+	}
+	querier, ok := opts.querier.(func(Query[I]) ([]T, error))
+	if !ok {
+		panic(fmt.Sprintf("delta: WithQueryLoader loader %T used with a collection of %s", opts.querier, reflect.TypeFor[T]()))
+	}
+	return querier
+}
+
+// GetWhere returns the items matching the filter.
+// The results of the query loader are cached per query, until the collection is evicted or invalidated.
+// Pending changes are applied over the results: removed items are skipped
+// and, if the filter has Match, modified items that no longer match are skipped and,
+// for queries without pagination, matching additions are included.
+func (s *LazySlice[T, I]) GetWhere(filter Filter[T], opts ...QueryOption) (iter.Seq[T], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+	return s.find(filter, opts)
+}
+
+func (s *LazySlice[T, I]) find(filter Filter[T], opts []QueryOption) (iter.Seq[T], error) {
+	q, err := queryOf[I](filter.Name, filter.Args, opts)
+	if err != nil {
+		return nil, err
+	}
+	inMemory := filter.Name == "" || filter.Match != nil
+	if s.isSet && inMemory && len(q.Sort) == 0 {
+		s.counters.hit()
+		s.budget.touch(s)
+		return paginate(matching(filterRemoved(s.fetched.Values()), filter.Match), q.Page), nil
+	}
+	if s.isReset {
+		return nil, fmt.Errorf("%w: query over a reset collection", ErrUnsupportedOperation)
+	}
+
+	key := fmt.Sprintf("%v", q)
+	ids, ok := s.queries[key]
+	if ok {
+		s.counters.hit()
+		s.budget.touch(s)
+	} else {
+		ids, err = s.loadQuery(q)
+		if err != nil {
+			return nil, err
+		}
+		if s.queries == nil {
+			s.queries = map[string][]I{}
+		}
+		s.queries[key] = ids
+	}
+
+	unpaged := q.Limit == 0 && q.Offset == 0 && !q.HasAfter
+	return func(yield func(T) bool) {
+		for _, id := range ids {
+			item, ok := s.fetched.Get(id)
+			if !ok || item.status == Removed || item.status == Absent {
+				continue
+			}
+			if filter.Match != nil && !filter.Match(item.value) {
+				continue
+			}
+			if !yield(item.value) {
+				return
+			}
+		}
+		if !unpaged || filter.Match == nil {
+			return
+		}
+		for id, item := range s.fetched.Entries() {
+			if item.status != Added || !filter.Match(item.value) || slices.Contains(ids, id) {
+				continue
+			}
+			if !yield(item.value) {
+				return
+			}
+		}
+	}, nil
+}
+
+// loadQuery loads the items of a query into the cache, returning their IDs.
+func (s *LazySlice[T, I]) loadQuery(q Query[I]) ([]I, error) {
+	var load func() ([]T, error)
+	switch {
+	case s.pager != nil && q.Filter == "" && len(q.Sort) == 0:
+		load = func() ([]T, error) { return s.pager(q.Page) }
+	case s.querier != nil:
+		load = func() ([]T, error) { return s.querier(q) }
+	default:
+		return nil, fmt.Errorf("%w: query without a query loader", ErrUnsupportedOperation)
+	}
+
+	s.counters.miss()
+	start := s.now()
+	values, err := load()
+	if err != nil {
+		s.counters.failed(s.now().Sub(start))
+		return nil, err
+	}
+	loadedAt := s.now()
+	s.counters.loaded(sizeOfAll(values), loadedAt.Sub(start))
+	if s.loadedAt.IsZero() {
+		s.loadedAt = loadedAt
+	}
+
+	ids := make([]I, 0, len(values))
+	for _, v := range values {
+		s.merge(v)
+		ids = append(ids, v.ID())
+	}
+	s.budget.loaded(s)
+	return ids, nil
+}
+
+func matching[T any](it iter.Seq[T], match func(T) bool) iter.Seq[T] {
+	if match == nil {
+		return it
+	}
+	return func(yield func(T) bool) {
+		for v := range it {
+			if match(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func paginate[T Identifiable[I], I comparable](it iter.Seq[T], page Page[I]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		skip, count := page.Offset, 0
+		started := !page.HasAfter
+		for v := range it {
+			if !started {
+				started = v.ID() == page.After
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if page.Limit > 0 && count == page.Limit {
+				return
+			}
+			count++
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package delta_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazySlice_GetWhere(t *testing.T) {
+	ents := []*testEntity{
+		{id: "1", name: "active1"},
+		{id: "2", name: "inactive2"},
+		{id: "3", name: "active3"},
+	}
+	var queries []delta.Query[string]
+	lazySlice := delta.NewLazySlice(fetcher(ents), delta.WithQueryLoader(func(q delta.Query[string]) ([]*testEntity, error) {
+		queries = append(queries, q)
+		var result []*testEntity
+		for _, e := range ents {
+			if q.Filter != "active" || strings.HasPrefix(e.name, "active") {
+				result = append(result, e)
+			}
+		}
+		return result, nil
+	}))
+	active := delta.Filter[*testEntity]{
+		Name: "active",
+		Match: func(e *testEntity) bool {
+			return strings.HasPrefix(e.name, "active")
+		},
+	}
+
+	values, err := lazySlice.GetWhere(active)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, ids(slices.Collect(values)))
+
+	// pending changes are applied over the cached results
+	lazySlice.Set(&testEntity{id: "3", name: "inactive3"})
+	lazySlice.Set(&testEntity{id: "4", name: "active4"})
+	values, err = lazySlice.GetWhere(active)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "4"}, ids(slices.Collect(values)))
+	assert.Len(t, queries, 1)
+
+	// sorting is done by the loader
+	_, err = lazySlice.GetWhere(active, delta.OrderByDesc("name"))
+	require.NoError(t, err)
+	require.Len(t, queries, 2)
+	assert.Equal(t, []delta.Sort{{Field: "name", Desc: true}}, queries[1].Sort)
+
+	// once all are loaded, the filter is applied in memory
+	_, err = lazySlice.GetAll()
+	require.NoError(t, err)
+	values, err = lazySlice.GetWhere(active, delta.Limit(1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids(slices.Collect(values)))
+	assert.Len(t, queries, 2)
+}
+
+func TestLazySlice_GetWhere_WithoutQueryLoader(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher(nil))
+	_, err := lazySlice.GetWhere(delta.Filter[*testEntity]{Name: "active"})
+	require.ErrorIs(t, err, delta.ErrUnsupportedOperation)
+}
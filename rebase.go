@@ -0,0 +1,373 @@
+package delta
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+var ErrConflict = errors.New("merge conflict")
+
+// Conflict is a change of mine and a change of theirs, made over the same base, that cannot be merged.
+type Conflict struct {
+	Field string // registered name of the field, when reported by a tracker
+	ID    any    // id of the item, for collections
+	// Base, Mine and Theirs are the values on each side, or nil if the item does not exist on that side.
+	Base   any
+	Mine   any
+	Theirs any
+}
+
+func (c Conflict) String() string {
+	switch {
+	case c.Field != "" && c.ID != nil:
+		return fmt.Sprintf("%s[%v]", c.Field, c.ID)
+	case c.Field != "":
+		return c.Field
+	case c.ID != nil:
+		return fmt.Sprint(c.ID)
+	default:
+		return "value"
+	}
+}
+
+// ConflictError reports the conflicts of a merge. It matches ErrConflict.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	names := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		names[i] = c.String()
+	}
+	return fmt.Sprintf("%s: %s", ErrConflict, strings.Join(names, ", "))
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// side is a value on one side of a merge, that may not exist.
+type side[T any] struct {
+	value  T
+	exists bool
+}
+
+func (s side[T]) any() any {
+	if !s.exists {
+		return nil
+	}
+	return s.value
+}
+
+// merge3 merges the changes of mine and theirs over base, returning false if both changed it differently.
+func merge3[T any](base, mine, theirs side[T], equal func(a, b T) bool) (side[T], bool) {
+	same := func(a, b side[T]) bool {
+		return a.exists == b.exists && (!a.exists || equal(a.value, b.value))
+	}
+	switch {
+	case same(mine, base), same(mine, theirs):
+		return theirs, true
+	case same(theirs, base):
+		return mine, true
+	default:
+		return side[T]{}, false
+	}
+}
+
+func mergeEqual[T any](equal func(a, b T) bool) func(a, b T) bool {
+	if equal != nil {
+		return equal
+	}
+	return func(a, b T) bool { return reflect.DeepEqual(a, b) }
+}
+
+// MergeValue merges the changes of mine and theirs, both made over base:
+// if only one side changed the value, that change is kept. If both changed it to different values, it returns a *ConflictError.
+// Values are compared with equal or, if it is nil, with reflect.DeepEqual.
+func MergeValue[T any](base, mine, theirs T, equal func(a, b T) bool) (T, error) {
+	return mergeValue(base, mine, theirs, equal, nil)
+}
+
+func mergeValue[T any](base, mine, theirs T, equal func(a, b T) bool, resolve Resolver) (T, error) {
+	b, m, t := side[T]{base, true}, side[T]{mine, true}, side[T]{theirs, true}
+	merged, ok := merge3(b, m, t, mergeEqual(equal))
+	if ok {
+		return merged.value, nil
+	}
+	c := Conflict{Base: base, Mine: mine, Theirs: theirs}
+	r, err := resolved[T](c, resolve)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return r.value, nil
+}
+
+// resolved resolves a conflict with resolve, if any, returning a *ConflictError if it is not resolved.
+// A resolved nil value is an item that does not exist.
+func resolved[T any](c Conflict, resolve Resolver) (side[T], error) {
+	if resolve == nil {
+		return side[T]{}, &ConflictError{Conflicts: []Conflict{c}}
+	}
+	v, err := resolve(c)
+	switch {
+	case errors.Is(err, ErrConflict):
+		return side[T]{}, &ConflictError{Conflicts: []Conflict{c}}
+	case err != nil:
+		return side[T]{}, err
+	case v == nil:
+		return side[T]{}, nil
+	}
+	value, err := convert[T](v)
+	if err != nil {
+		return side[T]{}, err
+	}
+	return side[T]{value, true}, nil
+}
+
+// Merge merges, item by item, the changes of mine and theirs, both made over base, as MergeValue.
+// An item added, modified or removed by only one side keeps that change, and an item changed by both in the same way
+// is kept once. The other items changed by both are reported in a *ConflictError, with their IDs.
+// The merged items are in the order of theirs, followed by the ones only in mine.
+func Merge[T Identifiable[I], I comparable](base, mine, theirs []T, equal func(a, b T) bool) ([]T, error) {
+	return mergeItems(base, mine, theirs, equal, nil)
+}
+
+func mergeItems[T Identifiable[I], I comparable](base, mine, theirs []T, equal func(a, b T) bool, resolve Resolver) ([]T, error) {
+	equal = mergeEqual(equal)
+	bases, mines := sidesByID(base), sidesByID(mine)
+	var merged []T
+	var conflicts []Conflict
+	merge := func(id I, theirs side[T]) error {
+		b, m := bases[id], mines[id]
+		r, ok := merge3(b, m, theirs, equal)
+		if !ok {
+			var err error
+			r, err = resolved[T](Conflict{ID: id, Base: b.any(), Mine: m.any(), Theirs: theirs.any()}, resolve)
+			var ce *ConflictError
+			if errors.As(err, &ce) {
+				conflicts = append(conflicts, ce.Conflicts...)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if r.exists {
+			merged = append(merged, r.value)
+		}
+		return nil
+	}
+	inTheirs := make(map[I]bool, len(theirs))
+	for _, v := range theirs {
+		inTheirs[v.ID()] = true
+		if err := merge(v.ID(), side[T]{v, true}); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range mine {
+		if inTheirs[v.ID()] {
+			continue
+		}
+		if err := merge(v.ID(), side[T]{}); err != nil {
+			return nil, err
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, &ConflictError{Conflicts: conflicts}
+	}
+	return merged, nil
+}
+
+func sidesByID[T Identifiable[I], I comparable](values []T) map[I]side[T] {
+	m := make(map[I]side[T], len(values))
+	for _, v := range values {
+		m[v.ID()] = side[T]{v, true}
+	}
+	return m
+}
+
+// Rebase replaces the fetched value with theirs, fresher from the store (eg: after an optimistic lock failure),
+// keeping the pending change on top of it, as merged by MergeValue with the fetched value as base.
+// A value set without being fetched overrides theirs.
+// If both changed the value differently, it returns a *ConflictError and the scalar is not changed.
+func (v *LazyScalar[T]) Rebase(theirs T) error {
+	return v.rebaseWith(theirs, nil)
+}
+
+func (v *LazyScalar[T]) rebaseWith(theirs T, resolve Resolver) error {
+	if err := v.mut.writable(); err != nil {
+		return err
+	}
+	v.mut.begin()
+	defer v.mut.end()
+
+	value := theirs
+	if v.isDirty {
+		base := theirs
+		if v.hasOrig {
+			base = v.original
+		}
+		merged, err := mergeValue(base, v.value, theirs, v.equal, resolve)
+		if err != nil {
+			return err
+		}
+		value = merged
+	}
+	v.original, v.hasOrig = theirs, true
+	v.value = value
+	v.isSet = true
+	v.isDirty = !mergeEqual(v.equal)(value, theirs)
+	v.fetchedAt = v.now()
+	v.updated()
+	return nil
+}
+
+// Rebase replaces the fetched items with theirs, all the items fresher from the store (eg: after an optimistic lock failure),
+// keeping the pending changes on top of them, as merged by Merge with the fetched items as base.
+// Items changed without being fetched override theirs.
+// If both changed the same items differently, it returns a *ConflictError and the collection is not changed.
+// A reset collection cannot be rebased.
+func (s *LazySlice[T, I]) Rebase(theirs []T) error {
+	return s.rebaseWith(theirs, nil)
+}
+
+func (s *LazySlice[T, I]) rebaseWith(theirs []T, resolve Resolver) error {
+	if err := s.mut.writable(); err != nil {
+		return err
+	}
+	if s.isReset {
+		return fmt.Errorf("%w: rebase of a reset collection", ErrUnsupportedOperation)
+	}
+	s.mut.begin()
+	defer s.mut.end()
+
+	fresh := sidesByID(theirs)
+	var base, mine []T
+	for id, item := range s.fetched.Entries() {
+		// the base of a change made without fetching the item is unknown, so the change overrides theirs
+		blind := fresh[id]
+		switch item.effectiveStatus() {
+		case Unchanged:
+			base = append(base, item.value)
+			mine = append(mine, item.value)
+		case Added:
+			mine = append(mine, item.value)
+		case Modified:
+			if item.status == Modified && item.hasOld {
+				base = append(base, item.old)
+			} else if blind.exists {
+				base = append(base, blind.value)
+			}
+			mine = append(mine, item.value)
+		case Removed:
+			if item.hasOld {
+				base = append(base, item.old)
+			} else if blind.exists {
+				base = append(base, blind.value)
+			}
+		}
+	}
+	merged, err := mergeItems(base, mine, theirs, s.equal, resolve)
+	if err != nil {
+		return err
+	}
+
+	fetched := linkedmap.New(linkedmap.WithCapacity[I, Item[T, I]](max(len(theirs), len(merged))))
+	for _, v := range theirs {
+		fetched.Put(v.ID(), Item[T, I]{value: v, status: Unchanged})
+	}
+	for c := range DiffSlices(theirs, merged, s.equal).Items {
+		item := Item[T, I]{value: c.Value, status: c.Status, old: c.Old, hasOld: c.HasOld}
+		if prev, ok := s.fetched.Get(c.ID); ok {
+			item.meta = prev.meta
+		}
+		fetched.Put(c.ID, item)
+	}
+	s.isSet = true
+	s.fetchedAt = s.now()
+	s.loadedAt = s.fetchedAt
+	s.queries = nil
+	s.existing = nil
+	s.replaceFetched(fetched)
+	return nil
+}
+
+// rebaser is implemented by the fields that can be rebased on a fresher state. See Tracker.Rebase.
+type rebaser interface {
+	rebase(theirs any, resolve Resolver) error
+}
+
+func (v *LazyScalar[T]) rebase(theirs any, resolve Resolver) error {
+	value, err := convert[T](theirs)
+	if err != nil {
+		return err
+	}
+	return v.rebaseWith(value, resolve)
+}
+
+func (s *LazySlice[T, I]) rebase(theirs any, resolve Resolver) error {
+	values, err := convert[[]T](theirs)
+	if err != nil {
+		return err
+	}
+	return s.rebaseWith(values, resolve)
+}
+
+// Rebase rebases the registered fields on their fresher state, by name (eg: reloaded after an optimistic lock failure),
+// keeping their pending changes on top of it, as LazyScalar.Rebase and LazySlice.Rebase.
+// Fields without a fresher state are left as they are.
+// The conflicts of a field are resolved with the resolver registered with WithResolver, if any.
+// If any field conflicts, it returns a *ConflictError with the conflicts of all the fields, and no field is changed.
+func (t *Tracker) Rebase(fresh map[string]any) error {
+	if t.frozen {
+		return ErrFrozen
+	}
+	for name := range fresh {
+		if _, ok := t.fields.Get(name); !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownField, name)
+		}
+	}
+	snapshots := map[string]any{}
+	restore := func() {
+		for name, snap := range snapshots {
+			field, _ := t.fields.Get(name)
+			field.restoreSnapshot(snap)
+		}
+	}
+	var conflicts []Conflict
+	for name, field := range t.fields.Entries() {
+		theirs, ok := fresh[name]
+		if !ok {
+			continue
+		}
+		r, ok := field.base().(rebaser)
+		if !ok {
+			restore()
+			return fmt.Errorf("rebase of %q: %w", name, ErrUnsupportedOperation)
+		}
+		snapshots[name] = field.snapshot()
+		err := r.rebase(theirs, t.resolvers[name])
+		var ce *ConflictError
+		switch {
+		case errors.As(err, &ce):
+			for _, c := range ce.Conflicts {
+				c.Field = name
+				conflicts = append(conflicts, c)
+			}
+		case err != nil:
+			restore()
+			return fmt.Errorf("rebase of %q: %w", name, err)
+		}
+	}
+	if len(conflicts) > 0 {
+		restore()
+		return &ConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package delta_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeValue(t *testing.T) {
+	v, err := delta.MergeValue(1, 2, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+
+	v, err = delta.MergeValue(1, 1, 3, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, v)
+
+	_, err = delta.MergeValue(1, 2, 3, nil)
+	var ce *delta.ConflictError
+	require.True(t, errors.As(err, &ce))
+	assert.ErrorIs(t, err, delta.ErrConflict)
+	assert.Equal(t, []delta.Conflict{{Base: 1, Mine: 2, Theirs: 3}}, ce.Conflicts)
+}
+
+func TestMerge(t *testing.T) {
+	base := []*testEntity{{id: "1", name: "a"}, {id: "2", name: "b"}, {id: "3", name: "c"}}
+	mine := []*testEntity{{id: "1", name: "a"}, {id: "3", name: "c"}, {id: "4", name: "d"}}
+	theirs := []*testEntity{{id: "2", name: "b"}, {id: "3", name: "theirs"}, {id: "5", name: "e"}}
+
+	merged, err := delta.Merge(base, mine, theirs, nil)
+	require.NoError(t, err)
+	// 1 was removed by them, 2 by me, 3 modified by them, 4 added by me and 5 by them
+	assert.Equal(t, []*testEntity{{id: "3", name: "theirs"}, {id: "5", name: "e"}, {id: "4", name: "d"}}, merged)
+
+	mine = []*testEntity{{id: "1", name: "mine"}, {id: "2", name: "b"}}
+	theirs = []*testEntity{{id: "1", name: "theirs"}, {id: "2", name: "b"}}
+	_, err = delta.Merge(base, mine, theirs, nil)
+	var ce *delta.ConflictError
+	require.True(t, errors.As(err, &ce))
+	assert.Equal(t, []delta.Conflict{
+		{ID: "1", Base: base[0], Mine: mine[0], Theirs: theirs[0]},
+	}, ce.Conflicts)
+}
+
+func TestLazySlice_Rebase(t *testing.T) {
+	items := delta.NewSlice([]*testEntity{{id: "1", name: "a"}, {id: "2", name: "b"}})
+	items.Set(&testEntity{id: "1", name: "mine"})
+	items.Set(&testEntity{id: "3", name: "c"})
+
+	require.NoError(t, items.Rebase([]*testEntity{{id: "1", name: "a"}, {id: "2", name: "theirs"}}))
+	assert.Equal(t, []*testEntity{{id: "1", name: "mine"}, {id: "2", name: "theirs"}, {id: "3", name: "c"}}, slices.Collect(items.GetAll()))
+	changes := changesByID(items.Changes())
+	require.Len(t, changes, 2)
+	assert.Equal(t, delta.Modified, changes["1"].Status)
+	assert.Equal(t, &testEntity{id: "1", name: "a"}, changes["1"].Old)
+	assert.Equal(t, delta.Added, changes["3"].Status)
+
+	err := items.Rebase([]*testEntity{{id: "1", name: "other"}})
+	require.ErrorIs(t, err, delta.ErrConflict)
+	// unchanged
+	assert.Equal(t, []string{"1", "3"}, changedIDs(items.Changes()))
+}
+
+func TestTracker_Rebase(t *testing.T) {
+	name := delta.New("Paulo")
+	age := delta.New(40)
+	tracker := delta.NewTracker()
+	tracker.Register("name", name)
+	tracker.Register("age", age)
+
+	name.Set("Quintans")
+	require.NoError(t, tracker.Rebase(map[string]any{"name": "Paulo", "age": 41}))
+	assert.Equal(t, "Quintans", name.Get())
+	assert.Equal(t, 41, age.Get())
+	assert.False(t, age.IsDirty())
+
+	age.Set(42)
+	err := tracker.Rebase(map[string]any{"name": "Other", "age": 43})
+	var ce *delta.ConflictError
+	require.True(t, errors.As(err, &ce))
+	assert.Equal(t, []delta.Conflict{
+		{Field: "name", Base: "Paulo", Mine: "Quintans", Theirs: "Other"},
+		{Field: "age", Base: 41, Mine: 42, Theirs: 43},
+	}, ce.Conflicts)
+	assert.Equal(t, 42, age.Get())
+
+	require.ErrorIs(t, tracker.Rebase(map[string]any{"unknown": 1}), delta.ErrUnknownField)
+}
@@ -0,0 +1,86 @@
+package delta
+
+import (
+	"time"
+)
+
+// Invalidate drops the loaded value, so that it is loaded again on the next access.
+// A pending change is kept.
+func (v *LazyScalar[T]) Invalidate() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.invalidate()
+}
+
+func (v *LazyScalar[T]) invalidate() {
+	if v.fn == nil {
+		return
+	}
+	v.errs.clear()
+	var zero T
+	v.original, v.hasOrig = zero, false
+	v.fetchedAt = time.Time{}
+	if v.isDirty {
+		return
+	}
+	v.value = zero
+	v.isSet = false
+	v.updated()
+}
+
+// Reload loads the value again, keeping a pending change on top of it.
+func (v *LazyScalar[T]) Reload() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.fn == nil {
+		return nil
+	}
+	v.errs.clear()
+	v.counters.miss()
+	_, err := v.fetch()
+	return err
+}
+
+// Invalidate drops the loaded items, so that they are loaded again on the next access.
+// Pending changes are kept, unless the collection was reset, in which case nothing is dropped.
+func (s *LazySlice[T, I]) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.invalidate()
+}
+
+func (s *LazySlice[T, I]) invalidate() {
+	if s.isReset || s.fn == nil {
+		return
+	}
+	s.isSet = false
+	s.fetchedAt = time.Time{}
+	s.loadedAt = time.Time{}
+	s.queries = nil
+	s.existing = nil
+	s.errs.clear()
+	// not a change of the collection, so it is not recorded in the history
+	s.fetched = filterItems(s.fetched, func(item Item[T, I]) (Item[T, I], bool) {
+		if item.status == Unchanged || item.status == Absent {
+			return item, false
+		}
+		var zero T
+		item.old, item.hasOld = zero, false
+		return item, true
+	})
+	s.recount()
+}
+
+// Reload loads all the items again, keeping the pending changes on top of them.
+// Concurrent reads wait for the reload, instead of seeing the items dropped.
+func (s *LazySlice[T, I]) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.invalidate()
+	_, err := s.getAll()
+	return err
+}
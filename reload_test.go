@@ -0,0 +1,140 @@
+package delta_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyScalar_Invalidate(t *testing.T) {
+	stored := "v1"
+	callCount := 0
+	scalar := delta.NewLazy(func() (string, error) {
+		callCount++
+		return stored, nil
+	})
+	_, err := scalar.Get()
+	require.NoError(t, err)
+
+	stored = "v2"
+	scalar.Invalidate()
+	assert.False(t, scalar.IsLoaded())
+	assert.Equal(t, "v2", scalar.MustGet())
+	assert.Equal(t, 2, callCount)
+
+	// pending changes are kept
+	scalar.Set("mine")
+	scalar.Invalidate()
+	assert.Equal(t, "mine", scalar.MustGet())
+	assert.Equal(t, 2, callCount)
+}
+
+func TestLazyScalar_Reload(t *testing.T) {
+	stored := "v1"
+	scalar := delta.NewLazy(func() (string, error) {
+		return stored, nil
+	})
+	_, err := scalar.Get()
+	require.NoError(t, err)
+
+	stored = "v2"
+	require.NoError(t, scalar.Reload())
+	assert.Equal(t, "v2", scalar.MustGet())
+
+	scalar.Set("mine")
+	stored = "v3"
+	require.NoError(t, scalar.Reload())
+	assert.Equal(t, "mine", scalar.MustGet())
+	scalar.DiscardChanges()
+	assert.Equal(t, "v3", scalar.MustGet())
+}
+
+func TestLazySlice_Reload(t *testing.T) {
+	stored := []*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}
+	lazySlice := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		return fetcher(stored)(id)
+	})
+	_, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	lazySlice.Set(&testEntity{id: "1", name: "entity1_mine"})
+
+	stored = []*testEntity{
+		{id: "1", name: "entity1_theirs"},
+		{id: "2", name: "entity2_theirs"},
+		{id: "3", name: "entity3"},
+	}
+	require.NoError(t, lazySlice.Reload())
+	all, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*testEntity{
+		{id: "1", name: "entity1_mine"},
+		{id: "2", name: "entity2_theirs"},
+		{id: "3", name: "entity3"},
+	}, slices.Collect(all))
+
+	changes := slices.Collect(lazySlice.Changes().Items)
+	require.Len(t, changes, 1)
+	assert.Equal(t, delta.Modified, changes[0].Status)
+
+	// discarding restores the reloaded value
+	lazySlice.DiscardChanges()
+	v, err := lazySlice.Get("1")
+	require.NoError(t, err)
+	assert.Equal(t, "entity1_theirs", v.name)
+}
+
+func TestLazySlice_Invalidate(t *testing.T) {
+	callCount := 0
+	lazySlice := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		callCount++
+		return fetcher([]*testEntity{{id: "1", name: "entity1"}})(id)
+	})
+	_, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	lazySlice.Invalidate()
+	assert.False(t, lazySlice.IsLoaded())
+	_, err = lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, 2, callCount)
+
+	// nothing is dropped from a reset collection
+	lazySlice.SetAll([]*testEntity{{id: "2", name: "entity2"}})
+	lazySlice.Invalidate()
+	assert.True(t, lazySlice.IsLoaded())
+	assert.Equal(t, 2, callCount)
+}
+
+func TestLazySlice_Reload_Concurrent(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	scalar := delta.NewLazy(func() (string, error) {
+		return "John", nil
+	})
+
+	// readers never see the items dropped by a reload
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for range 100 {
+			assert.NoError(t, lazySlice.Reload())
+			scalar.Invalidate()
+		}
+	})
+	wg.Go(func() {
+		for range 100 {
+			all, err := lazySlice.GetAll()
+			assert.NoError(t, err)
+			assert.Len(t, slices.Collect(all), 2)
+			assert.Equal(t, "John", scalar.MustGet())
+		}
+	})
+	wg.Wait()
+}
@@ -0,0 +1,53 @@
+package delta
+
+import "reflect"
+
+// ReplaceAll replaces all the items, loading them if needed, and records the differences by ID,
+// instead of resetting the collection like SetAll:
+// missing items are removed, new ones are added and the ones that differ are modified.
+// Items are compared with the WithEqual comparator or, if there is none, with reflect.DeepEqual.
+// An item replaced by its fetched value is no longer modified.
+func (s *LazySlice[T, I]) ReplaceAll(values []T) error {
+	if err := s.mut.writable(); err != nil {
+		return err
+	}
+	current, err := s.GetAll()
+	if err != nil {
+		return err
+	}
+	s.mut.begin()
+	defer s.mut.end()
+
+	keep := make(map[I]struct{}, len(values))
+	for _, v := range values {
+		keep[v.ID()] = struct{}{}
+	}
+	var removed []I
+	for v := range current {
+		if _, ok := keep[v.ID()]; !ok {
+			removed = append(removed, v.ID())
+		}
+	}
+	for _, id := range removed {
+		s.Remove(id)
+	}
+
+	for _, v := range values {
+		item, ok := s.fetched.Get(v.ID())
+		switch {
+		case ok && item.hasOld && s.same(item.old, v):
+			s.put(v.ID(), Item[T, I]{value: v, status: Unchanged})
+		case ok && item.effectiveStatus() == Unchanged && s.same(item.value, v):
+		default:
+			s.Set(v)
+		}
+	}
+	return nil
+}
+
+func (s *LazySlice[T, I]) same(a, b T) bool {
+	if s.equal != nil {
+		return s.equal(a, b)
+	}
+	return reflect.DeepEqual(a, b)
+}
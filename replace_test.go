@@ -0,0 +1,45 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazySlice_ReplaceAll(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+		{id: "3", name: "entity3"},
+	}))
+	lazySlice.Set(&testEntity{id: "3", name: "entity3_new"})
+
+	err := lazySlice.ReplaceAll([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "3", name: "entity3"},
+		{id: "4", name: "entity4"},
+	})
+	require.NoError(t, err)
+
+	changes := lazySlice.Changes()
+	assert.False(t, changes.Reset)
+	assert.Equal(t, []delta.SliceChange[string, *testEntity]{
+		{ID: "2", Status: delta.Removed, Old: &testEntity{id: "2", name: "entity2"}, HasOld: true},
+		{ID: "4", Value: &testEntity{id: "4", name: "entity4"}, Status: delta.Added},
+	}, slices.Collect(changes.Items))
+}
+
+func TestLazySlice_ReplaceAll_WithEqual(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+	}), delta.WithEqual(func(a, b *testEntity) bool {
+		return a.id == b.id
+	}))
+
+	err := lazySlice.ReplaceAll([]*testEntity{{id: "1", name: "ignored"}})
+	require.NoError(t, err)
+	assert.False(t, lazySlice.IsDirty())
+}
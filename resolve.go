@@ -0,0 +1,72 @@
+package delta
+
+import "slices"
+
+// Resolver resolves a conflict found by Tracker.Rebase, returning the merged value,
+// or the merged item for collections, where nil means that the item is removed.
+// Returning ErrConflict leaves the conflict unresolved.
+type Resolver func(c Conflict) (any, error)
+
+// WithResolver registers the resolver of the conflicts of a field in Tracker.Rebase.
+func WithResolver(field string, r Resolver) TrackerOption {
+	return trackerOptionFunc(func(t *Tracker) {
+		if t.resolvers == nil {
+			t.resolvers = map[string]Resolver{}
+		}
+		t.resolvers[field] = r
+	})
+}
+
+// LastWriterWins resolves a conflict keeping our change, since it is written after theirs.
+func LastWriterWins(c Conflict) (any, error) {
+	return c.Mine, nil
+}
+
+// ResolveWith returns a resolver of conflicts on values, or items, of type T.
+func ResolveWith[T any](fn func(base, mine, theirs T) (T, error)) Resolver {
+	return func(c Conflict) (any, error) {
+		base, err := convert[T](c.Base)
+		if err != nil {
+			return nil, err
+		}
+		mine, err := convert[T](c.Mine)
+		if err != nil {
+			return nil, err
+		}
+		theirs, err := convert[T](c.Theirs)
+		if err != nil {
+			return nil, err
+		}
+		return fn(base, mine, theirs)
+	}
+}
+
+// Number is a type that can be added.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Additive resolves the conflicts of a counter by adding both increments to the base.
+func Additive[T Number]() Resolver {
+	return ResolveWith(func(base, mine, theirs T) (T, error) {
+		return mine + theirs - base, nil
+	})
+}
+
+// Union resolves the conflicts of a slice used as a set (eg: tags) by adding, to theirs, the members we added
+// and removing the ones we removed.
+func Union[T comparable]() Resolver {
+	return ResolveWith(func(base, mine, theirs []T) ([]T, error) {
+		merged := slices.Clone(theirs)
+		for _, v := range mine {
+			if !slices.Contains(base, v) && !slices.Contains(merged, v) {
+				merged = append(merged, v)
+			}
+		}
+		return slices.DeleteFunc(merged, func(v T) bool {
+			return slices.Contains(base, v) && !slices.Contains(mine, v)
+		}), nil
+	})
+}
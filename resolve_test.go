@@ -0,0 +1,57 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithResolver(t *testing.T) {
+	name := delta.New("Paulo")
+	visits := delta.New(10)
+	tags := delta.New([]string{"a", "b"})
+	items := delta.NewSlice([]*testEntity{{id: "1", name: "a"}})
+	tracker := delta.NewTracker(
+		delta.WithResolver("name", delta.LastWriterWins),
+		delta.WithResolver("visits", delta.Additive[int]()),
+		delta.WithResolver("tags", delta.Union[string]()),
+		delta.WithResolver("items", delta.ResolveWith(func(base, mine, theirs *testEntity) (*testEntity, error) {
+			return &testEntity{id: base.id, name: mine.name + "+" + theirs.name}, nil
+		})),
+	)
+	tracker.Register("name", name)
+	tracker.Register("visits", visits)
+	tracker.Register("tags", tags)
+	tracker.Register("items", items)
+
+	name.Set("Mine")
+	visits.Set(12)
+	tags.Set([]string{"a", "c"})
+	items.Set(&testEntity{id: "1", name: "mine"})
+
+	require.NoError(t, tracker.Rebase(map[string]any{
+		"name":   "Theirs",
+		"visits": 15,
+		"tags":   []string{"b", "d"},
+		"items":  []*testEntity{{id: "1", name: "theirs"}},
+	}))
+	assert.Equal(t, "Mine", name.Get())
+	assert.Equal(t, 17, visits.Get())
+	assert.Equal(t, []string{"d", "c"}, tags.Get())
+	assert.Equal(t, &testEntity{id: "1", name: "mine+theirs"}, items.Get("1"))
+	assert.Equal(t, "Theirs", name.Change().Old)
+}
+
+func TestWithResolver_Unresolved(t *testing.T) {
+	name := delta.New("Paulo")
+	tracker := delta.NewTracker(delta.WithResolver("name", func(c delta.Conflict) (any, error) {
+		return nil, delta.ErrConflict
+	}))
+	tracker.Register("name", name)
+
+	name.Set("Mine")
+	require.ErrorIs(t, tracker.Rebase(map[string]any{"name": "Theirs"}), delta.ErrConflict)
+	assert.Equal(t, "Paulo", name.Change().Old)
+}
@@ -0,0 +1,76 @@
+package delta
+
+import (
+	"errors"
+	"time"
+)
+
+// Backoff returns how long to wait before the given retry attempt, starting at 1.
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff waits the same duration before every retry.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff doubles the wait before every retry, starting at base and up to max.
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		return min(d, max)
+	}
+}
+
+type retryPolicy struct {
+	attempts int
+	backoff  Backoff
+}
+
+// WithRetry retries a failed load up to attempts times in total, waiting according to backoff between attempts.
+// ErrNotFound is not retried. Streaming loads are not retried, since items may have been already consumed.
+func WithRetry(attempts int, backoff Backoff) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optRetry
+		o.retry = retryPolicy{attempts: attempts, backoff: backoff}
+	})
+}
+
+// retried wraps a loader so that it is retried according to the policy.
+func retried[A, R any](p retryPolicy, fn func(A) (R, error)) func(A) (R, error) {
+	if fn == nil || p.attempts <= 1 {
+		return fn
+	}
+	return func(a A) (R, error) {
+		var (
+			r   R
+			err error
+		)
+		for attempt := 1; ; attempt++ {
+			r, err = fn(a)
+			if err == nil || errors.Is(err, ErrNotFound) || attempt == p.attempts {
+				return r, err
+			}
+			if p.backoff != nil {
+				time.Sleep(p.backoff(attempt))
+			}
+		}
+	}
+}
+
+// retried0 is like retried, for loaders without arguments.
+func retried0[R any](p retryPolicy, fn func() (R, error)) func() (R, error) {
+	if fn == nil || p.attempts <= 1 {
+		return fn
+	}
+	r := retried(p, func(struct{}) (R, error) {
+		return fn()
+	})
+	return func() (R, error) {
+		return r(struct{}{})
+	}
+}
@@ -0,0 +1,55 @@
+package delta_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetry(t *testing.T) {
+	calls := 0
+	scalar := delta.NewLazy(func() (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errors.New("blip")
+		}
+		return "loaded", nil
+	}, delta.WithRetry(3, delta.ConstantBackoff(0)))
+
+	value, err := scalar.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "loaded", value)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	lazySlice := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		calls++
+		if id == "" {
+			return nil, errors.New("blip")
+		}
+		return nil, delta.ErrNotFound
+	}, delta.WithRetry(2, nil))
+
+	_, err = lazySlice.GetAll()
+	require.EqualError(t, err, "blip")
+	assert.Equal(t, 2, calls)
+
+	// not found is not retried
+	calls = 0
+	_, err = lazySlice.Get("1")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+	assert.Equal(t, 1, calls)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := delta.ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, backoff(1))
+	assert.Equal(t, 20*time.Millisecond, backoff(2))
+	assert.Equal(t, 40*time.Millisecond, backoff(3))
+	assert.Equal(t, 50*time.Millisecond, backoff(4))
+	assert.Equal(t, 50*time.Millisecond, backoff(100))
+}
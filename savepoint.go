@@ -0,0 +1,38 @@
+package delta
+
+import "errors"
+
+var ErrUnknownSavepoint = errors.New("savepoint of another tracker")
+
+// Savepoint is the state of the fields of a tracker at a point in time. See Tracker.Savepoint.
+type Savepoint struct {
+	tracker *Tracker
+	states  []scopeEntry
+}
+
+// Savepoint records the state of the registered fields, including their pending changes,
+// so that the edits made afterwards can be undone with RollbackTo, keeping the earlier ones.
+func (t *Tracker) Savepoint() Savepoint {
+	sp := Savepoint{tracker: t}
+	for field := range t.fields.Values() {
+		sp.states = append(sp.states, scopeEntry{field: field, snapshot: field.snapshot()})
+	}
+	return sp
+}
+
+// RollbackTo restores the fields to the state recorded by sp, dropping the edits made since.
+// Fields registered after sp was taken are left as they are.
+// A savepoint can be rolled back to more than once.
+// It returns ErrUnknownSavepoint if sp was taken by another tracker.
+func (t *Tracker) RollbackTo(sp Savepoint) error {
+	if sp.tracker != t {
+		return ErrUnknownSavepoint
+	}
+	if t.frozen {
+		return ErrFrozen
+	}
+	for _, s := range sp.states {
+		s.field.restoreSnapshot(s.snapshot)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_RollbackTo(t *testing.T) {
+	price := delta.New(100)
+	items := delta.NewSlice([]*testEntity{{id: "1", name: "entity1"}})
+	tracker := delta.NewTracker()
+	tracker.Register("price", price)
+	tracker.Register("items", items)
+
+	items.Set(&testEntity{id: "2"})
+	sp := tracker.Savepoint()
+	price.Set(90)
+	items.Remove("1")
+	require.NoError(t, tracker.RollbackTo(sp))
+
+	assert.Equal(t, 100, price.Get())
+	assert.False(t, price.IsDirty())
+	assert.Equal(t, []string{"2"}, changedIDs(items.Changes()))
+
+	// can be rolled back to again
+	price.Set(80)
+	require.NoError(t, tracker.RollbackTo(sp))
+	assert.Equal(t, 100, price.Get())
+
+	assert.ErrorIs(t, delta.NewTracker().RollbackTo(sp), delta.ErrUnknownSavepoint)
+}
@@ -0,0 +1,74 @@
+package delta
+
+import "errors"
+
+var ErrScopeDone = errors.New("scope already committed or rolled back")
+
+// Scope groups the mutations of a multi-step operation over the fields of several trackers,
+// so that they are kept together with Commit or undone together with Rollback (eg: when a later step fails).
+// Mutations are applied to the fields as they are made; Rollback restores the state the fields had when enrolled.
+type Scope struct {
+	enrolled []scopeEntry
+	done     bool
+}
+
+type scopeEntry struct {
+	field    Field
+	snapshot any
+}
+
+// Begin starts a scope enrolling the fields registered in trackers.
+func Begin(trackers ...*Tracker) *Scope {
+	s := &Scope{}
+	for _, t := range trackers {
+		for field := range t.fields.Values() {
+			s.Enroll(field)
+		}
+	}
+	return s
+}
+
+// Enroll adds fields to the scope, recording their current state.
+// Fields already enrolled keep the state recorded when they were first enrolled.
+func (s *Scope) Enroll(fields ...Field) {
+	for _, field := range fields {
+		field = field.base()
+		if s.enrolls(field) {
+			continue
+		}
+		s.enrolled = append(s.enrolled, scopeEntry{field: field, snapshot: field.snapshot()})
+	}
+}
+
+func (s *Scope) enrolls(field Field) bool {
+	for _, e := range s.enrolled {
+		if e.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Commit keeps the mutations made in the scope, as pending changes of the fields.
+// It returns ErrScopeDone if the scope was already committed or rolled back.
+func (s *Scope) Commit() error {
+	if s.done {
+		return ErrScopeDone
+	}
+	s.done = true
+	s.enrolled = nil
+	return nil
+}
+
+// Rollback restores the enrolled fields to the state they had when enrolled, dropping the mutations made in the scope.
+// It does nothing if the scope was already committed or rolled back, so that it can be deferred.
+func (s *Scope) Rollback() {
+	if s.done {
+		return
+	}
+	s.done = true
+	for _, e := range s.enrolled {
+		e.field.restoreSnapshot(e.snapshot)
+	}
+	s.enrolled = nil
+}
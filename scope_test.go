@@ -0,0 +1,41 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope_Rollback(t *testing.T) {
+	name := delta.New("Paulo")
+	name.Set("Quintans") // pending before the scope
+	items := delta.NewSlice([]*testEntity{{id: "1", name: "entity1"}})
+	person := delta.NewTracker()
+	person.Register("name", name)
+	orders := delta.NewTracker()
+	orders.Register("items", items)
+
+	scope := delta.Begin(person, orders)
+	name.Set("Other")
+	items.Remove("1")
+	scope.Rollback()
+
+	assert.Equal(t, "Quintans", name.Get())
+	assert.True(t, name.IsDirty())
+	assert.False(t, items.IsDirty())
+	assert.ErrorIs(t, scope.Commit(), delta.ErrScopeDone)
+}
+
+func TestScope_Commit(t *testing.T) {
+	name := delta.New("Paulo")
+	scope := delta.Begin()
+	scope.Enroll(name)
+	name.Set("Quintans")
+	require.NoError(t, scope.Commit())
+	scope.Rollback() // no effect after commit
+
+	assert.Equal(t, "Quintans", name.Get())
+	assert.True(t, name.IsDirty())
+}
@@ -0,0 +1,82 @@
+package delta_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentGet_LoadsOnce(t *testing.T) {
+	var scalarLoads, sliceLoads atomic.Int32
+	scalar := delta.NewLazy(func() (string, error) {
+		scalarLoads.Add(1)
+		return "loaded", nil
+	})
+	lazySlice := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		sliceLoads.Add(1)
+		return []*testEntity{{id: "1", name: "entity1"}}, nil
+	})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			value, err := scalar.Get()
+			assert.NoError(t, err)
+			assert.Equal(t, "loaded", value)
+			_, err = lazySlice.GetAll()
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), scalarLoads.Load())
+	assert.Equal(t, int32(1), sliceLoads.Load())
+	require.True(t, lazySlice.IsLoaded())
+}
+
+func TestConcurrentLoads_LoadOnce(t *testing.T) {
+	var manyLoads, existsLoads, queryLoads atomic.Int32
+	lazySlice := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		return nil, nil
+	},
+		delta.WithManyLoader(func(ids []string) ([]*testEntity, error) {
+			manyLoads.Add(1)
+			return []*testEntity{{id: "1", name: "entity1"}, {id: "2", name: "entity2"}}, nil
+		}),
+		delta.WithExistsLoader(func(id string) (bool, error) {
+			existsLoads.Add(1)
+			return true, nil
+		}),
+		delta.WithQueryLoader(func(q delta.Query[string]) ([]*testEntity, error) {
+			queryLoads.Add(1)
+			return []*testEntity{{id: "4", name: "entity4"}}, nil
+		}),
+	)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			values, err := lazySlice.GetMany("1", "2")
+			assert.NoError(t, err)
+			assert.Len(t, values, 2)
+		})
+		wg.Go(func() {
+			exists, err := lazySlice.Exists("3")
+			assert.NoError(t, err)
+			assert.True(t, exists)
+		})
+		wg.Go(func() {
+			_, err := lazySlice.GetWhere(delta.Filter[*testEntity]{Name: "recent"})
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), manyLoads.Load())
+	assert.Equal(t, int32(1), existsLoads.Load())
+	assert.Equal(t, int32(1), queryLoads.Load())
+}
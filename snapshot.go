@@ -0,0 +1,79 @@
+package delta
+
+import (
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// ScalarSnapshot is the state of a scalar, including its change state, to be restored with Restore.
+type ScalarSnapshot[T any] struct {
+	isSet    bool
+	value    T
+	isDirty  bool
+	original T
+	hasOrig  bool
+	meta     ChangeMeta
+}
+
+// Snapshot returns the current state of the scalar.
+func (v *LazyScalar[T]) Snapshot() ScalarSnapshot[T] {
+	return ScalarSnapshot[T]{
+		isSet:    v.isSet,
+		value:    v.value,
+		isDirty:  v.isDirty,
+		original: v.original,
+		hasOrig:  v.hasOrig,
+		meta:     v.meta,
+	}
+}
+
+// Restore restores the state of the scalar from a snapshot.
+func (v *LazyScalar[T]) Restore(s ScalarSnapshot[T]) {
+	v.isSet, v.value, v.isDirty = s.isSet, s.value, s.isDirty
+	v.original, v.hasOrig = s.original, s.hasOrig
+	v.meta = s.meta
+	v.updated()
+}
+
+// SliceSnapshot is the state of a collection, including the statuses of its items and the reset flag,
+// to be restored with Restore.
+type SliceSnapshot[T Identifiable[I], I comparable] struct {
+	isSet       bool
+	isReset     bool
+	fetched     *linkedmap.Map[I, Item[T, I]]
+	beforeReset *linkedmap.Map[I, Item[T, I]]
+	wasSet      bool
+}
+
+// Snapshot returns the current state of the collection.
+// The items themselves are not copied, so changes made in place to them are not reverted by Restore.
+func (s *LazySlice[T, I]) Snapshot() SliceSnapshot[T, I] {
+	snap := SliceSnapshot[T, I]{
+		isSet:   s.isSet,
+		isReset: s.isReset,
+		fetched: copyMap(s.fetched),
+		wasSet:  s.wasSet,
+	}
+	if s.beforeReset != nil {
+		snap.beforeReset = copyMap(s.beforeReset)
+	}
+	return snap
+}
+
+// Restore restores the state of the collection from a snapshot. The snapshot can be restored more than once.
+func (s *LazySlice[T, I]) Restore(snap SliceSnapshot[T, I]) {
+	s.isSet = snap.isSet
+	s.isReset = snap.isReset
+	s.wasSet = snap.wasSet
+	s.beforeReset = nil
+	if snap.beforeReset != nil {
+		s.beforeReset = copyMap(snap.beforeReset)
+	}
+	fetched := linkedmap.New[I, Item[T, I]]()
+	if snap.fetched != nil {
+		fetched = copyMap(snap.fetched)
+	}
+	// the cached queries may refer to items that are no longer there
+	s.queries = nil
+	s.existing = nil
+	s.replaceFetched(fetched)
+}
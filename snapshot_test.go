@@ -0,0 +1,46 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyScalar_Snapshot(t *testing.T) {
+	scalar := delta.New("John")
+	scalar.Set("Jane")
+	snap := scalar.Snapshot()
+
+	scalar.Set("Joe")
+	scalar.AcceptChanges()
+	scalar.Restore(snap)
+	assert.Equal(t, "Jane", scalar.Get())
+	assert.Equal(t, &delta.Change[string]{Value: "Jane", Old: "John", HasOld: true}, scalar.Change())
+}
+
+func TestLazySlice_Snapshot(t *testing.T) {
+	lazySlice := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	lazySlice.Set(&testEntity{id: "1", name: "entity1_new"})
+	snap := lazySlice.Snapshot()
+	changes := slices.Collect(lazySlice.Changes().Items)
+
+	lazySlice.Remove("1")
+	lazySlice.SetAll([]*testEntity{{id: "3", name: "entity3"}})
+	require.True(t, lazySlice.IsReset())
+
+	for range 2 {
+		lazySlice.Restore(snap)
+		assert.False(t, lazySlice.IsReset())
+		assert.Equal(t, changes, slices.Collect(lazySlice.Changes().Items))
+		all, err := lazySlice.GetAll()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "2"}, ids(slices.Collect(all)))
+		lazySlice.Clear()
+	}
+}
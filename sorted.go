@@ -0,0 +1,73 @@
+package delta
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+)
+
+// ChangeOrder is the order in which the changes of a LazySlice are iterated.
+type ChangeOrder int
+
+const (
+	// InsertionOrder iterates the changes in the order the items were cached. It is the default.
+	InsertionOrder ChangeOrder = iota
+	// ByID iterates the changes sorted by ID.
+	ByID
+	// ByStatusThenID iterates the changes sorted by status (added, removed, modified) and then by ID.
+	ByStatusThenID
+)
+
+// WithChangeOrder sets the order in which the changes of a LazySlice are iterated, eg: for reproducible statements or golden files.
+// IDs of an ordered kind (eg: integers, strings) are compared by value and any other IDs by their fmt.Sprint representation.
+// Other than InsertionOrder, each iteration of the changes collects them in a slice to sort them.
+func WithChangeOrder(order ChangeOrder) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optChangeOrder
+		o.order = order
+	})
+}
+
+// sortedChanges returns the changes in the given order.
+// They are collected and sorted when iterated, not when returned, so that an unused iteration costs nothing.
+func sortedChanges[T any, I comparable](items iter.Seq[SliceChange[I, T]], order ChangeOrder) iter.Seq[SliceChange[I, T]] {
+	if order == InsertionOrder {
+		return items
+	}
+	return func(yield func(SliceChange[I, T]) bool) {
+		changes := slices.Collect(items)
+		switch order {
+		case ByID:
+			slices.SortStableFunc(changes, func(a, b SliceChange[I, T]) int {
+				return compareIDs(a.ID, b.ID)
+			})
+		case ByStatusThenID:
+			slices.SortStableFunc(changes, func(a, b SliceChange[I, T]) int {
+				return cmp.Or(cmp.Compare(a.Status, b.Status), compareIDs(a.ID, b.ID))
+			})
+		}
+		for _, c := range changes {
+			if !yield(c) {
+				return
+			}
+		}
+	}
+}
+
+func compareIDs[I comparable](a, b I) int {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(va.Int(), vb.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(va.Uint(), vb.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(va.Float(), vb.Float())
+	case reflect.String:
+		return cmp.Compare(va.String(), vb.String())
+	default:
+		return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
@@ -0,0 +1,57 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+)
+
+type numbered int
+
+func (n numbered) ID() int {
+	return int(n)
+}
+
+func changedIDs[T delta.Identifiable[I], I comparable](c delta.Changes[T, I]) []I {
+	var ids []I
+	for change := range c.Items {
+		ids = append(ids, change.ID)
+	}
+	return ids
+}
+
+func TestWithChangeOrder(t *testing.T) {
+	build := func(options ...delta.Option) *delta.Slice[numbered, int] {
+		s := delta.NewSlice([]numbered{1, 2, 3, 4}, options...)
+		s.Set(10)
+		s.Remove(3)
+		s.Set(5)
+		s.Remove(1)
+		s.Set(2)
+		return s
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 10, 5}, changedIDs(build().Changes()))
+	assert.Equal(t, []int{1, 2, 3, 5, 10}, changedIDs(build(delta.WithChangeOrder(delta.ByID)).Changes()))
+	assert.Equal(t, []int{5, 10, 1, 3, 2}, changedIDs(build(delta.WithChangeOrder(delta.ByStatusThenID)).Changes()))
+}
+
+func TestWithChangeOrder_StringIDs(t *testing.T) {
+	s := delta.NewSlice([]*testEntity{}, delta.WithChangeOrder(delta.ByID))
+	for _, id := range []string{"b", "c", "a"} {
+		s.Set(&testEntity{id: id})
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, changedIDs(s.Changes()))
+}
+
+func TestWithChangeOrder_SortsWhenIterated(t *testing.T) {
+	s := delta.NewSlice([]numbered{1, 2}, delta.WithChangeOrder(delta.ByID))
+	s.Set(3)
+	changes := s.Changes()
+	s.Remove(1)
+
+	// like the insertion order, the changes are read when iterated
+	assert.Equal(t, []int{1, 3}, changedIDs(changes))
+	assert.Equal(t, []int{1, 3}, changedIDs(changes))
+}
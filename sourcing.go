@@ -0,0 +1,45 @@
+package delta
+
+import (
+	"fmt"
+)
+
+// Event is a pending operation of an aggregate, to be stored in an event store.
+type Event struct {
+	Version uint64 `json:"version"` // version of the aggregate after the event
+	PatchOp
+}
+
+// Kind returns the kind of the event, eg: "cars.set".
+func (e Event) Kind() string {
+	return e.Path + "." + string(e.Op)
+}
+
+// Events returns the pending changes as events (see Pending), numbered after version.
+func (t *Tracker) Events(version uint64) []Event {
+	pending := t.Pending()
+	events := make([]Event, 0, len(pending))
+	for _, op := range pending {
+		version++
+		events = append(events, Event{Version: version, PatchOp: op})
+	}
+	return events
+}
+
+// Replay applies the events, in order, to the fields registered in the tracker, as ApplyPatch,
+// and accepts the changes, since they were already persisted.
+// It returns the version of the last event, failing if the versions are not increasing.
+func Replay(tracker *Tracker, events []Event) (uint64, error) {
+	var version uint64
+	for i, e := range events {
+		if e.Version <= version {
+			return 0, fmt.Errorf("%w: event %d has version %d, after version %d", ErrInvalidValue, i, e.Version, version)
+		}
+		version = e.Version
+		if err := ApplyPatch(tracker, Patch{e.PatchOp}); err != nil {
+			return 0, fmt.Errorf("event %d: %w", i, err)
+		}
+	}
+	tracker.AcceptAll()
+	return version, nil
+}
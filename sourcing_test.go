@@ -0,0 +1,48 @@
+package delta_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSourcing(t *testing.T) {
+	name := delta.New("John")
+	cars := delta.NewSlice([]*jsonEntity{{Key: "1", Name: "car1"}})
+	tracker := delta.NewTracker()
+	tracker.Register("name", name)
+	tracker.Register("cars", cars)
+	name.Set("Jane")
+	cars.Remove("1")
+	cars.Set(&jsonEntity{Key: "2", Name: "car2"})
+
+	events := tracker.Events(3)
+	require.Len(t, events, 3)
+	assert.Equal(t, []string{"name.set", "cars.remove", "cars.set"}, []string{events[0].Kind(), events[1].Kind(), events[2].Kind()})
+	assert.Equal(t, uint64(6), events[2].Version)
+
+	// stored as JSON and replayed onto another instance
+	data, err := json.Marshal(events)
+	require.NoError(t, err)
+	var stored []delta.Event
+	require.NoError(t, json.Unmarshal(data, &stored))
+
+	replayedName := delta.New("John")
+	replayedCars := delta.NewSlice([]*jsonEntity{{Key: "1", Name: "car1"}})
+	replayed := delta.NewTracker()
+	replayed.Register("name", replayedName)
+	replayed.Register("cars", replayedCars)
+	version, err := delta.Replay(replayed, stored)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), version)
+	assert.False(t, replayed.IsDirty())
+	assert.Equal(t, "Jane", replayedName.Get())
+	assert.Equal(t, []*jsonEntity{{Key: "2", Name: "car2"}}, slices.Collect(replayedCars.GetAll()))
+
+	_, err = delta.Replay(replayed, []delta.Event{stored[1], stored[0]})
+	require.ErrorIs(t, err, delta.ErrInvalidValue)
+}
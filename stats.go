@@ -0,0 +1,337 @@
+package delta
+
+import (
+	"cmp"
+	"maps"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sizer can be implemented by values to report their size in bytes, used by the load statistics.
+type Sizer interface {
+	Size() int
+}
+
+// LatencyBounds are the upper bounds of the load latency histogram buckets.
+var LatencyBounds = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// LatencyHistogram is a snapshot of the load latencies.
+type LatencyHistogram struct {
+	Bounds []time.Duration // upper bound of each bucket
+	Counts []int64         // count per bucket. The extra last bucket has no upper bound.
+	Count  int64
+	Sum    time.Duration
+}
+
+// FieldStats is a snapshot of the load statistics of a field of an aggregate type.
+type FieldStats struct {
+	AggregateType string
+	Field         string
+	Loads         int64 // number of successful loader calls
+	Errors        int64 // number of failed loader calls
+	Hits          int64 // reads served from the cache
+	Misses        int64 // reads that required a load
+	Bytes         int64 // bytes loaded, for values that are []byte, string or Sizer
+	Cached        int64 // items currently cached, across all live instances
+	Pending       int64 // pending changes, across all live instances
+	LoadLatency   LatencyHistogram
+}
+
+type statsKey struct {
+	aggregateType string
+	field         string
+}
+
+// statCounters are the load statistics shared by the fields registered under the same aggregate type and name.
+type statCounters struct {
+	loads   atomic.Int64
+	errors  atomic.Int64
+	hits    atomic.Int64
+	misses  atomic.Int64
+	bytes   atomic.Int64
+	cached  atomic.Int64
+	pending atomic.Int64
+	latency []atomic.Int64
+	sum     atomic.Int64
+}
+
+func newStatCounters() *statCounters {
+	return &statCounters{
+		latency: make([]atomic.Int64, len(LatencyBounds)+1),
+	}
+}
+
+func (c *statCounters) loaded(bytes int, elapsed time.Duration) {
+	c.loads.Add(1)
+	c.bytes.Add(int64(bytes))
+	i, _ := slices.BinarySearch(LatencyBounds, elapsed)
+	c.latency[i].Add(1)
+	c.sum.Add(int64(elapsed))
+}
+
+func (c *statCounters) reset() {
+	c.loads.Store(0)
+	c.errors.Store(0)
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.bytes.Store(0)
+	for i := range c.latency {
+		c.latency[i].Store(0)
+	}
+	c.sum.Store(0)
+}
+
+// fieldGauge keeps the contribution of a single field instance to the cached and pending gauges,
+// so that it can be withdrawn when the field is garbage collected.
+type fieldGauge struct {
+	counters *fieldCounters
+	cached   atomic.Int64
+	pending  atomic.Int64
+}
+
+func (g *fieldGauge) add(cached, pending int64) {
+	if g == nil || g.counters.stats == nil {
+		return
+	}
+	if cached != 0 {
+		g.cached.Add(cached)
+		g.counters.stats.cached.Add(cached)
+	}
+	if pending != 0 {
+		g.pending.Add(pending)
+		g.counters.stats.pending.Add(pending)
+	}
+}
+
+func (g *fieldGauge) set(cached, pending int64) {
+	if g == nil {
+		return
+	}
+	g.add(cached-g.cached.Load(), pending-g.pending.Load())
+}
+
+func (g *fieldGauge) release() {
+	g.set(0, 0)
+}
+
+func (g *fieldGauge) addStatus(s Status, sign int64) {
+	if g == nil {
+		return
+	}
+	var cached, pending int64
+	switch s {
+	case Unchanged:
+		cached = sign
+	case Added, Modified:
+		cached, pending = sign, sign
+	case Removed:
+		pending = sign
+	}
+	g.add(cached, pending)
+}
+
+var stats = struct {
+	mu       sync.Mutex
+	counters map[statsKey]*statCounters
+	trackers map[string]*atomic.Int64
+}{
+	counters: map[statsKey]*statCounters{},
+	trackers: map[string]*atomic.Int64{},
+}
+
+func countersFor(aggregateType, field string) *statCounters {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	key := statsKey{aggregateType: aggregateType, field: field}
+	c, ok := stats.counters[key]
+	if !ok {
+		c = newStatCounters()
+		stats.counters[key] = c
+	}
+	return c
+}
+
+// trackerCreated counts a live tracker of an aggregate type, until it is garbage collected.
+func trackerCreated(t *Tracker) {
+	stats.mu.Lock()
+	c, ok := stats.trackers[t.aggType]
+	if !ok {
+		c = &atomic.Int64{}
+		stats.trackers[t.aggType] = c
+	}
+	stats.mu.Unlock()
+
+	c.Add(1)
+	runtime.AddCleanup(t, func(c *atomic.Int64) {
+		c.Add(-1)
+	}, c)
+}
+
+// ActiveTrackers returns the number of live trackers per aggregate type.
+func ActiveTrackers() map[string]int64 {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	result := make(map[string]int64, len(stats.trackers))
+	for k, c := range stats.trackers {
+		result[k] = c.Load()
+	}
+	return result
+}
+
+// AggregateTypes returns the sorted aggregate types with statistics.
+func AggregateTypes() []string {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	return slices.Sorted(maps.Keys(stats.trackers))
+}
+
+// Stats returns the load statistics of all the fields registered in trackers with an aggregate type,
+// sorted by aggregate type and field.
+func Stats() []FieldStats {
+	stats.mu.Lock()
+	result := make([]FieldStats, 0, len(stats.counters))
+	for k, c := range stats.counters {
+		latency := LatencyHistogram{
+			Bounds: LatencyBounds,
+			Counts: make([]int64, len(c.latency)),
+			Sum:    time.Duration(c.sum.Load()),
+		}
+		for i := range c.latency {
+			latency.Counts[i] = c.latency[i].Load()
+			latency.Count += latency.Counts[i]
+		}
+		result = append(result, FieldStats{
+			AggregateType: k.aggregateType,
+			Field:         k.field,
+			Loads:         c.loads.Load(),
+			Errors:        c.errors.Load(),
+			Hits:          c.hits.Load(),
+			Misses:        c.misses.Load(),
+			Bytes:         c.bytes.Load(),
+			Cached:        c.cached.Load(),
+			Pending:       c.pending.Load(),
+			LoadLatency:   latency,
+		})
+	}
+	stats.mu.Unlock()
+
+	slices.SortFunc(result, func(a, b FieldStats) int {
+		return cmp.Or(cmp.Compare(a.AggregateType, b.AggregateType), cmp.Compare(a.Field, b.Field))
+	})
+	return result
+}
+
+// StatsFor returns the load statistics of the fields of an aggregate type.
+func StatsFor(aggregateType string) []FieldStats {
+	return slices.DeleteFunc(Stats(), func(s FieldStats) bool {
+		return s.AggregateType != aggregateType
+	})
+}
+
+// HitRatio returns the ratio of reads served from the cache.
+func (s FieldStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// ResetStats clears the load statistics counters.
+// Gauges, like active trackers, cached items and pending changes, are kept.
+func ResetStats() {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	for _, c := range stats.counters {
+		c.reset()
+	}
+}
+
+func (v *LazyScalar[T]) instrument(c *fieldCounters) {
+	v.counters = c
+	if v.gauge != nil {
+		// withdraw the contribution of a previous registration
+		v.gauge.release()
+	}
+	v.gauge = &fieldGauge{counters: c}
+	v.syncGauge()
+	runtime.AddCleanup(v, (*fieldGauge).release, v.gauge)
+}
+
+// syncGauge updates the gauge with the current state of the scalar.
+func (v *LazyScalar[T]) syncGauge() {
+	if v.gauge == nil {
+		return
+	}
+	var cached, pending int64
+	if v.isSet {
+		cached = 1
+	}
+	if v.isDirty {
+		pending = 1
+	}
+	v.gauge.set(cached, pending)
+}
+
+func (s *LazySlice[T, I]) instrument(c *fieldCounters) {
+	s.counters = c
+	if s.gauge != nil {
+		// withdraw the contribution of a previous registration
+		s.gauge.release()
+	}
+	s.gauge = &fieldGauge{counters: c}
+	s.recount()
+	runtime.AddCleanup(s, (*fieldGauge).release, s.gauge)
+}
+
+// recount recomputes the gauge from scratch.
+func (s *LazySlice[T, I]) recount() {
+	if s.gauge == nil {
+		return
+	}
+	s.gauge.release()
+	for v := range s.fetched.Values() {
+		s.gauge.addStatus(v.status, 1)
+	}
+}
+
+func sizeOf(value any) int {
+	switch v := value.(type) {
+	case Sizer:
+		return v.Size()
+	case []byte:
+		return len(v)
+	case string:
+		return len(v)
+	default:
+		return 0
+	}
+}
+
+func sizeOfAll[T any](values []T) int {
+	size := 0
+	for _, v := range values {
+		size += sizeOf(v)
+	}
+	return size
+}
@@ -0,0 +1,89 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	delta.ResetStats()
+
+	photo := delta.NewLazy(func() ([]byte, error) {
+		return []byte("photo"), nil
+	})
+	items := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	tracker := delta.NewTracker(delta.WithAggregateType("stats-person"))
+	tracker.Register("photo", photo)
+	tracker.Register("items", items)
+
+	for range 3 {
+		_, err := photo.Get()
+		require.NoError(t, err)
+	}
+	_, err := items.Get("1")
+	require.NoError(t, err)
+	_, err = items.Get("1")
+	require.NoError(t, err)
+	_, err = items.GetAll()
+	require.NoError(t, err)
+
+	stats := delta.StatsFor("stats-person")
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, "items", stats[0].Field)
+	assert.Equal(t, int64(2), stats[0].Loads)
+	assert.Equal(t, int64(1), stats[0].Hits)
+	assert.Equal(t, int64(2), stats[0].Misses)
+	assert.Equal(t, 1.0/3, stats[0].HitRatio())
+
+	assert.Equal(t, "photo", stats[1].Field)
+	assert.Equal(t, int64(1), stats[1].Loads)
+	assert.Equal(t, int64(2), stats[1].Hits)
+	assert.Equal(t, int64(1), stats[1].Misses)
+	assert.Equal(t, int64(5), stats[1].Bytes)
+
+	delta.ResetStats()
+	stats = delta.StatsFor("stats-person")
+	require.Len(t, stats, 2)
+	assert.Zero(t, stats[1].Loads)
+}
+
+func TestStats_Gauges(t *testing.T) {
+	items := delta.NewLazySlice(fetcher([]*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}))
+	name := delta.New("john")
+	tracker := delta.NewTracker(delta.WithAggregateType("gauges-person"))
+	tracker.Register("items", items)
+	tracker.Register("name", name)
+
+	assert.GreaterOrEqual(t, delta.ActiveTrackers()["gauges-person"], int64(1))
+
+	_, err := items.GetAll()
+	require.NoError(t, err)
+	items.Set(&testEntity{id: "3", name: "entity3"})
+	items.Remove("1")
+	name.Set("jane")
+
+	stats := delta.StatsFor("gauges-person")
+	require.Len(t, stats, 2)
+	assert.Equal(t, "items", stats[0].Field)
+	assert.Equal(t, int64(2), stats[0].Cached)
+	assert.Equal(t, int64(2), stats[0].Pending)
+	assert.Equal(t, int64(1), stats[0].LoadLatency.Count)
+	assert.Equal(t, "name", stats[1].Field)
+	assert.Equal(t, int64(1), stats[1].Cached)
+	assert.Equal(t, int64(1), stats[1].Pending)
+
+	items.Clear()
+	stats = delta.StatsFor("gauges-person")
+	assert.Equal(t, int64(0), stats[0].Cached)
+	assert.Equal(t, int64(0), stats[0].Pending)
+}
@@ -0,0 +1,92 @@
+package delta
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// WithStreamLoader sets the loader used to load all the items of a LazySlice,
+// streaming them into the cache instead of collecting them first in a slice.
+// It panics when used with a collection of a different type.
+func WithStreamLoader[T any](fn func() iter.Seq2[T, error]) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optStreamLoader
+		o.stream = fn
+	})
+}
+
+func streamFor[T any](opts options) func() iter.Seq2[T, error] {
+	if opts.stream == nil {
+		return nil
+	}
+	stream, ok := opts.stream.(func() iter.Seq2[T, error])
+	if !ok {
+		panic(fmt.Sprintf("delta: WithStreamLoader loader %T used with a collection of %s", opts.stream, reflect.TypeFor[T]()))
+	}
+	return stream
+}
+
+func (s *LazySlice[T, I]) loadStream() (int, error) {
+	size := 0
+	for v, err := range s.stream() {
+		if err != nil {
+			return 0, err
+		}
+		s.merge(v)
+		size += sizeOf(v)
+	}
+	return size, nil
+}
+
+// Stream iterates over all the items without caching them, for read-only access to large collections.
+// If all the items are loaded they are iterated from the cache, otherwise they are streamed from the stream loader
+// (see WithStreamLoader), with the pending changes applied: pending additions and modifications come first.
+// Without a stream loader, the items are loaded and cached, as in GetAll.
+func (s *LazySlice[T, I]) Stream() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		s.expire()
+		if s.isSet || s.stream == nil {
+			values, err := s.GetAll()
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for v := range values {
+				if !yield(v, nil) {
+					return
+				}
+			}
+			return
+		}
+
+		for _, item := range s.fetched.Entries() {
+			if item.status != Added && item.status != Modified {
+				continue
+			}
+			if !yield(item.value, nil) {
+				return
+			}
+		}
+		s.counters.miss()
+		for v, err := range s.stream() {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if item, ok := s.fetched.Get(v.ID()); ok {
+				switch item.status {
+				case Added, Modified, Removed:
+					continue
+				case Unchanged:
+					v = item.value
+				}
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
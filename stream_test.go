@@ -0,0 +1,70 @@
+package delta_test
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func streamer(ents []*testEntity, failAt int) func() iter.Seq2[*testEntity, error] {
+	return func() iter.Seq2[*testEntity, error] {
+		return func(yield func(*testEntity, error) bool) {
+			for i, e := range ents {
+				if i == failAt {
+					yield(nil, errors.New("boom"))
+					return
+				}
+				if !yield(e, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestLazySlice_GetAll_StreamLoader(t *testing.T) {
+	ents := []*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+	}
+	lazySlice := delta.NewLazySlice(fetcher(nil), delta.WithStreamLoader(streamer(ents, -1)))
+	lazySlice.Set(&testEntity{id: "2", name: "entity2_new"})
+
+	values, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, []*testEntity{{id: "2", name: "entity2_new"}, ents[0]}, slices.Collect(values))
+	assert.True(t, lazySlice.IsLoaded())
+
+	failing := delta.NewLazySlice(fetcher(nil), delta.WithStreamLoader(streamer(ents, 1)))
+	_, err = failing.GetAll()
+	require.EqualError(t, err, "boom")
+	assert.False(t, failing.IsLoaded())
+}
+
+func TestLazySlice_Stream(t *testing.T) {
+	ents := []*testEntity{
+		{id: "1", name: "entity1"},
+		{id: "2", name: "entity2"},
+		{id: "3", name: "entity3"},
+	}
+	lazySlice := delta.NewLazySlice(fetcher(nil), delta.WithStreamLoader(streamer(ents, -1)))
+	lazySlice.Set(&testEntity{id: "2", name: "entity2_new"})
+	lazySlice.Set(&testEntity{id: "4", name: "entity4"})
+	lazySlice.Remove("3")
+
+	var got []string
+	for v, err := range lazySlice.Stream() {
+		require.NoError(t, err)
+		got = append(got, v.name)
+	}
+	assert.Equal(t, []string{"entity2_new", "entity4", "entity1"}, got)
+	// nothing was cached
+	assert.False(t, lazySlice.IsLoaded())
+	_, err := lazySlice.Get("1")
+	require.ErrorIs(t, err, delta.ErrNotFound)
+}
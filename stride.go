@@ -0,0 +1,57 @@
+package delta
+
+import (
+	"iter"
+	"runtime"
+)
+
+// WithStride makes the iterators over the tracked items (eg: GetAll, Changes) yield the processor
+// every n visited items, so that iterating over huge collections does not hold the processor for long.
+// Iteration is done over the tracked items, without copying them, whether or not a stride is set.
+// Supported by LazySlice, LazyMap, LazySet and DynamicFields.
+func WithStride(n int) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optStride
+		o.stride = n
+	})
+}
+
+// strided calls runtime.Gosched() every stride visited values.
+func strided[V any](it iter.Seq[V], stride int) iter.Seq[V] {
+	if stride <= 0 {
+		return it
+	}
+	return func(yield func(V) bool) {
+		n := 0
+		for v := range it {
+			if !yield(v) {
+				return
+			}
+			n++
+			if n == stride {
+				n = 0
+				runtime.Gosched()
+			}
+		}
+	}
+}
+
+// strided2 calls runtime.Gosched() every stride visited pairs.
+func strided2[K, V any](it iter.Seq2[K, V], stride int) iter.Seq2[K, V] {
+	if stride <= 0 {
+		return it
+	}
+	return func(yield func(K, V) bool) {
+		n := 0
+		for k, v := range it {
+			if !yield(k, v) {
+				return
+			}
+			n++
+			if n == stride {
+				n = 0
+				runtime.Gosched()
+			}
+		}
+	}
+}
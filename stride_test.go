@@ -0,0 +1,77 @@
+package delta_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazySlice_WithStride(t *testing.T) {
+	const size = 10_000
+	entities := make([]*testEntity, size)
+	for i := range entities {
+		entities[i] = &testEntity{id: strconv.Itoa(i), name: "entity"}
+	}
+	lazySlice := delta.NewLazySlice(fetcher(entities), delta.WithStride(100))
+
+	all, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	count := 0
+	for range all {
+		count++
+	}
+	assert.Equal(t, size, count)
+
+	lazySlice.Set(&testEntity{id: "5000", name: "changed"})
+	lazySlice.Remove("9999")
+	var changes []string
+	for c := range lazySlice.Changes().Items {
+		changes = append(changes, c.ID)
+	}
+	assert.Equal(t, []string{"5000", "9999"}, changes)
+
+	// stops early
+	count = 0
+	for range all {
+		count++
+		if count == 150 {
+			break
+		}
+	}
+	assert.Equal(t, 150, count)
+
+	// iterating does not copy the items
+	allocs := testing.AllocsPerRun(10, func() {
+		all, _ := lazySlice.GetAll()
+		for range all {
+		}
+	})
+	assert.Less(t, allocs, float64(100))
+}
+
+func TestDynamicFields_WithStride(t *testing.T) {
+	values := map[string]any{}
+	for i := range 1000 {
+		values[strconv.Itoa(i)] = i
+	}
+	fields, err := delta.NewDynamicFieldsFrom(values, delta.WithStride(10))
+	require.NoError(t, err)
+
+	all, err := fields.GetAll()
+	require.NoError(t, err)
+	count := 0
+	for range all {
+		count++
+	}
+	assert.Equal(t, 1000, count)
+
+	require.NoError(t, fields.Set("500", -1))
+	var changes []string
+	for c := range fields.Changes() {
+		changes = append(changes, c.Key)
+	}
+	assert.Equal(t, []string{"500"}, changes)
+}
@@ -0,0 +1,231 @@
+package delta
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// StructPatch is a typed partial update of a struct T (eg: an update command exchanged between services):
+// the values to assign to some of its fields.
+// Fields are named as in DiffStructs, by the delta struct tag or by their Go name,
+// and the fields of embedded structs as if they were fields of the struct.
+// The zero value is an empty patch.
+type StructPatch[T any] struct {
+	values map[string]any
+}
+
+type patchField struct {
+	name  string
+	index []int
+	typ   reflect.Type
+}
+
+// patchFields returns the fields of T, in the order they are declared.
+func patchFields[T any]() ([]patchField, error) {
+	rt := reflect.TypeFor[T]()
+	if rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s is not a struct", ErrInvalidValue, rt)
+	}
+	var fields []patchField
+	if err := collectPatchFields(rt, nil, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func collectPatchFields(rt reflect.Type, index []int, fields *[]patchField) error {
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		t, err := parseTag(sf.Tag)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", sf.Name, err)
+		}
+		if t.skip {
+			continue
+		}
+		idx := append(index[:len(index):len(index)], i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if err := collectPatchFields(sf.Type, idx, fields); err != nil {
+				return err
+			}
+			continue
+		}
+		name := sf.Name
+		if t.name != "" {
+			name = t.name
+		}
+		*fields = append(*fields, patchField{name: name, index: idx, typ: sf.Type})
+	}
+	return nil
+}
+
+func lookupPatchField[T any](name string) (patchField, error) {
+	fields, err := patchFields[T]()
+	if err != nil {
+		return patchField{}, err
+	}
+	for _, f := range fields {
+		if f.name == name {
+			return f, nil
+		}
+	}
+	return patchField{}, fmt.Errorf("%w: %q", ErrUnknownField, name)
+}
+
+// Set assigns value to the field, converting it to the field type if needed (eg: a number decoded from JSON).
+// A nil value assigns the zero value.
+func (p *StructPatch[T]) Set(field string, value any) error {
+	f, err := lookupPatchField[T](field)
+	if err != nil {
+		return err
+	}
+	v := reflect.Zero(f.typ).Interface()
+	if value != nil {
+		if v, err = convertTo(value, f.typ); err != nil {
+			return fmt.Errorf("field %s: %w", field, err)
+		}
+	}
+	if p.values == nil {
+		p.values = map[string]any{}
+	}
+	p.values[field] = v
+	return nil
+}
+
+// Get returns the value assigned to the field, or false if the patch does not assign it.
+func (p StructPatch[T]) Get(field string) (any, bool) {
+	v, ok := p.values[field]
+	return v, ok
+}
+
+// Fields returns the names of the assigned fields, in the order they are declared.
+func (p StructPatch[T]) Fields() []string {
+	fields, _ := patchFields[T]()
+	var names []string
+	for _, f := range fields {
+		if _, ok := p.values[f.name]; ok {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// IsEmpty returns true if the patch does not assign any field.
+func (p StructPatch[T]) IsEmpty() bool {
+	return len(p.values) == 0
+}
+
+// Apply returns a copy of v with the assigned fields.
+func (p StructPatch[T]) Apply(v T) T {
+	if len(p.values) == 0 {
+		return v
+	}
+	rv := reflect.New(reflect.TypeFor[T]()).Elem()
+	rv.Set(reflect.ValueOf(&v).Elem())
+	fields, _ := patchFields[T]()
+	for _, f := range fields {
+		value, ok := p.values[f.name]
+		if !ok {
+			continue
+		}
+		// unexported fields cannot be set through reflection, so they are set through their address
+		fv := reflect.NewAt(f.typ, unsafe.Pointer(rv.FieldByIndex(f.index).UnsafeAddr())).Elem()
+		if value == nil {
+			fv.SetZero()
+		} else {
+			fv.Set(reflect.ValueOf(value))
+		}
+	}
+	return rv.Interface().(T)
+}
+
+// DiffPatch returns the patch that turns old into new, with the fields that changed, compared as in DiffStructs.
+func DiffPatch[T any](old, new T) (StructPatch[T], error) {
+	changes, err := DiffStructs(old, new)
+	if err != nil {
+		return StructPatch[T]{}, err
+	}
+	var p StructPatch[T]
+	for _, c := range changes {
+		if err := p.Set(c.Field, c.New); err != nil {
+			return StructPatch[T]{}, err
+		}
+	}
+	return p, nil
+}
+
+// TrackerPatch returns the patch with the pending changes of the scalar fields registered in the tracker
+// whose names are fields of T (eg: a PersonUpdate command with Name and Age).
+// The other changes are ignored, so that T can be a subset of the aggregate.
+func TrackerPatch[T any](tracker *Tracker) (StructPatch[T], error) {
+	fields, err := patchFields[T]()
+	if err != nil {
+		return StructPatch[T]{}, err
+	}
+	var p StructPatch[T]
+	for _, f := range fields {
+		field, ok := tracker.Field(f.name)
+		if !ok {
+			continue
+		}
+		for _, op := range field.operations() {
+			if op.Op != OpSet || op.ID != nil {
+				continue
+			}
+			if err := p.Set(f.name, op.Value); err != nil {
+				return StructPatch[T]{}, err
+			}
+		}
+	}
+	return p, nil
+}
+
+// MarshalJSON encodes the patch as an object with the assigned fields.
+func (p StructPatch[T]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range p.Fields() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.values[name])
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes an object with the fields to assign, decoding each value as its field type.
+func (p *StructPatch[T]) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = StructPatch[T]{}
+	for name, msg := range raw {
+		f, err := lookupPatchField[T](name)
+		if err != nil {
+			return err
+		}
+		ptr := reflect.New(f.typ)
+		if err := json.Unmarshal(msg, ptr.Interface()); err != nil {
+			return fmt.Errorf("field %s: %w", name, err)
+		}
+		if err := p.Set(name, ptr.Elem().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package delta_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type personUpdate struct {
+	Name    string
+	Age     int `delta:"age"`
+	Address address
+}
+
+func TestStructPatch(t *testing.T) {
+	var p delta.StructPatch[personUpdate]
+	assert.True(t, p.IsEmpty())
+	require.NoError(t, p.Set("age", 41.0)) // eg: decoded from JSON
+	require.ErrorIs(t, p.Set("Unknown", 1), delta.ErrUnknownField)
+
+	v := p.Apply(personUpdate{Name: "Paulo", Age: 40})
+	assert.Equal(t, personUpdate{Name: "Paulo", Age: 41}, v)
+	assert.Equal(t, []string{"age"}, p.Fields())
+
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"age":41}`, string(data))
+
+	var decoded delta.StructPatch[personUpdate]
+	require.NoError(t, json.Unmarshal([]byte(`{"Address":{"Street":"Main St","Zip":"1000"},"Name":"Quintans"}`), &decoded))
+	assert.Equal(t, []string{"Name", "Address"}, decoded.Fields())
+	assert.Equal(t, personUpdate{Name: "Quintans", Age: 40, Address: address{Street: "Main St", Zip: "1000"}},
+		decoded.Apply(personUpdate{Name: "Paulo", Age: 40}))
+
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"Unknown":1}`), &decoded), delta.ErrUnknownField)
+}
+
+func TestDiffPatch(t *testing.T) {
+	p, err := delta.DiffPatch(personUpdate{Name: "Paulo", Age: 40}, personUpdate{Name: "Paulo", Age: 41})
+	require.NoError(t, err)
+	value, ok := p.Get("age")
+	require.True(t, ok)
+	assert.Equal(t, 41, value)
+	_, ok = p.Get("Name")
+	assert.False(t, ok)
+}
+
+func TestTrackerPatch(t *testing.T) {
+	name := delta.New("Paulo")
+	age := delta.New(40)
+	nickname := delta.New("PQ")
+	tracker := delta.NewTracker()
+	tracker.Register("Name", name)
+	tracker.Register("age", age)
+	tracker.Register("nickname", nickname)
+
+	age.Set(41)
+	nickname.Set("Q")
+
+	p, err := delta.TrackerPatch[personUpdate](tracker)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"age"}, p.Fields())
+	assert.Equal(t, personUpdate{Name: "Paulo", Age: 41}, p.Apply(personUpdate{Name: "Paulo", Age: 40}))
+}
@@ -0,0 +1,68 @@
+package delta
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// tag is the parsed delta struct tag of a tracked field: `delta:"name,equal=comparator"`.
+// The name "-" skips the field.
+type tag struct {
+	name  string
+	skip  bool
+	equal string
+}
+
+func parseTag(st reflect.StructTag) (tag, error) {
+	value, ok := st.Lookup("delta")
+	if !ok {
+		return tag{}, nil
+	}
+	name, opts, _ := strings.Cut(value, ",")
+	if name == "-" {
+		return tag{skip: true}, nil
+	}
+	t := tag{name: name}
+	for opt := range strings.SplitSeq(opts, ",") {
+		switch k, v, _ := strings.Cut(opt, "="); k {
+		case "":
+		case "equal":
+			t.equal = v
+		default:
+			return tag{}, fmt.Errorf("%w: unknown delta tag option %q", ErrInvalidValue, opt)
+		}
+	}
+	return t, nil
+}
+
+var comparators sync.Map // name -> func(a, b any) bool
+
+// RegisterEqual registers a comparator by name, to be selected by the equal option of the delta struct tag
+// (eg: `delta:"photo,equal=samePhoto"`). Collect ignores the scalar changes to a value equal to the old one.
+func RegisterEqual[T any](name string, equal func(a, b T) bool) {
+	comparators.Store(name, func(a, b any) bool {
+		ta, okA := a.(T)
+		tb, okB := b.(T)
+		return okA && okB && equal(ta, tb)
+	})
+}
+
+func comparator(name string) (func(a, b any) bool, error) {
+	eq, ok := comparators.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown comparator %q", ErrInvalidValue, name)
+	}
+	return eq.(func(a, b any) bool), nil
+}
+
+// scalarChange is implemented by the changes that can be compared with a comparator selected by name.
+type scalarChange interface {
+	unchanged(equal func(a, b any) bool) bool
+}
+
+// unchanged returns true if the value was set to a value equal to the old one.
+func (c *Change[T]) unchanged(equal func(a, b any) bool) bool {
+	return c != nil && c.HasOld && equal(c.Value, c.Old)
+}
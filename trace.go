@@ -0,0 +1,52 @@
+package delta
+
+import (
+	"context"
+	"reflect"
+)
+
+// Tracer starts a span around a load (eg: with OpenTelemetry), used by the loaders of TracedLoader and TracedKeyLoader.
+type Tracer interface {
+	// Start starts the span named name (eg: "delta.load person.cars") of the load of field, as a child of the span in ctx,
+	// returning the context with the new span and the function that ends it with the number of items loaded and the error, if any.
+	Start(ctx context.Context, name, field string) (context.Context, func(items int, err error))
+}
+
+// TracedLoader decorates the loader of a LazyScalar or LazyRef so that every call is wrapped in a span
+// named "delta.load " followed by field, with one item loaded.
+// The span is started within ctx (eg: the request context) and fn is called with the context of the span,
+// so that the spans of the store are its children.
+func TracedLoader[T any](ctx context.Context, tracer Tracer, field string, fn func(context.Context) (T, error)) func() (T, error) {
+	return func() (T, error) {
+		return traced(ctx, tracer, field, fn, func(T) int { return 1 })
+	}
+}
+
+// TracedKeyLoader decorates the loader of a collection (eg: LazySlice, LazyMap), called with the key to load, as TracedLoader.
+// The items loaded are the length of the returned slice or map.
+func TracedKeyLoader[K comparable, R any](ctx context.Context, tracer Tracer, field string, fn func(context.Context, K) (R, error)) func(K) (R, error) {
+	return func(key K) (R, error) {
+		return traced(ctx, tracer, field, func(ctx context.Context) (R, error) { return fn(ctx, key) }, length)
+	}
+}
+
+func traced[T any](ctx context.Context, tracer Tracer, field string, fn func(context.Context) (T, error), count func(T) int) (T, error) {
+	ctx, end := tracer.Start(ctx, "delta.load "+field, field)
+	value, err := fn(ctx)
+	if err != nil {
+		end(0, err)
+		return value, err
+	}
+	end(count(value), nil)
+	return value, nil
+}
+
+func length[T any](value T) int {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len()
+	default:
+		return 0
+	}
+}
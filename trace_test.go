@@ -0,0 +1,73 @@
+package delta_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type spanKey struct{}
+
+type span struct {
+	name   string
+	field  string
+	parent string
+	items  int
+	err    error
+	ended  bool
+}
+
+type recordingTracer struct {
+	spans []*span
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name, field string) (context.Context, func(int, error)) {
+	parent, _ := ctx.Value(spanKey{}).(string)
+	s := &span{name: name, field: field, parent: parent}
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, spanKey{}, name), func(items int, err error) {
+		s.items, s.err, s.ended = items, err, true
+	}
+}
+
+func TestTracedLoader(t *testing.T) {
+	tracer := &recordingTracer{}
+	errLoad := errors.New("unavailable")
+	ctx := context.WithValue(context.Background(), spanKey{}, "request")
+
+	var loadSpan any
+	photo := delta.NewLazy(delta.TracedLoader(ctx, tracer, "person.photo", func(ctx context.Context) ([]byte, error) {
+		loadSpan = ctx.Value(spanKey{})
+		return []byte("photo"), nil
+	}))
+	_, err := photo.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "delta.load person.photo", loadSpan, "the loader runs within the span")
+
+	tags := delta.NewLazySet(delta.TracedKeyLoader(ctx, tracer, "person.tags", func(context.Context, string) ([]string, error) {
+		return nil, errLoad
+	}))
+	_, err = tags.GetAll()
+	require.ErrorIs(t, err, errLoad)
+
+	require.Len(t, tracer.spans, 2)
+	assert.Equal(t, span{name: "delta.load person.photo", field: "person.photo", parent: "request", items: 1, ended: true}, *tracer.spans[0])
+	assert.Equal(t, span{name: "delta.load person.tags", field: "person.tags", parent: "request", err: errLoad, ended: true}, *tracer.spans[1])
+}
+
+func TestTracedKeyLoader(t *testing.T) {
+	tracer := &recordingTracer{}
+	cars := delta.NewLazySlice(delta.TracedKeyLoader(context.Background(), tracer, "person.cars", func(context.Context, string) ([]*testEntity, error) {
+		return []*testEntity{{id: "1"}, {id: "2"}}, nil
+	}))
+
+	_, err := cars.GetAll()
+	require.NoError(t, err)
+
+	require.Len(t, tracer.spans, 1)
+	assert.Equal(t, span{name: "delta.load person.cars", field: "person.cars", items: 2, ended: true}, *tracer.spans[0])
+}
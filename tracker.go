@@ -0,0 +1,273 @@
+package delta
+
+import (
+	"iter"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/quintans/ds/collections/linkedmap"
+)
+
+// Field is implemented by the tracked types of this package so that they can be registered in a Tracker.
+type Field interface {
+	IsDirty() bool
+	AcceptChanges()
+	// changes returns the value of the Change or Changes method of the field.
+	changes() any
+	// observe sets the function called before each mutation of the field.
+	observe(before func())
+	snapshot() any
+	restoreSnapshot(s any)
+	patch(op PatchOp) error
+	// operations returns the operations that reproduce the pending changes on top of the fetched state.
+	operations() []PatchOp
+	// base returns the lazy field, so that eager wrappers (eg: Scalar) and their lazy field are the same field.
+	base() Field
+	instrument(c *fieldCounters)
+	setClock(clock Clock)
+	setLogger(logger *slog.Logger, field string)
+	setActor(fn func() (actor, reason string))
+	Evictable
+	// footprint returns the estimated size, in bytes, of the tracked data.
+	footprint() int
+	setBudget(b *memoryBudget)
+	// locker returns the mutex that serializes the loads of the field.
+	locker() *sync.Mutex
+	// load loads all the data of the field.
+	load() error
+	Freeze()
+}
+
+// Tracker keeps the named tracked fields of an aggregate.
+type Tracker struct {
+	fields     *linkedmap.Map[string, Field]
+	executed   []Command
+	logOps     bool
+	ops        Patch
+	opOf       []int // index in ops of the operation of each executed command, or -1 if not logged
+	aggType    string
+	aggID      any
+	clock      Clock
+	budget     *memoryBudget
+	journal    *journal
+	metrics    Metrics
+	logger     *slog.Logger
+	actor      func() (actor, reason string)
+	changeSets *changeSets
+	resolvers  map[string]Resolver
+	frozen     bool
+}
+
+// WithOperationLog records every mutation done through the tracker (commands and patches) as an operation.
+func WithOperationLog() TrackerOption {
+	return trackerOptionFunc(func(t *Tracker) {
+		t.logOps = true
+	})
+}
+
+// WithAggregateType sets the aggregate type, under which the load statistics of the registered fields are collected.
+// See Stats().
+func WithAggregateType(name string) TrackerOption {
+	return trackerOptionFunc(func(t *Tracker) {
+		t.aggType = name
+	})
+}
+
+func NewTracker(options ...TrackerOption) *Tracker {
+	t := &Tracker{
+		fields: linkedmap.New[string, Field](),
+	}
+	for _, opt := range options {
+		opt.applyTracker(t)
+	}
+	trackerCreated(t)
+	return t
+}
+
+// Register registers a tracked field under name, replacing any field previously registered with the same name.
+func (t *Tracker) Register(name string, field Field) {
+	old, replaced := t.fields.Put(name, field)
+	if t.clock != nil {
+		field.setClock(t.clock)
+	}
+	if t.aggType != "" || t.metrics != nil || t.logger != nil {
+		c := &fieldCounters{metrics: t.metrics, logger: t.logger, aggregateType: t.aggType, field: name}
+		if t.aggType != "" {
+			c.stats = countersFor(t.aggType, name)
+		}
+		field.instrument(c)
+	}
+	if t.budget != nil {
+		if replaced {
+			t.budget.remove(old)
+		}
+		field.setBudget(t.budget)
+		t.budget.add(field)
+	}
+	// also names the field in the errors of its mutations
+	field.setLogger(t.logger, name)
+	if t.actor != nil {
+		field.setActor(t.actor)
+	}
+	if t.frozen {
+		field.Freeze()
+	}
+	if t.journal != nil {
+		if replaced {
+			old.observe(nil)
+		}
+		field.observe(func() { t.journal.record(field) })
+	}
+}
+
+func (t *Tracker) Field(name string) (Field, bool) {
+	return t.fields.Get(name)
+}
+
+// Fields iterates over the registered fields in registration order.
+func (t *Tracker) Fields() iter.Seq2[string, Field] {
+	return t.fields.Entries()
+}
+
+// Execute executes the command and records it, so that it can be undone.
+func (t *Tracker) Execute(cmd Command) error {
+	if t.frozen {
+		return ErrFrozen
+	}
+	if err := cmd.Execute(); err != nil {
+		return err
+	}
+	t.executed = append(t.executed, cmd)
+	op := -1
+	if t.logOps {
+		if c, ok := cmd.(fieldCommand); ok {
+			op = len(t.ops)
+			t.ops = append(t.ops, c.operation(t.nameOf(c.field())))
+		}
+	}
+	t.opOf = append(t.opOf, op)
+	return nil
+}
+
+func (t *Tracker) nameOf(field Field) string {
+	for name, f := range t.fields.Entries() {
+		if f.base() == field.base() {
+			return name
+		}
+	}
+	return ""
+}
+
+// Undo reverts the last executed command or, with WithJournal, the last mutation.
+func (t *Tracker) Undo() error {
+	if t.frozen {
+		return ErrFrozen
+	}
+	if t.journal != nil {
+		return t.journal.undo()
+	}
+	if len(t.executed) == 0 {
+		return ErrNothingToUndo
+	}
+	last := len(t.executed) - 1
+	if err := t.executed[last].Undo(); err != nil {
+		return err
+	}
+	t.executed = t.executed[:last]
+	// the undone command is no longer part of the operation log
+	if op := t.opOf[last]; op >= 0 {
+		t.ops = slices.Delete(t.ops, op, op+1)
+	}
+	t.opOf = t.opOf[:last]
+	return nil
+}
+
+// Redo reapplies the last undone mutation. It requires WithJournal.
+// A new mutation forgets the undone ones.
+func (t *Tracker) Redo() error {
+	if t.frozen {
+		return ErrFrozen
+	}
+	if t.journal == nil {
+		return ErrNothingToRedo
+	}
+	return t.journal.redo()
+}
+
+// Commands returns the executed commands, oldest first.
+func (t *Tracker) Commands() []Command {
+	return slices.Clone(t.executed)
+}
+
+// Operations returns the operation log, oldest first.
+func (t *Tracker) Operations() Patch {
+	return slices.Clone(t.ops)
+}
+
+func (t *Tracker) logPatch(op PatchOp) {
+	if t.logOps {
+		t.ops = append(t.ops, op)
+	}
+}
+
+// Pending returns the minimal set of operations that, applied over the fetched state,
+// produce the current pending changes of the registered fields.
+func (t *Tracker) Pending() Patch {
+	var ops Patch
+	for name, field := range t.fields.Entries() {
+		for _, op := range field.operations() {
+			op.Path = name
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// IsDirty returns true if any of the registered fields has pending changes.
+func (t *Tracker) IsDirty() bool {
+	for _, field := range t.fields.Entries() {
+		if field.IsDirty() {
+			return true
+		}
+	}
+	return false
+}
+
+// Changes returns the changes of the registered fields with pending changes, by name.
+// The changes of each field are the value returned by its Change or Changes method (eg: *Change[T] or Changes[T, I]).
+func (t *Tracker) Changes() map[string]any {
+	changes := map[string]any{}
+	for name, field := range t.fields.Entries() {
+		if field.IsDirty() {
+			changes[name] = field.changes()
+		}
+	}
+	return changes
+}
+
+// AcceptAll marks the pending changes of all the registered fields as persisted (see AcceptChanges).
+// With WithLogger, the changes being accepted are logged, and with WithChangeHistory, they are kept in the History.
+// The operation log is emptied, since its operations were persisted.
+func (t *Tracker) AcceptAll() {
+	t.logChanges()
+	t.recordChangeSet()
+	for _, field := range t.fields.Entries() {
+		field.AcceptChanges()
+	}
+	t.resetOps(nil)
+}
+
+// Compact replaces the operation log with the pending operations (see Pending).
+func (t *Tracker) Compact() Patch {
+	t.resetOps(t.Pending())
+	return slices.Clone(t.ops)
+}
+
+// resetOps replaces the operation log, which no longer holds the operations of the executed commands.
+func (t *Tracker) resetOps(ops Patch) {
+	t.ops = ops
+	for i := range t.opOf {
+		t.opOf[i] = -1
+	}
+}
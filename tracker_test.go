@@ -0,0 +1,40 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_Changes(t *testing.T) {
+	name := delta.New("John")
+	photo := delta.NewLazy(func() ([]byte, error) { return []byte("photo"), nil })
+	cars := delta.NewSlice([]*testEntity{{id: "1", name: "car1"}})
+	tracker := delta.NewTracker()
+	tracker.Register("name", name)
+	tracker.Register("photo", photo)
+	tracker.Register("cars", cars)
+	assert.False(t, tracker.IsDirty())
+	assert.Empty(t, tracker.Changes())
+
+	name.Set("Jane")
+	cars.Set(&testEntity{id: "2", name: "car2"})
+	assert.True(t, tracker.IsDirty())
+	changes := tracker.Changes()
+	require.Len(t, changes, 2)
+	assert.Equal(t, &delta.Change[string]{Value: "Jane", Old: "John", HasOld: true}, changes["name"])
+	carChanges, ok := changes["cars"].(delta.Changes[*testEntity, string])
+	require.True(t, ok)
+	items := slices.Collect(carChanges.Items)
+	require.Len(t, items, 1)
+	assert.Equal(t, delta.Added, items[0].Status)
+
+	tracker.AcceptAll()
+	assert.False(t, tracker.IsDirty())
+	assert.Empty(t, tracker.Changes())
+	assert.Equal(t, "Jane", name.Get())
+	assert.Equal(t, []string{"1", "2"}, ids(slices.Collect(cars.GetAll())))
+}
@@ -0,0 +1,79 @@
+package delta
+
+import (
+	"time"
+)
+
+// WithTTL makes loaded values expire after ttl, so that they are transparently loaded again on the next access.
+// Pending changes are kept.
+// For collections, all the loaded items expire together, when the oldest one expires.
+// A LazyRef or LazyList with a pending change does not expire, since the change replaces the whole loaded value.
+func WithTTL(ttl time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.applied |= optTTL
+		o.ttl = ttl
+	})
+}
+
+func (v *LazyScalar[T]) expire() {
+	if v.ttl > 0 && !v.fetchedAt.IsZero() && v.now().Sub(v.fetchedAt) >= v.ttl {
+		v.invalidate()
+	}
+}
+
+func (s *LazySlice[T, I]) expire() {
+	if s.ttl > 0 && !s.loadedAt.IsZero() && s.now().Sub(s.loadedAt) >= s.ttl {
+		s.invalidate()
+	}
+}
+
+func (m *LazyMap[K, V]) expire() {
+	if m.ttl > 0 && !m.loadedAt.IsZero() && m.now().Sub(m.loadedAt) >= m.ttl {
+		m.Evict()
+		m.loadedAt = time.Time{}
+	}
+}
+
+func (m *LazyMap[K, V]) markLoaded(at time.Time) {
+	if m.loadedAt.IsZero() {
+		m.loadedAt = at
+	}
+}
+
+func (s *LazySet[T]) expire() {
+	if s.ttl > 0 && !s.loadedAt.IsZero() && s.now().Sub(s.loadedAt) >= s.ttl {
+		s.Evict()
+		s.loadedAt = time.Time{}
+	}
+}
+
+func (s *LazySet[T]) markLoaded(at time.Time) {
+	if s.loadedAt.IsZero() {
+		s.loadedAt = at
+	}
+}
+
+func (d *DynamicFields) expire() {
+	if d.ttl > 0 && !d.loadedAt.IsZero() && d.now().Sub(d.loadedAt) >= d.ttl {
+		d.Evict()
+		d.loadedAt = time.Time{}
+	}
+}
+
+func (d *DynamicFields) markLoaded(at time.Time) {
+	if d.loadedAt.IsZero() {
+		d.loadedAt = at
+	}
+}
+
+func (r *LazyRef[T, I]) expire() {
+	if r.ttl > 0 && !r.fetchedAt.IsZero() && r.now().Sub(r.fetchedAt) >= r.ttl {
+		r.Evict()
+	}
+}
+
+func (l *LazyList[T, I]) expire() {
+	if l.ttl > 0 && !l.fetchedAt.IsZero() && l.now().Sub(l.fetchedAt) >= l.ttl {
+		l.Evict()
+	}
+}
@@ -0,0 +1,159 @@
+package delta_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyScalar_WithTTL(t *testing.T) {
+	clock := newFakeClock()
+	callCount := 0
+	scalar := delta.NewLazy(func() (int, error) {
+		callCount++
+		return callCount, nil
+	}, delta.WithClock(clock), delta.WithTTL(time.Minute))
+
+	assert.Equal(t, 1, scalar.MustGet())
+	clock.Advance(59 * time.Second)
+	assert.Equal(t, 1, scalar.MustGet())
+	clock.Advance(time.Second)
+	assert.Equal(t, 2, scalar.MustGet())
+
+	// pending changes do not expire
+	scalar.Set(10)
+	clock.Advance(time.Hour)
+	assert.Equal(t, 10, scalar.MustGet())
+	assert.Equal(t, 2, callCount)
+}
+
+func TestLazySlice_WithTTL(t *testing.T) {
+	clock := newFakeClock()
+	callCount := 0
+	lazySlice := delta.NewLazySlice(func(id string) ([]*testEntity, error) {
+		callCount++
+		return fetcher([]*testEntity{
+			{id: "1", name: "entity1"},
+			{id: "2", name: "entity2"},
+		})(id)
+	}, delta.WithClock(clock), delta.WithTTL(time.Minute))
+
+	_, err := lazySlice.Get("1")
+	require.NoError(t, err)
+	clock.Advance(30 * time.Second)
+	_, err = lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, 2, callCount)
+
+	// expires with the oldest loaded item
+	clock.Advance(30 * time.Second)
+	lazySlice.Set(&testEntity{id: "3", name: "entity3"})
+	all, err := lazySlice.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, 3, callCount)
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, ids(slices.Collect(all)))
+}
+
+func TestLazyMap_WithTTL(t *testing.T) {
+	clock := newFakeClock()
+	callCount := 0
+	settings := delta.NewLazyMap(func(key string) (map[string]int, error) {
+		callCount++
+		return map[string]int{"a": callCount, "b": callCount}, nil
+	}, delta.WithClock(clock), delta.WithTTL(time.Minute))
+
+	value, err := settings.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+	settings.Put("b", 10)
+	clock.Advance(time.Minute)
+
+	// the pending change is kept
+	all, err := settings.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 2, "b": 10}, maps.Collect(all))
+	assert.Equal(t, 2, callCount)
+}
+
+func TestLazySet_WithTTL(t *testing.T) {
+	clock := newFakeClock()
+	callCount := 0
+	tags := delta.NewLazySet(func(member string) ([]string, error) {
+		callCount++
+		return []string{"a"}, nil
+	}, delta.WithClock(clock), delta.WithTTL(time.Minute))
+
+	ok, err := tags.Contains("a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	clock.Advance(time.Minute)
+	ok, err = tags.Contains("a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, callCount)
+}
+
+func TestDynamicFields_WithTTL(t *testing.T) {
+	clock := newFakeClock()
+	callCount := 0
+	attributes := delta.NewDynamicFields(func(key string) (map[string]any, error) {
+		callCount++
+		return map[string]any{"color": callCount}, nil
+	}, delta.WithClock(clock), delta.WithTTL(time.Minute))
+
+	_, err := attributes.GetAll()
+	require.NoError(t, err)
+	clock.Advance(59 * time.Second)
+	value, err := attributes.Get("color")
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+	clock.Advance(time.Second)
+	value, err = attributes.Get("color")
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+}
+
+func TestLazyRef_WithTTL(t *testing.T) {
+	clock := newFakeClock()
+	callCount := 0
+	owner := delta.NewLazyRef(func() (*testEntity, error) {
+		callCount++
+		return &testEntity{id: "1", name: "entity1"}, nil
+	}, delta.WithClock(clock), delta.WithTTL(time.Minute))
+
+	_, err := owner.Get()
+	require.NoError(t, err)
+	clock.Advance(time.Minute)
+	_, err = owner.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 2, callCount)
+
+	// a pending change does not expire
+	owner.Set(&testEntity{id: "2", name: "entity2"})
+	clock.Advance(time.Hour)
+	entity, err := owner.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "2", entity.ID())
+	assert.Equal(t, 2, callCount)
+}
+
+func TestLazyList_WithTTL(t *testing.T) {
+	clock := newFakeClock()
+	callCount := 0
+	steps := delta.NewLazyList(func() ([]*testEntity, error) {
+		callCount++
+		return []*testEntity{{id: "1", name: "entity1"}}, nil
+	}, delta.WithClock(clock), delta.WithTTL(time.Minute))
+
+	_, err := steps.Len()
+	require.NoError(t, err)
+	clock.Advance(time.Minute)
+	_, err = steps.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 2, callCount)
+}
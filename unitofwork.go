@@ -0,0 +1,54 @@
+package delta
+
+import "slices"
+
+// Acceptable can be implemented by the aggregates that can mark their changes as persisted,
+// usually by calling AcceptChanges on their tracked fields.
+type Acceptable interface {
+	AcceptChanges()
+}
+
+// UnitOfWork keeps the aggregate roots loaded in a business transaction,
+// so that the ones with changes can be saved together.
+type UnitOfWork struct {
+	roots []Dirtyable
+}
+
+func NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{}
+}
+
+// Register adds the aggregate roots to the unit of work. A root already registered is ignored.
+func (u *UnitOfWork) Register(roots ...Dirtyable) {
+	for _, r := range roots {
+		if !slices.Contains(u.roots, r) {
+			u.roots = append(u.roots, r)
+		}
+	}
+}
+
+// Dirty returns the registered roots that have changes, in the order they were registered.
+func (u *UnitOfWork) Dirty() []Dirtyable {
+	var dirty []Dirtyable
+	for _, r := range u.roots {
+		if r.HasChanges() {
+			dirty = append(dirty, r)
+		}
+	}
+	return dirty
+}
+
+// Commit calls save for each root with changes, stopping at the first error.
+// The changes of a saved root are accepted if it implements Acceptable, so that a failed commit can be retried
+// without saving it again.
+func (u *UnitOfWork) Commit(save func(root Dirtyable) error) error {
+	for _, r := range u.Dirty() {
+		if err := save(r); err != nil {
+			return err
+		}
+		if a, ok := r.(Acceptable); ok {
+			a.AcceptChanges()
+		}
+	}
+	return nil
+}
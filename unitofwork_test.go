@@ -0,0 +1,58 @@
+package delta_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type uowAggregate struct {
+	name *delta.Scalar[string]
+}
+
+func newUowAggregate(name string) *uowAggregate {
+	return &uowAggregate{name: delta.New(name)}
+}
+
+func (a *uowAggregate) HasChanges() bool {
+	return a.name.Change() != nil
+}
+
+func (a *uowAggregate) AcceptChanges() {
+	a.name.AcceptChanges()
+}
+
+func TestUnitOfWork(t *testing.T) {
+	a1 := newUowAggregate("a1")
+	a2 := newUowAggregate("a2")
+	a3 := newUowAggregate("a3")
+	uow := delta.NewUnitOfWork()
+	uow.Register(a1, a2, a3, a1)
+
+	a1.name.Set("a1_new")
+	a3.name.Set("a3_new")
+	assert.Equal(t, []delta.Dirtyable{a1, a3}, uow.Dirty())
+
+	errSave := errors.New("save failed")
+	var saved []delta.Dirtyable
+	err := uow.Commit(func(root delta.Dirtyable) error {
+		if root == a3 && len(saved) == 1 {
+			return errSave
+		}
+		saved = append(saved, root)
+		return nil
+	})
+	require.ErrorIs(t, err, errSave)
+	assert.Equal(t, []delta.Dirtyable{a3}, uow.Dirty())
+
+	// retrying only saves what is left
+	require.NoError(t, uow.Commit(func(root delta.Dirtyable) error {
+		saved = append(saved, root)
+		return nil
+	}))
+	assert.Equal(t, []delta.Dirtyable{a1, a3}, saved)
+	assert.Empty(t, uow.Dirty())
+}
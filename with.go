@@ -0,0 +1,46 @@
+package delta
+
+// With returns a copy of the scalar carrying the change of setting value, as Set, leaving the receiver unchanged.
+// The copy shares the values with the receiver (see Clone).
+func (v *LazyScalar[T]) With(value T) *LazyScalar[T] {
+	c := v.Clone(nil)
+	c.Set(value)
+	return c
+}
+
+// With returns a copy of the scalar carrying the change of setting value, as LazyScalar.With.
+func (e *Scalar[T]) With(value T) *Scalar[T] {
+	c := e.Clone(nil)
+	c.Set(value)
+	return c
+}
+
+// With returns a copy of the collection carrying the change of setting value, as Set, leaving the receiver unchanged.
+// The copy shares the items with the receiver (see Clone).
+func (s *LazySlice[T, I]) With(value T) *LazySlice[T, I] {
+	c := s.Clone(nil)
+	c.Set(value)
+	return c
+}
+
+// Without returns a copy of the collection carrying the removal of the item with id, as Remove,
+// leaving the receiver unchanged.
+func (s *LazySlice[T, I]) Without(id I) *LazySlice[T, I] {
+	c := s.Clone(nil)
+	c.Remove(id)
+	return c
+}
+
+// With returns a copy of the collection carrying the change of setting value, as LazySlice.With.
+func (e *Slice[T, I]) With(value T) *Slice[T, I] {
+	c := e.Clone(nil)
+	c.Set(value)
+	return c
+}
+
+// Without returns a copy of the collection carrying the removal of the item with id, as LazySlice.Without.
+func (e *Slice[T, I]) Without(id I) *Slice[T, I] {
+	c := e.Clone(nil)
+	c.Remove(id)
+	return c
+}
@@ -0,0 +1,31 @@
+package delta_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/quintans/delta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScalar_With(t *testing.T) {
+	name := delta.New("Paulo")
+	changed := name.With("Quintans")
+
+	assert.Equal(t, "Paulo", name.Get())
+	assert.False(t, name.IsDirty())
+	assert.Equal(t, "Quintans", changed.Get())
+	assert.Equal(t, &delta.Change[string]{Value: "Quintans", Old: "Paulo", HasOld: true}, changed.Change())
+}
+
+func TestSlice_WithWithout(t *testing.T) {
+	items := delta.NewSlice([]*testEntity{{id: "1", name: "entity1"}})
+	added := items.With(&testEntity{id: "2", name: "entity2"})
+	removed := added.Without("1")
+
+	assert.Len(t, slices.Collect(items.GetAll()), 1)
+	assert.False(t, items.IsDirty())
+	assert.Equal(t, []string{"2"}, changedIDs(added.Changes()))
+	assert.ElementsMatch(t, []string{"1", "2"}, changedIDs(removed.Changes()))
+	assert.Equal(t, []*testEntity{{id: "2", name: "entity2"}}, slices.Collect(removed.GetAll()))
+}